@@ -2,6 +2,7 @@ package disgord
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -59,14 +60,99 @@ func (a *Attachment) DeepCopy() (copy interface{}) {
 	return
 }
 
+// Permission overwrite types
+// https://discordapp.com/developers/docs/resources/channel#overwrite-object
+const (
+	PermissionOverwriteRole   = "role"
+	PermissionOverwriteMember = "member"
+)
+
 // PermissionOverwrite https://discordapp.com/developers/docs/resources/channel#overwrite-object
 type PermissionOverwrite struct {
 	ID    Snowflake      `json:"id"`    // role or user id
-	Type  string         `json:"type"`  // either `role` or `member`
+	Type  string         `json:"type"`  // either `role` or `member`, see PermissionOverwriteRole and PermissionOverwriteMember
 	Allow PermissionBits `json:"allow"` // permission bit set
 	Deny  PermissionBits `json:"deny"`  // permission bit set
 }
 
+// UnmarshalJSON see interface json.Unmarshaler
+func (pmo *PermissionOverwrite) UnmarshalJSON(data []byte) error {
+	type permissionOverwriteJSON PermissionOverwrite
+	j := struct {
+		*permissionOverwriteJSON
+		Type  overwriteTypeJSON  `json:"type"`
+		Allow permissionBitsJSON `json:"allow"`
+		Deny  permissionBitsJSON `json:"deny"`
+	}{
+		permissionOverwriteJSON: (*permissionOverwriteJSON)(pmo),
+		Type:                    overwriteTypeJSON(pmo.Type),
+		Allow:                   permissionBitsJSON(pmo.Allow),
+		Deny:                    permissionBitsJSON(pmo.Deny),
+	}
+	if err := unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	pmo.Type = string(j.Type)
+	pmo.Allow = PermissionBits(j.Allow)
+	pmo.Deny = PermissionBits(j.Deny)
+	return nil
+}
+
+// overwriteTypeJSON decodes the overwrite type from both strings and the integers Discord uses from v8
+// (0 for a role and 1 for a member), and encodes it as an integer.
+type overwriteTypeJSON string
+
+var _ json.Marshaler = (*overwriteTypeJSON)(nil)
+var _ json.Unmarshaler = (*overwriteTypeJSON)(nil)
+
+func (t overwriteTypeJSON) MarshalJSON() ([]byte, error) {
+	if t == PermissionOverwriteMember {
+		return []byte("1"), nil
+	}
+	return []byte("0"), nil
+}
+
+func (t *overwriteTypeJSON) UnmarshalJSON(data []byte) error {
+	switch str := string(data); str {
+	case "null":
+	case "0":
+		*t = PermissionOverwriteRole
+	case "1":
+		*t = PermissionOverwriteMember
+	default:
+		if len(str) < 2 || str[0] != '"' || str[len(str)-1] != '"' {
+			return errors.New("unsupported permission overwrite type " + str)
+		}
+		*t = overwriteTypeJSON(str[1 : len(str)-1])
+	}
+	return nil
+}
+
+// permissionOverwritesParam returns the overwrites in the format expected by the given Discord API version.
+func permissionOverwritesParam(apiVersion int, overwrites []PermissionOverwrite) interface{} {
+	if apiVersion < 8 || overwrites == nil {
+		return overwrites
+	}
+
+	type permissionOverwriteJSON struct {
+		ID    Snowflake          `json:"id"`
+		Type  overwriteTypeJSON  `json:"type"`
+		Allow permissionBitsJSON `json:"allow"`
+		Deny  permissionBitsJSON `json:"deny"`
+	}
+	params := make([]permissionOverwriteJSON, len(overwrites))
+	for i := range overwrites {
+		params[i] = permissionOverwriteJSON{
+			ID:    overwrites[i].ID,
+			Type:  overwriteTypeJSON(overwrites[i].Type),
+			Allow: permissionBitsJSON(overwrites[i].Allow),
+			Deny:  permissionBitsJSON(overwrites[i].Deny),
+		}
+	}
+	return params
+}
+
 // NewChannel ...
 func NewChannel() *Channel {
 	return &Channel{}
@@ -348,7 +434,7 @@ func (c *Client) GetChannel(ctx context.Context, channelID Snowflake, flags ...F
 //  Reviewed                2018-06-07
 //  Comment                 andersfylling: only implemented the patch method, as its parameters are optional.
 func (c *Client) UpdateChannel(ctx context.Context, channelID Snowflake, flags ...Flag) (builder *updateChannelBuilder) {
-	builder = &updateChannelBuilder{}
+	builder = &updateChannelBuilder{apiVersion: c.req.APIVersion()}
 	builder.r.itemFactory = func() interface{} {
 		return c.pool.channel.Get()
 	}
@@ -404,7 +490,7 @@ func (c *Client) DeleteChannel(ctx context.Context, channelID Snowflake, flags .
 type UpdateChannelPermissionsParams struct {
 	Allow PermissionBits `json:"allow"` // the bitwise value of all allowed permissions
 	Deny  PermissionBits `json:"deny"`  // the bitwise value of all disallowed permissions
-	Type  string         `json:"type"`  // "member" for a user or "role" for a role
+	Type  string         `json:"type"`  // "member" for a user or "role" for a role, sent as an integer from v8
 }
 
 // EditChannelPermissions [REST] Edit the channel permission overwrites for a user or role in a channel. Only usable
@@ -423,12 +509,22 @@ func (c *Client) UpdateChannelPermissions(ctx context.Context, channelID, overwr
 		return errors.New("overwriteID must be set to target the specific channel permissions")
 	}
 
+	var body interface{} = params
+	if c.req.APIVersion() >= 8 {
+		body = &struct {
+			*UpdateChannelPermissionsParams
+			Allow permissionBitsJSON `json:"allow"`
+			Deny  permissionBitsJSON `json:"deny"`
+			Type  overwriteTypeJSON  `json:"type"`
+		}{params, permissionBitsJSON(params.Allow), permissionBitsJSON(params.Deny), overwriteTypeJSON(params.Type)}
+	}
+
 	r := c.newRESTRequest(&httd.Request{
 		Method:      httd.MethodPut,
 		Ctx:         ctx,
 		Endpoint:    endpoint.ChannelPermission(channelID, overwriteID),
 		ContentType: httd.ContentTypeJSON,
-		Body:        params,
+		Body:        body,
 	}, flags)
 	r.expectsStatusCode = http.StatusNoContent
 	r.updateCache = func(registry cacheRegistry, id Snowflake, x interface{}) (err error) {
@@ -624,22 +720,25 @@ func (c *Client) KickParticipant(ctx context.Context, channelID, userID Snowflak
 //////////////////////////////////////////////////////
 
 // updateChannelBuilder https://discordapp.com/developers/docs/resources/channel#modify-channel-json-params
-//generate-rest-params: parent_id:Snowflake, permission_overwrites:[]PermissionOverwrite, user_limit:uint, bitrate:uint, rate_limit_per_user:uint, nsfw:bool, topic:string, position:int, name:string,
+//generate-rest-params: parent_id:Snowflake, user_limit:uint, bitrate:uint, rate_limit_per_user:uint, nsfw:bool, topic:string, position:int, name:string,
 //generate-rest-basic-execute: channel:*Channel,
 type updateChannelBuilder struct {
-	r RESTBuilder
+	r          RESTBuilder
+	apiVersion int
+	overwrites []PermissionOverwrite
 }
 
-func (b *updateChannelBuilder) AddPermissionOverwrite(permission PermissionOverwrite) *updateChannelBuilder {
-	if _, exists := b.r.body["permission_overwrites"]; !exists {
-		b.SetPermissionOverwrites([]PermissionOverwrite{permission})
-	} else {
-		s := b.r.body["permission_overwrites"].([]PermissionOverwrite)
-		s = append(s, permission)
-		b.SetPermissionOverwrites(s)
-	}
+// SetPermissionOverwrites sets the permission overwrites of the channel. The overwrite types are sent as
+// integers and the permissions as strings from v8.
+func (b *updateChannelBuilder) SetPermissionOverwrites(permissionOverwrites []PermissionOverwrite) *updateChannelBuilder {
+	b.overwrites = permissionOverwrites
+	b.r.param("permission_overwrites", permissionOverwritesParam(b.apiVersion, permissionOverwrites))
 	return b
 }
+
+func (b *updateChannelBuilder) AddPermissionOverwrite(permission PermissionOverwrite) *updateChannelBuilder {
+	return b.SetPermissionOverwrites(append(b.overwrites, permission))
+}
 func (b *updateChannelBuilder) AddPermissionOverwrites(permissions []PermissionOverwrite) *updateChannelBuilder {
 	for i := range permissions {
 		b.AddPermissionOverwrite(permissions[i])
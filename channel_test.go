@@ -1,7 +1,11 @@
 package disgord
 
 import (
+	"context"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/andersfylling/disgord/internal/util"
@@ -88,3 +92,86 @@ func TestChannel_JSONIconNull(t *testing.T) {
 		t.Error(c.Icon, "was not empty")
 	}
 }
+
+func TestPermissionOverwrite_UnmarshalJSON(t *testing.T) {
+	// v6 sends the overwrite type as a string and the permissions as numbers, v8 uses integers and strings
+	fixtures := []string{
+		`{"id":"486833611564253184","permission_overwrites":[{"id":"3","type":"member","allow":1024,"deny":0},{"id":"4","type":"role","allow":0,"deny":2048}]}`,
+		`{"id":"486833611564253184","permission_overwrites":[{"id":"3","type":1,"allow":"1024","deny":"0"},{"id":"4","type":0,"allow":"0","deny":"2048"}]}`,
+	}
+	for _, data := range fixtures {
+		channel := Channel{}
+		if err := util.Unmarshal([]byte(data), &channel); err != nil {
+			t.Fatal(err)
+		}
+		expects := []PermissionOverwrite{
+			{ID: 3, Type: PermissionOverwriteMember, Allow: PermissionReadMessages},
+			{ID: 4, Type: PermissionOverwriteRole, Deny: PermissionSendMessages},
+		}
+		if len(channel.PermissionOverwrites) != len(expects) {
+			t.Fatalf("expected %d overwrites from %s. Got %+v", len(expects), data, channel.PermissionOverwrites)
+		}
+		for i := range expects {
+			if channel.PermissionOverwrites[i] != expects[i] {
+				t.Errorf("expected overwrite %+v from %s. Got %+v", expects[i], data, channel.PermissionOverwrites[i])
+			}
+		}
+	}
+
+	overwrite := PermissionOverwrite{}
+	if err := util.Unmarshal([]byte(`{"id":"3","type":2}`), &overwrite); err == nil {
+		t.Error("expected an error for an unknown overwrite type")
+	}
+}
+
+func TestPermissionOverwrite_APIVersion(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"486833611564253184","type":0}`))
+	}))
+	defer srv.Close()
+
+	table := []struct {
+		version   int
+		params    string
+		overwrite string
+	}{
+		{6, `"allow":1024,"deny":2048,"type":"member"`, `"type":"member","allow":1024,"deny":2048`},
+		{8, `"allow":"1024","deny":"2048","type":1`, `"type":1,"allow":"1024","deny":"2048"`},
+	}
+	for _, test := range table {
+		version := test.version
+		c, err := NewClient(Config{BotToken: "test", RESTBaseURL: srv.URL, APIVersion: version})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = c.UpdateChannelPermissions(context.Background(), 486833611564253184, 3, &UpdateChannelPermissionsParams{
+			Allow: PermissionReadMessages,
+			Deny:  PermissionSendMessages,
+			Type:  PermissionOverwriteMember,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(body), test.params) {
+			t.Errorf("v%d: expected body to contain %s. Got %s", version, test.params, string(body))
+		}
+
+		_, err = c.UpdateChannel(context.Background(), 486833611564253184).
+			AddPermissionOverwrite(PermissionOverwrite{ID: 3, Type: PermissionOverwriteMember, Allow: PermissionReadMessages, Deny: PermissionSendMessages}).
+			Execute()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(body), test.overwrite) {
+			t.Errorf("v%d: expected update body to contain %s. Got %s", version, test.overwrite, string(body))
+		}
+	}
+}
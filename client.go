@@ -39,6 +39,9 @@ func NewClient(conf Config) (*Client, error) {
 
 // NewClient creates a new DisGord Client and returns an error on configuration issues
 func createClient(conf *Config) (c *Client, err error) {
+	if conf.BotToken == "" && !conf.webhookOnly {
+		return nil, errors.New("no Discord Bot Token was provided")
+	}
 	if conf.HTTPClient == nil {
 		// WARNING: do not set http.Client.Timeout (!)
 		conf.HTTPClient = &http.Client{}
@@ -53,8 +56,12 @@ func createClient(conf *Config) (c *Client, err error) {
 			},
 		}
 	}
+	if conf.APIVersion == 0 {
+		conf.APIVersion = constant.DiscordVersion
+	}
 	httdClient, err := httd.NewClient(&httd.Config{
-		APIVersion:                   constant.DiscordVersion,
+		APIVersion:                   conf.APIVersion,
+		BaseURL:                      conf.RESTBaseURL,
 		BotToken:                     conf.BotToken,
 		UserAgentSourceURL:           constant.GitHubURL,
 		UserAgentVersion:             constant.Version,
@@ -104,10 +111,19 @@ func createClient(conf *Config) (c *Client, err error) {
 	}
 
 	// websocket sharding
-	evtChan := make(chan *gateway.Event, 2) // TODO: higher value when more shards?
+	// the dispatch queue holds the backlog, see DispatchConfig
+	evtChan := make(chan *gateway.Event, 2)
 
 	// event dispatcher
 	dispatch := newDispatcher()
+	dispatch.executor = newDispatchExecutor(conf.DispatchConfig, dispatch.dispatch)
+	dispatch.handlerTimeout = conf.DispatchConfig.HandlerTimeout
+	dispatch.eventDeadline = conf.DispatchConfig.EventDeadline
+	dispatch.sink = conf.EventSink
+	dispatch.dedup = newDeduplicator(conf.DispatchConfig.Deduplication)
+	dispatch.ctx, dispatch.cancel = context.WithCancel(context.Background())
+	dispatch.onHandlerPanic = conf.DispatchConfig.OnHandlerPanic
+	dispatch.onHandlerError = conf.DispatchConfig.OnHandlerError
 
 	// create a disgord Client/instance/session
 	c = &Client{
@@ -158,6 +174,9 @@ type Config struct {
 	// for cancellation
 	shutdownChan chan interface{}
 
+	// webhookOnly allows a client without a bot token, see WebhookClient
+	webhookOnly bool
+
 	// your project name, name of bot, or application
 	ProjectName string
 
@@ -176,10 +195,36 @@ type Config struct {
 	// ################################################
 	RESTBucketManager httd.RESTBucketManager
 
+	// RESTBaseURL overrides the root of the Discord REST API, without the version suffix.
+	// eg. "http://localhost:8080/api". Defaults to the Discord url of the APIVersion.
+	// See the disgordtest package for a fake Discord server to use in unit tests.
+	RESTBaseURL string
+
+	// APIVersion is the Discord REST API version used for every request. Supported versions
+	// are 6, 7 and 8. Defaults to 6. The gateway is not affected and stays on version 6.
+	APIVersion int
+
 	DisableCache bool
 	CacheConfig  *CacheConfig
 	ShardConfig  ShardConfig
 
+	// DispatchConfig bounds the number of goroutines and queued events used to execute
+	// handlers, and decides what happens when handlers can not keep up with the gateway.
+	DispatchConfig DispatchConfig
+
+	// EventSink receives every event after it was cached. Used to forward the events of the shards to
+	// worker processes, see EventBusServer.
+	EventSink EventSink
+
+	// EventSource makes Client.Connect consume events from the source, in stead of connecting to the
+	// Discord gateway. Gateway commands are forwarded to the Client that owns the shards, see EventBusClient.
+	// Shard specific features, such as Client.Ready and Client.HeartbeatLatencies, are not supported.
+	EventSource EventSource
+
+	// VoiceEncryptionModes is the preferred order of the voice encryption modes. The first mode offered by
+	// Discord is used. Defaults to DefaultVoiceEncryptionModes.
+	VoiceEncryptionModes []string
+
 	// IgnoreEvents will skip events that matches the given event names.
 	// WARNING! This can break your caching, so be careful about what you want to ignore.
 	//
@@ -201,6 +246,7 @@ type Client struct {
 	config       *Config
 	botToken     string
 
+	// myID is guarded by the Client lock, see botID
 	myID        Snowflake
 	permissions PermissionBits
 
@@ -280,20 +326,33 @@ func (c *Client) GetPermissions() (permissions PermissionBits) {
 // for your bot to run successfully, you should utilise
 //  Client.
 func (c *Client) InviteURL(ctx context.Context) (u string, err error) {
-	if _, err = c.GetCurrentUser(ctx); err != nil && c.myID.IsZero() {
+	if _, err = c.GetCurrentUser(ctx); err != nil && c.botID().IsZero() {
 		return "", disgorderr.Wrap(err, "can't create invite url without fetching the bot id")
 	}
 
 	format := "https://discordapp.com/oauth2/authorize?scope=bot&client_id=%s&permissions=%d"
-	u = fmt.Sprintf(format, c.myID.String(), c.permissions)
+	u = fmt.Sprintf(format, c.botID().String(), c.permissions)
 	return u, nil
 }
 
+// botID returns the user ID of the bot, or 0 when it has not been fetched yet. See GetCurrentUser
+func (c *Client) botID() Snowflake {
+	c.RLock()
+	defer c.RUnlock()
+	return c.myID
+}
+
+func (c *Client) setBotID(id Snowflake) {
+	c.Lock()
+	c.myID = id
+	c.Unlock()
+}
+
 // AvgHeartbeatLatency checks the duration of waiting before receiving a response from Discord when a
 // heartbeat packet was sent. Note that heartbeats are usually sent around once a minute and is not a accurate
 // way to measure delay between the Client and Discord server
 func (c *Client) AvgHeartbeatLatency() (duration time.Duration, err error) {
-	latencies, err := c.shardManager.HeartbeatLatencies()
+	latencies, err := c.HeartbeatLatencies()
 	if err != nil {
 		return 0, err
 	}
@@ -309,6 +368,9 @@ func (c *Client) AvgHeartbeatLatency() (duration time.Duration, err error) {
 
 // HeartbeatLatencies returns latencies mapped to each shard, by their respective ID. shardID => latency.
 func (c *Client) HeartbeatLatencies() (latencies map[uint]time.Duration, err error) {
+	if c.shardManager == nil {
+		return nil, errors.New("no shards are connected by this client")
+	}
 	return c.shardManager.HeartbeatLatencies()
 }
 
@@ -343,6 +405,16 @@ func (c *Client) RESTBucketGrouping() (group map[string][]string) {
 	return c.req.BucketGrouping()
 }
 
+// DispatchStats shows the state of the event dispatch queue and workers. A queue depth close to the
+// queue size means the handlers can not keep up with the incoming events. See Config.DispatchConfig.
+func (c *Client) DispatchStats() DispatchStats {
+	stats := c.dispatcher.executor.stats()
+	if c.dispatcher.dedup != nil {
+		stats.Deduplicated = c.dispatcher.dedup.discarded.Load()
+	}
+	return stats
+}
+
 // Req return the request object. Used in REST requests to handle rate limits,
 // wrong http responses, etc.
 func (c *Client) Req() httd.Requester {
@@ -388,7 +460,11 @@ func (c *Client) Connect(ctx context.Context) (err error) {
 	if me, err = c.GetCurrentUser(ctx); err != nil {
 		return err
 	}
-	c.myID = me.ID
+	c.setBotID(me.ID)
+
+	if c.config.EventSource != nil {
+		return c.connectToSource(ctx)
+	}
 
 	if err = gateway.ConfigureShardConfig(ctx, c, &c.config.ShardConfig); err != nil {
 		return err
@@ -404,6 +480,7 @@ func (c *Client) Connect(ctx context.Context) (err error) {
 		DisgordInfo:        LibraryInfo(),
 		ProjectName:        c.config.ProjectName,
 		BotToken:           c.config.BotToken,
+		RESTClient:         c,
 	})
 
 	c.setupConnectEnv()
@@ -423,8 +500,15 @@ func (c *Client) Connect(ctx context.Context) (err error) {
 func (c *Client) Disconnect() (err error) {
 	fmt.Println() // to keep ^C on it's own line
 	c.log.Info("Closing Discord gateway connection")
+	c.voiceRepository.closeAll()
 	close(c.dispatcher.shutdown)
-	if err = c.shardManager.Disconnect(); err != nil {
+	c.dispatcher.cancel()
+	if c.config.EventSource != nil {
+		err = c.disconnectFromSource()
+	} else {
+		err = c.shardManager.Disconnect()
+	}
+	if err != nil {
 		c.log.Error(err)
 		return err
 	}
@@ -543,6 +627,9 @@ func (c *Client) Ready(cb func()) {
 		ctrl.Lock()
 		defer ctrl.Unlock()
 
+		if c.shardManager == nil {
+			return // the shards are owned by a different client, see Config.EventSource
+		}
 		l := c.shardManager.ShardCount()
 		if l != uint(len(ctrl.shardReady)) {
 			ctrl.shardReady = make([]bool, l)
@@ -609,19 +696,51 @@ func (c *Client) GuildsReady(cb func()) {
 // If the HandlerCtrl.OnInsert returns an error, the related handlers are still added to the dispatcher.
 // But the error is logged to the injected logger instance (log.Error).
 //
+// A handler that panics is recovered, and the remaining handlers are still executed. Handlers that can fail
+// can use the error handler signatures, eg. MessageCreateErrHandler. See DispatchConfig.OnHandlerPanic and
+// DispatchConfig.OnHandlerError.
+//
+// The typed registration methods, eg. Client.OnMessageCreate, verifies the handler, middlewares and
+// controller at compile time.
+//
 // This ctrl feature was inspired by https://github.com/discordjs/discord.js
 func (c *Client) On(event string, inputs ...interface{}) {
-	if err := ValidateHandlerInputs(inputs...); err != nil {
+	if _, err := c.Subscribe(event, inputs...); err != nil {
 		panic(err)
 	}
+}
 
-	if err := c.dispatcher.register(event, inputs...); err != nil {
-		panic(err)
+// Subscribe works like On, but returns an error on invalid inputs in stead of panicking. The returned
+// Subscription removes the handlers on demand, which is useful when features are loaded and unloaded
+// at runtime:
+//
+//  sub, err := Client.Subscribe(EvtMessageCreate, onMessage)
+//  if err != nil {
+//      return err
+//  }
+//  defer sub.Unsubscribe()
+func (c *Client) Subscribe(event string, inputs ...interface{}) (*Subscription, error) {
+	if err := ValidateHandlerInputs(inputs...); err != nil {
+		return nil, err
 	}
+
+	return c.dispatcher.subscribe(event, inputs...)
+}
+
+// subscribeTyped registers the inputs of the typed registration methods, eg. Client.OnMessageCreate
+func (c *Client) subscribeTyped(event string, inputs []interface{}) *Subscription {
+	sub, err := c.dispatcher.subscribe(event, inputs...)
+	if err != nil {
+		panic(err) // the inputs are typed, so this is a bug in DisGord
+	}
+	return sub
 }
 
 // Emit sends a socket command directly to Discord.
 func (c *Client) Emit(name gatewayCmdName, payload gatewayCmdPayload) (unchandledGuildIDs []Snowflake, err error) {
+	if c.config.EventSource != nil {
+		return c.emitToSource(name, payload)
+	}
 	if c.shardManager == nil {
 		return nil, errors.New("you must connect before you can Emit")
 	}
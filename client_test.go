@@ -218,6 +218,58 @@ func TestClient_On_Middleware(t *testing.T) {
 	wg.Wait()
 }
 
+func TestClient_OnTyped(t *testing.T) {
+	c := New(Config{
+		BotToken:       "testing",
+		DisableCache:   true,
+		DispatchConfig: DispatchConfig{Workers: 1}, // handle the events in order
+	})
+	defer close(c.dispatcher.shutdown)
+	input := make(chan *gateway.Event)
+	go demultiplexer(c.dispatcher, input, nil)
+
+	const prefix = "!"
+	var hasPrefix MessageCreateMiddleware = func(evt *MessageCreate) *MessageCreate {
+		if strings.HasPrefix(evt.Message.Content, prefix) {
+			return evt
+		}
+		return nil
+	}
+	trimPrefix := MessageCreateMiddleware(func(evt *MessageCreate) *MessageCreate {
+		evt.Message.Content = strings.TrimPrefix(evt.Message.Content, prefix)
+		return evt
+	})
+
+	contents := make(chan string, 3)
+	sub := c.OnMessageCreate(func(s Session, evt *MessageCreate) {
+		contents <- evt.Message.Content
+	}, hasPrefix, trimPrefix, WithCtrl(&Ctrl{Runs: 2}))
+	if sub.Event() != EvtMessageCreate {
+		t.Errorf("expected subscription for %s, got %s", EvtMessageCreate, sub.Event())
+	}
+
+	for _, content := range []string{"ignored", "!ping", "!pong", "!too late"} {
+		input <- &gateway.Event{Name: EvtMessageCreate, Data: []byte(`{"content":"` + content + `"}`)}
+	}
+
+	for _, expects := range []string{"ping", "pong"} {
+		select {
+		case content := <-contents:
+			if content != expects {
+				t.Errorf("expected content %s, got %s", expects, content)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the handler to be triggered")
+		}
+	}
+
+	select {
+	case content := <-contents:
+		t.Errorf("expected the controller to stop the handler after 2 runs, got %s", content)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
 // TestClient_System looks for crashes when the DisGord system starts up.
 // the websocket logic is excluded to avoid crazy rewrites. At least, for now.
 func TestClient_System(t *testing.T) {
@@ -0,0 +1,123 @@
+package disgord
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// EventPredicate decides if an event is collected, see Client.Collect. The event is a pointer to the event
+// struct, eg. *MessageCreate for EvtMessageCreate.
+type EventPredicate = func(evt interface{}) bool
+
+// AwaitEvent waits for the first event that matches the predicate. Use the context to set a timeout. The
+// returned error is the context error when no event matched in time.
+//
+//  // wait 60 seconds for the author to answer
+//  ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+//  defer cancel()
+//  evt, err := Client.AwaitEvent(ctx, disgord.EvtMessageCreate, func(evt interface{}) bool {
+//      msg := evt.(*disgord.MessageCreate).Message
+//      return msg.Author.ID == authorID && msg.ChannelID == channelID
+//  })
+func (c *Client) AwaitEvent(ctx context.Context, evtName string, predicate EventPredicate) (interface{}, error) {
+	evts, err := c.Collect(ctx, evtName, predicate, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if evt, ok := <-evts; ok {
+		return evt, nil
+	}
+	return nil, ctx.Err()
+}
+
+// Collect streams every event that matches the predicate, until max events are collected or the context
+// is done. The channel is closed and the handler removed when the collector completes. A nil predicate
+// matches every event, and a max of 0 or less collects until the context is done.
+//
+// The channel must be read from, as the collector waits for room in the channel before the event
+// handling continues.
+func (c *Client) Collect(ctx context.Context, evtName string, predicate EventPredicate, max int) (<-chan interface{}, error) {
+	if ctx == nil {
+		return nil, errors.New("context can not be nil")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	col := &collector{
+		ctx:       ctx,
+		predicate: predicate,
+		max:       max,
+		done:      make(chan struct{}),
+	}
+	if max > 0 {
+		col.evts = make(chan interface{}, max)
+	} else {
+		col.evts = make(chan interface{})
+	}
+
+	var collect Middleware = col.collect
+	var handler SimplestHandler = func() {}
+	sub, err := c.Subscribe(evtName, collect, handler)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			col.close()
+		case <-col.done:
+		}
+		sub.Unsubscribe()
+	}()
+
+	return col.evts, nil
+}
+
+type collector struct {
+	sync.Mutex
+	ctx       context.Context
+	predicate EventPredicate
+	max       int
+	collected int
+	closed    bool
+	evts      chan interface{}
+	done      chan struct{}
+}
+
+// collect is used as a middleware, which always stops the event such that the spec is never considered executed
+func (col *collector) collect(evt interface{}) interface{} {
+	col.Lock()
+	defer col.Unlock()
+
+	if col.closed || (col.predicate != nil && !col.predicate(evt)) {
+		return nil
+	}
+
+	select {
+	case col.evts <- evt:
+	case <-col.ctx.Done():
+		return nil
+	}
+
+	col.collected++
+	if col.max > 0 && col.collected >= col.max {
+		col.closed = true
+		close(col.evts)
+		close(col.done)
+	}
+	return nil
+}
+
+func (col *collector) close() {
+	col.Lock()
+	defer col.Unlock()
+
+	if !col.closed {
+		col.closed = true
+		close(col.evts)
+	}
+}
@@ -0,0 +1,187 @@
+package disgord
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andersfylling/disgord/internal/gateway"
+)
+
+func newCollectorTestClient(t *testing.T) (c *Client, input chan *gateway.Event, done func()) {
+	t.Helper()
+	return newCollectorTestClientWithConfig(t, DispatchConfig{Workers: 1})
+}
+
+func newCollectorTestClientWithConfig(t *testing.T, conf DispatchConfig) (c *Client, input chan *gateway.Event, done func()) {
+	t.Helper()
+	c = New(Config{
+		BotToken:       "testing",
+		DisableCache:   true,
+		DispatchConfig: conf,
+	})
+	input = make(chan *gateway.Event)
+	go demultiplexer(c.dispatcher, input, nil)
+	return c, input, func() {
+		close(c.dispatcher.shutdown)
+	}
+}
+
+func waitForHandlers(t *testing.T, d *dispatcher, expects int) {
+	deadline := time.Now().Add(time.Second)
+	for d.nrOfAliveHandlers() != expects {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d handlers, got %d", expects, d.nrOfAliveHandlers())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func messageEvent(content string) *gateway.Event {
+	return &gateway.Event{Name: EvtMessageCreate, Data: []byte(`{"content":"` + content + `"}`)}
+}
+
+func hasContent(content string) EventPredicate {
+	return func(evt interface{}) bool {
+		return evt.(*MessageCreate).Message.Content == content
+	}
+}
+
+func TestClient_AwaitEvent(t *testing.T) {
+	c, input, done := newCollectorTestClient(t)
+	defer done()
+	base := c.dispatcher.nrOfAliveHandlers()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	result := make(chan interface{})
+	go func() {
+		evt, err := c.AwaitEvent(ctx, EvtMessageCreate, hasContent("yes"))
+		if err != nil {
+			t.Error(err)
+		}
+		result <- evt
+	}()
+
+	waitForHandlers(t, c.dispatcher, base+1)
+	input <- messageEvent("no")
+	input <- messageEvent("yes")
+
+	evt := <-result
+	if msg, ok := evt.(*MessageCreate); !ok || msg.Message.Content != "yes" {
+		t.Errorf("expected the matching event, got %+v", evt)
+	}
+	waitForHandlers(t, c.dispatcher, base)
+}
+
+func TestClient_AwaitEventContext(t *testing.T) {
+	c, input, done := newCollectorTestClientWithConfig(t, DispatchConfig{
+		Workers:        1,
+		EventDeadline:  time.Minute,
+		HandlerTimeout: time.Minute,
+	})
+	defer done()
+	base := c.dispatcher.nrOfAliveHandlers()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	result := make(chan interface{})
+	go func() {
+		evt, err := c.AwaitEvent(ctx, EvtMessageCreate, hasContent("yes"))
+		if err != nil {
+			t.Error(err)
+		}
+		result <- evt
+	}()
+
+	waitForHandlers(t, c.dispatcher, base+1)
+	input <- messageEvent("yes")
+
+	evt, ok := (<-result).(*MessageCreate)
+	if !ok {
+		t.Fatal("expected the matching event")
+	}
+	time.Sleep(20 * time.Millisecond) // the dispatch is done
+	if err := evt.Ctx.Err(); err != nil {
+		t.Errorf("expected the collected event to have a live context, got %v", err)
+	}
+}
+
+func TestClient_AwaitEventTimeout(t *testing.T) {
+	c, input, done := newCollectorTestClient(t)
+	defer done()
+	base := c.dispatcher.nrOfAliveHandlers()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	go func() {
+		input <- messageEvent("no")
+	}()
+	evt, err := c.AwaitEvent(ctx, EvtMessageCreate, hasContent("yes"))
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if evt != nil {
+		t.Errorf("expected no event, got %+v", evt)
+	}
+	waitForHandlers(t, c.dispatcher, base)
+}
+
+func TestClient_Collect(t *testing.T) {
+	c, input, done := newCollectorTestClient(t)
+	defer done()
+	base := c.dispatcher.nrOfAliveHandlers()
+
+	evts, err := c.Collect(context.Background(), EvtMessageCreate, nil, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input <- messageEvent("1")
+	input <- messageEvent("2")
+	input <- messageEvent("3")
+
+	var contents []string
+	for evt := range evts {
+		contents = append(contents, evt.(*MessageCreate).Message.Content)
+	}
+	if len(contents) != 2 || contents[0] != "1" || contents[1] != "2" {
+		t.Errorf("expected the first two events, got %+v", contents)
+	}
+	waitForHandlers(t, c.dispatcher, base)
+}
+
+func TestClient_CollectCancel(t *testing.T) {
+	c, input, done := newCollectorTestClient(t)
+	defer done()
+	base := c.dispatcher.nrOfAliveHandlers()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	evts, err := c.Collect(ctx, EvtMessageCreate, hasContent("yes"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input <- messageEvent("yes")
+	if evt := <-evts; evt.(*MessageCreate).Message.Content != "yes" {
+		t.Errorf("expected the matching event, got %+v", evt)
+	}
+
+	cancel()
+	select {
+	case _, open := <-evts:
+		if open {
+			t.Error("expected no more events")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the channel to be closed on cancel")
+	}
+	waitForHandlers(t, c.dispatcher, base)
+
+	if _, err = c.Collect(ctx, EvtMessageCreate, nil, 0); err == nil {
+		t.Error("expected an error for a cancelled context")
+	}
+}
@@ -0,0 +1,36 @@
+// Package disgordtest provides in-memory fakes of the Discord API such that bots built on DisGord
+// can be tested end-to-end without network access or a real bot token.
+//
+// The REST emulator keeps guilds, channels, messages, members, roles, reactions and webhooks in memory,
+// responds with Discord styled rate limit headers and error codes, and records every request for later
+// assertions. Point a disgord.Client at it using the RESTBaseURL option:
+//
+//	srv := disgordtest.NewRESTServer()
+//	defer srv.Close()
+//
+//	guild := srv.AddGuild(disgordtest.Guild{Name: "test guild"})
+//	channel := srv.AddChannel(disgordtest.Channel{GuildID: guild.ID, Name: "general"})
+//
+//	client := disgord.New(disgord.Config{
+//	    BotToken:    srv.BotToken(),
+//	    RESTBaseURL: srv.URL(),
+//	})
+//
+//	_, _ = client.CreateMessage(context.Background(), channel.ID, &disgord.CreateMessageParams{Content: "hi"})
+//	msgs := srv.Messages(channel.ID) // => [{Content: "hi", ...}]
+//
+// The gateway emulator speaks the websocket protocol used by the shards: hello, identify, ready, heartbeats,
+// resumes, invalid sessions, reconnect requests and close codes, including 4011 for re-sharding. Events can
+// be scripted per shard and every command sent by the client is recorded:
+//
+//	gw := disgordtest.NewGatewayServer(disgordtest.WithRESTServer(srv))
+//	defer gw.Close()
+//
+//	_, _ = gw.WaitForShard(ctx, 0)
+//	_ = gw.Dispatch(0, "MESSAGE_CREATE", map[string]interface{}{"content": "hi"})
+//	_ = gw.Reconnect(0)
+//	_, _ = gw.WaitForCommand(ctx, disgordtest.OpResume, nil)
+//
+// The package intentionally does not depend on the disgord package itself, such that it can be used to
+// test the internal packages as well, and to avoid hiding serialization bugs behind shared data structures.
+package disgordtest
@@ -0,0 +1,62 @@
+package disgordtest
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// JSON error codes used by Discord. See
+// https://discordapp.com/developers/docs/topics/opcodes-and-status-codes#json-json-error-codes
+const (
+	ErrCodeGeneral               = 0
+	ErrCodeUnknownChannel        = 10003
+	ErrCodeUnknownGuild          = 10004
+	ErrCodeUnknownMember         = 10007
+	ErrCodeUnknownMessage        = 10008
+	ErrCodeUnknownRole           = 10011
+	ErrCodeUnknownUser           = 10013
+	ErrCodeUnknownEmoji          = 10014
+	ErrCodeUnknownWebhook        = 10015
+	ErrCodeUnknownBan            = 10026
+	ErrCodeMissingAccess         = 50001
+	ErrCodeInvalidWebhookToken   = 50027
+	ErrCodeCannotSendEmptyMsg    = 50006
+	ErrCodeMissingPermissions    = 50013
+	ErrCodeInvalidFormBody       = 50035
+	ErrCodeCannotEditOthersMsg   = 50005
+	ErrCodeTooManyBulkDeleteMsgs = 50016
+)
+
+// APIError is the JSON error body Discord responds with on unsuccessful requests.
+type APIError struct {
+	HTTPCode int    `json:"-"`
+	Code     int    `json:"code"`
+	Message  string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return strconv.Itoa(e.HTTPCode) + ": " + e.Message
+}
+
+func newAPIError(httpCode, code int, msg string) *APIError {
+	return &APIError{HTTPCode: httpCode, Code: code, Message: msg}
+}
+
+var (
+	errUnauthorized   = newAPIError(http.StatusUnauthorized, ErrCodeGeneral, "401: Unauthorized")
+	errNotFound       = newAPIError(http.StatusNotFound, ErrCodeGeneral, "404: Not Found")
+	errMethod         = newAPIError(http.StatusMethodNotAllowed, ErrCodeGeneral, "405: Method Not Allowed")
+	errInvalidBody    = newAPIError(http.StatusBadRequest, ErrCodeInvalidFormBody, "Invalid Form Body")
+	errEmptyMessage   = newAPIError(http.StatusBadRequest, ErrCodeCannotSendEmptyMsg, "Cannot send an empty message")
+	errUnknownChannel = newAPIError(http.StatusNotFound, ErrCodeUnknownChannel, "Unknown Channel")
+	errUnknownGuild   = newAPIError(http.StatusNotFound, ErrCodeUnknownGuild, "Unknown Guild")
+	errUnknownMember  = newAPIError(http.StatusNotFound, ErrCodeUnknownMember, "Unknown Member")
+	errUnknownMessage = newAPIError(http.StatusNotFound, ErrCodeUnknownMessage, "Unknown Message")
+	errUnknownRole    = newAPIError(http.StatusNotFound, ErrCodeUnknownRole, "Unknown Role")
+	errUnknownUser    = newAPIError(http.StatusNotFound, ErrCodeUnknownUser, "Unknown User")
+	errUnknownEmoji   = newAPIError(http.StatusNotFound, ErrCodeUnknownEmoji, "Unknown Emoji")
+	errUnknownWebhook = newAPIError(http.StatusNotFound, ErrCodeUnknownWebhook, "Unknown Webhook")
+	errUnknownBan     = newAPIError(http.StatusNotFound, ErrCodeUnknownBan, "Unknown Ban")
+	errWebhookToken   = newAPIError(http.StatusUnauthorized, ErrCodeInvalidWebhookToken, "Invalid Webhook Token")
+	errNotAuthor      = newAPIError(http.StatusForbidden, ErrCodeCannotEditOthersMsg, "Cannot edit a message authored by another user")
+)
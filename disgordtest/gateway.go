@@ -0,0 +1,723 @@
+package disgordtest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// Gateway operation codes. See
+// https://discordapp.com/developers/docs/topics/opcodes-and-status-codes#gateway-gateway-opcodes
+const (
+	OpDispatch            uint = 0
+	OpHeartbeat           uint = 1
+	OpIdentify            uint = 2
+	OpStatusUpdate        uint = 3
+	OpVoiceStateUpdate    uint = 4
+	OpResume              uint = 6
+	OpReconnect           uint = 7
+	OpRequestGuildMembers uint = 8
+	OpInvalidSession      uint = 9
+	OpHello               uint = 10
+	OpHeartbeatAck        uint = 11
+)
+
+// Gateway close event codes. See
+// https://discordapp.com/developers/docs/topics/opcodes-and-status-codes#gateway-gateway-close-event-codes
+const (
+	CloseUnknownError         = 4000
+	CloseUnknownOpcode        = 4001
+	CloseDecodeError          = 4002
+	CloseNotAuthenticated     = 4003
+	CloseAuthenticationFailed = 4004
+	CloseAlreadyAuthenticated = 4005
+	CloseInvalidSeq           = 4007
+	CloseRateLimited          = 4008
+	CloseSessionTimedOut      = 4009
+	CloseInvalidShard         = 4010
+	CloseShardingRequired     = 4011
+)
+
+// DefaultHeartbeatInterval is the interval sent in the Hello payload unless WithHeartbeatInterval is used.
+// It is long enough that clients only send their initial heartbeat during most unit tests.
+const DefaultHeartbeatInterval = 45 * time.Second
+
+// GatewayCommand is a payload received by the GatewayServer.
+type GatewayCommand struct {
+	// ShardID is the shard the connection identified or resumed as. -1 when it has not yet done either.
+	ShardID    int
+	Op         uint
+	Data       json.RawMessage
+	ReceivedAt time.Time
+}
+
+// GatewaySession describes a session created by an identify command.
+type GatewaySession struct {
+	ID         string
+	ShardID    uint
+	ShardCount uint
+	Sequence   uint64
+
+	// Connected is true while a websocket connection is using the session, and READY or RESUMED has been sent.
+	Connected bool
+}
+
+type gatewayPayload struct {
+	Op   uint            `json:"op"`
+	Data json.RawMessage `json:"d"`
+	Seq  *uint64         `json:"s"`
+	Name *string         `json:"t"`
+}
+
+type gatewaySession struct {
+	// mu serializes dispatches such that sequence numbers are written in order
+	mu sync.Mutex
+
+	id      string
+	shard   [2]uint
+	seq     uint64
+	history [][]byte // every dispatch, for replaying on resume
+	conn    *gatewayConn
+	ready   bool // READY or RESUMED has been sent on conn
+}
+
+type gatewayConn struct {
+	ws      *websocket.Conn
+	writeMu sync.Mutex
+	session *gatewaySession
+	closed  bool
+}
+
+func (c *gatewayConn) write(data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return c.ws.Write(ctx, websocket.MessageText, data)
+}
+
+// GatewayOption configures a GatewayServer on creation.
+type GatewayOption func(s *GatewayServer)
+
+// WithRESTServer shares the bot token and user of the REST emulator, and points the REST emulator's
+// /gateway/bot response to the gateway server.
+func WithRESTServer(rest *RESTServer) GatewayOption {
+	return func(s *GatewayServer) {
+		s.rest = rest
+		s.token = rest.BotToken()
+		s.bot = rest.Bot()
+	}
+}
+
+// WithHeartbeatInterval sets the heartbeat interval sent in the Hello payload.
+func WithHeartbeatInterval(interval time.Duration) GatewayOption {
+	return func(s *GatewayServer) {
+		s.heartbeatInterval = interval
+	}
+}
+
+// WithRequiredShards sets the minimum shard count accepted in identify commands. Connections identifying
+// with a lower shard count are closed with the 4011 close code.
+func WithRequiredShards(shards uint) GatewayOption {
+	return func(s *GatewayServer) {
+		s.requiredShards = shards
+	}
+}
+
+// GatewayServer is an in-memory Discord gateway emulator. It handles the connection lifecycle (hello, identify,
+// heartbeats, resume, invalid sessions, reconnects and close codes) while letting tests script the events
+// dispatched to each shard and assert on the commands received. All methods are safe for concurrent use.
+type GatewayServer struct {
+	mu sync.Mutex
+
+	srv  *httptest.Server
+	rest *RESTServer
+
+	token             string
+	bot               User
+	heartbeatInterval time.Duration
+	requiredShards    uint
+	skipHeartbeatAcks bool
+
+	guilds   []Guild
+	conns    map[*gatewayConn]bool
+	sessions map[string]*gatewaySession
+	commands []GatewayCommand
+
+	// changed is closed and replaced whenever the commands or sessions changes
+	changed chan struct{}
+}
+
+// NewGatewayServer creates and starts a gateway emulator listening on a random local port.
+// Remember to call Close.
+func NewGatewayServer(opts ...GatewayOption) *GatewayServer {
+	s := &GatewayServer{
+		token: DefaultBotToken,
+		bot: User{
+			ID:            NewSnowflake(),
+			Username:      "disgordtest",
+			Discriminator: "0001",
+			Bot:           true,
+		},
+		heartbeatInterval: DefaultHeartbeatInterval,
+		requiredShards:    1,
+		conns:             make(map[*gatewayConn]bool),
+		sessions:          make(map[string]*gatewaySession),
+		changed:           make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveWS))
+	if s.rest != nil {
+		s.rest.SetGateway(s.URL(), s.requiredShards)
+	}
+	return s
+}
+
+// URL returns the websocket url of the gateway. Use it as the ShardConfig.URL.
+func (s *GatewayServer) URL() string {
+	return "ws" + strings.TrimPrefix(s.srv.URL, "http")
+}
+
+// Close disconnects every client and shuts down the server.
+func (s *GatewayServer) Close() {
+	s.mu.Lock()
+	conns := make([]*gatewayConn, 0, len(s.conns))
+	for conn := range s.conns {
+		conns = append(conns, conn)
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, conn := range conns {
+		wg.Add(1)
+		go func(conn *gatewayConn) {
+			_ = conn.ws.Close(websocket.StatusGoingAway, "server is shutting down")
+			wg.Done()
+		}(conn)
+	}
+	wg.Wait()
+	s.srv.Close()
+}
+
+// AddGuild makes the guild part of the READY payload of the shard it belongs to. A GUILD_CREATE
+// is dispatched for it after READY, just like Discord does.
+func (s *GatewayServer) AddGuild(guild Guild) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.guilds = append(s.guilds, guild)
+}
+
+// SetHeartbeatAcks decides if heartbeats are acknowledged. Disable it to test zombied connections.
+func (s *GatewayServer) SetHeartbeatAcks(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.skipHeartbeatAcks = !enabled
+}
+
+// RequiredShards returns the minimum shard count accepted in identify commands.
+func (s *GatewayServer) RequiredShards() uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requiredShards
+}
+
+// RequireShards updates the minimum shard count, and closes every connection that identified with a lower
+// shard count using the 4011 close code. Their sessions are removed, as Discord requires a new identify.
+func (s *GatewayServer) RequireShards(shards uint) {
+	s.mu.Lock()
+	s.requiredShards = shards
+	var conns []*gatewayConn
+	for id, session := range s.sessions {
+		if session.shard[1] >= shards {
+			continue
+		}
+		if session.conn != nil {
+			conns = append(conns, session.conn)
+			s.detach(session.conn)
+		}
+		delete(s.sessions, id)
+	}
+	rest := s.rest
+	s.notify()
+	s.mu.Unlock()
+
+	if rest != nil {
+		rest.SetGateway(s.URL(), shards)
+	}
+	for _, conn := range conns {
+		s.closeConn(conn, CloseShardingRequired, "Sharding required.")
+	}
+}
+
+// Commands returns every payload received so far, in order.
+func (s *GatewayServer) Commands() []GatewayCommand {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]GatewayCommand(nil), s.commands...)
+}
+
+// ClearCommands empties the command log.
+func (s *GatewayServer) ClearCommands() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commands = nil
+}
+
+// WaitForCommand blocks until a command with the given operation code that satisfies match is received. Already
+// received commands are included. match can be nil.
+func (s *GatewayServer) WaitForCommand(ctx context.Context, op uint, match func(cmd GatewayCommand) bool) (GatewayCommand, error) {
+	for {
+		s.mu.Lock()
+		changed := s.changed
+		for _, cmd := range s.commands {
+			if cmd.Op == op && (match == nil || match(cmd)) {
+				s.mu.Unlock()
+				return cmd, nil
+			}
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return GatewayCommand{}, errors.New("no command with op " + strconv.FormatUint(uint64(op), 10) + " was received: " + ctx.Err().Error())
+		}
+	}
+}
+
+// Sessions returns every session that can still be resumed.
+func (s *GatewayServer) Sessions() []GatewaySession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions := make([]GatewaySession, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, session.snapshot())
+	}
+	return sessions
+}
+
+// WaitForSession blocks until a connected session satisfies match. match can be nil.
+func (s *GatewayServer) WaitForSession(ctx context.Context, match func(session GatewaySession) bool) (GatewaySession, error) {
+	for {
+		s.mu.Lock()
+		changed := s.changed
+		for _, session := range s.sessions {
+			snapshot := session.snapshot()
+			if snapshot.Connected && (match == nil || match(snapshot)) {
+				s.mu.Unlock()
+				return snapshot, nil
+			}
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return GatewaySession{}, errors.New("no matching session: " + ctx.Err().Error())
+		}
+	}
+}
+
+// WaitForShard blocks until the given shard has a connected session.
+func (s *GatewayServer) WaitForShard(ctx context.Context, shardID uint) (GatewaySession, error) {
+	return s.WaitForSession(ctx, func(session GatewaySession) bool {
+		return session.ShardID == shardID
+	})
+}
+
+// Dispatch sends an event to the connected session of the shard. data is marshalled to JSON, unless
+// it is a []byte or json.RawMessage.
+func (s *GatewayServer) Dispatch(shardID uint, name string, data interface{}) error {
+	session, err := s.activeSession(shardID)
+	if err != nil {
+		return err
+	}
+	return s.dispatch(session, name, data)
+}
+
+// Reconnect sends a reconnect request (op 7) to the shard.
+func (s *GatewayServer) Reconnect(shardID uint) error {
+	session, err := s.activeSession(shardID)
+	if err != nil {
+		return err
+	}
+	return s.send(session.conn, OpReconnect, nil)
+}
+
+// InvalidateSession sends an invalid session (op 9) to the shard. The session is removed unless resumable
+// is true, such that a following resume fails.
+func (s *GatewayServer) InvalidateSession(shardID uint, resumable bool) error {
+	session, err := s.activeSession(shardID)
+	if err != nil {
+		return err
+	}
+	conn := session.conn
+	if !resumable {
+		s.mu.Lock()
+		s.detach(conn)
+		delete(s.sessions, session.id)
+		s.notify()
+		s.mu.Unlock()
+	}
+	return s.send(conn, OpInvalidSession, resumable)
+}
+
+// CloseShard closes the websocket connection of the shard with the given close code. The session
+// can still be resumed.
+func (s *GatewayServer) CloseShard(shardID uint, code int, reason string) error {
+	session, err := s.activeSession(shardID)
+	if err != nil {
+		return err
+	}
+	s.closeConn(session.conn, code, reason)
+	return nil
+}
+
+//////////////////////////////////////////////////////
+//
+// INTERNALS
+//
+//////////////////////////////////////////////////////
+
+// notify wakes up everyone waiting for a change. The lock must be held.
+func (s *GatewayServer) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (session *gatewaySession) snapshot() GatewaySession {
+	return GatewaySession{
+		ID:         session.id,
+		ShardID:    session.shard[0],
+		ShardCount: session.shard[1],
+		Sequence:   session.seq,
+		Connected:  session.conn != nil && session.ready,
+	}
+}
+
+func (s *GatewayServer) activeSession(shardID uint) (*gatewaySession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, session := range s.sessions {
+		if session.shard[0] == shardID && session.conn != nil && session.ready {
+			return session, nil
+		}
+	}
+	return nil, errors.New("shard " + strconv.FormatUint(uint64(shardID), 10) + " is not connected")
+}
+
+func (s *GatewayServer) closeConn(conn *gatewayConn, code int, reason string) {
+	s.mu.Lock()
+	if conn.closed {
+		s.mu.Unlock()
+		return
+	}
+	conn.closed = true
+	s.detach(conn)
+	s.notify()
+	s.mu.Unlock()
+
+	// the close handshake blocks until the client responds
+	go conn.ws.Close(websocket.StatusCode(code), reason)
+}
+
+func (s *GatewayServer) send(conn *gatewayConn, op uint, data interface{}) error {
+	d, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(&gatewayPayload{Op: op, Data: d})
+	if err != nil {
+		return err
+	}
+	return conn.write(payload)
+}
+
+func (s *GatewayServer) dispatch(session *gatewaySession, name string, data interface{}) error {
+	var d []byte
+	switch t := data.(type) {
+	case []byte:
+		d = t
+	case json.RawMessage:
+		d = t
+	default:
+		var err error
+		if d, err = json.Marshal(data); err != nil {
+			return err
+		}
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	s.mu.Lock()
+	session.seq++
+	seq := session.seq
+	payload, err := json.Marshal(&gatewayPayload{Op: OpDispatch, Data: d, Seq: &seq, Name: &name})
+	if err != nil {
+		session.seq--
+		s.mu.Unlock()
+		return err
+	}
+	session.history = append(session.history, payload)
+	conn := session.conn
+	s.mu.Unlock()
+
+	if conn == nil {
+		return errors.New("the session is no longer connected")
+	}
+	return conn.write(payload)
+}
+
+func (s *GatewayServer) serveWS(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.Accept(w, r, &websocket.AcceptOptions{InsecureSkipVerify: true})
+	if err != nil {
+		return
+	}
+	ws.SetReadLimit(1 << 20)
+	conn := &gatewayConn{ws: ws}
+
+	s.mu.Lock()
+	s.conns[conn] = true
+	interval := s.heartbeatInterval
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.detach(conn)
+		conn.closed = true
+		s.notify()
+		s.mu.Unlock()
+	}()
+
+	hello := map[string]interface{}{
+		"heartbeat_interval": int64(interval / time.Millisecond),
+		"_trace":             []string{"disgordtest"},
+	}
+	if err = s.send(conn, OpHello, hello); err != nil {
+		return
+	}
+
+	for {
+		_, data, err := ws.Read(context.Background())
+		if err != nil {
+			return
+		}
+
+		var p gatewayPayload
+		if err = json.Unmarshal(data, &p); err != nil {
+			s.closeConn(conn, CloseDecodeError, "Error while decoding payload.")
+			return
+		}
+
+		s.mu.Lock()
+		shardID := -1
+		if conn.session != nil {
+			shardID = int(conn.session.shard[0])
+		}
+		s.commands = append(s.commands, GatewayCommand{
+			ShardID:    shardID,
+			Op:         p.Op,
+			Data:       p.Data,
+			ReceivedAt: time.Now(),
+		})
+		authenticated := conn.session != nil
+		s.notify()
+		s.mu.Unlock()
+
+		switch p.Op {
+		case OpHeartbeat:
+			s.onHeartbeat(conn)
+		case OpIdentify:
+			if authenticated {
+				s.closeConn(conn, CloseAlreadyAuthenticated, "Already authenticated.")
+				return
+			}
+			s.onIdentify(conn, p.Data)
+		case OpResume:
+			s.onResume(conn, p.Data)
+		case OpStatusUpdate, OpVoiceStateUpdate, OpRequestGuildMembers:
+			if !authenticated {
+				s.closeConn(conn, CloseNotAuthenticated, "Not authenticated.")
+				return
+			}
+		default:
+			s.closeConn(conn, CloseUnknownOpcode, "Unknown opcode.")
+			return
+		}
+	}
+}
+
+func (s *GatewayServer) onHeartbeat(conn *gatewayConn) {
+	s.mu.Lock()
+	skip := s.skipHeartbeatAcks
+	s.mu.Unlock()
+	if !skip {
+		_ = s.send(conn, OpHeartbeatAck, nil)
+	}
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (s *GatewayServer) onIdentify(conn *gatewayConn, data json.RawMessage) {
+	var identify struct {
+		Token string   `json:"token"`
+		Shard *[2]uint `json:"shard"`
+	}
+	if err := json.Unmarshal(data, &identify); err != nil {
+		s.closeConn(conn, CloseDecodeError, "Error while decoding payload.")
+		return
+	}
+	if identify.Shard == nil {
+		identify.Shard = &[2]uint{0, 1}
+	}
+
+	s.mu.Lock()
+	token := s.token
+	required := s.requiredShards
+	s.mu.Unlock()
+
+	if identify.Token != token {
+		s.closeConn(conn, CloseAuthenticationFailed, "Authentication failed.")
+		return
+	}
+	if identify.Shard[1] == 0 || identify.Shard[0] >= identify.Shard[1] {
+		s.closeConn(conn, CloseInvalidShard, "Invalid shard.")
+		return
+	}
+	if identify.Shard[1] < required {
+		s.closeConn(conn, CloseShardingRequired, "Sharding required.")
+		return
+	}
+
+	session := &gatewaySession{
+		id:    newSessionID(),
+		shard: *identify.Shard,
+	}
+
+	s.mu.Lock()
+	var guilds []Guild
+	unavailable := []map[string]interface{}{}
+	for _, g := range s.guilds {
+		if uint(g.ID>>22)%session.shard[1] != session.shard[0] {
+			continue
+		}
+		guilds = append(guilds, g)
+		unavailable = append(unavailable, map[string]interface{}{"id": g.ID, "unavailable": true})
+	}
+	bot := s.bot
+	s.sessions[session.id] = session
+	s.attach(conn, session)
+	s.mu.Unlock()
+
+	err := s.dispatch(session, "READY", map[string]interface{}{
+		"v":                6,
+		"user":             bot,
+		"private_channels": []interface{}{},
+		"guilds":           unavailable,
+		"session_id":       session.id,
+		"shard":            session.shard,
+		"_trace":           []string{"disgordtest"},
+	})
+	if err != nil {
+		return
+	}
+	s.markReady(session)
+
+	for _, g := range guilds {
+		_ = s.dispatch(session, "GUILD_CREATE", g)
+	}
+}
+
+// attach makes the connection the active one of the session. The lock must be held.
+func (s *GatewayServer) attach(conn *gatewayConn, session *gatewaySession) {
+	if session.conn != nil && session.conn != conn {
+		session.conn.session = nil
+	}
+	session.conn = conn
+	session.ready = false
+	conn.session = session
+}
+
+// detach removes the connection from its session. The lock must be held.
+func (s *GatewayServer) detach(conn *gatewayConn) {
+	if conn.session != nil {
+		conn.session.conn = nil
+		conn.session.ready = false
+		conn.session = nil
+	}
+}
+
+// markReady is called once READY or RESUMED has been written
+func (s *GatewayServer) markReady(session *gatewaySession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if session.conn != nil {
+		session.ready = true
+		s.notify()
+	}
+}
+
+func (s *GatewayServer) onResume(conn *gatewayConn, data json.RawMessage) {
+	var resume struct {
+		Token     string `json:"token"`
+		SessionID string `json:"session_id"`
+		Seq       uint64 `json:"seq"`
+	}
+	if err := json.Unmarshal(data, &resume); err != nil {
+		s.closeConn(conn, CloseDecodeError, "Error while decoding payload.")
+		return
+	}
+
+	s.mu.Lock()
+	session, ok := s.sessions[resume.SessionID]
+	valid := ok && resume.Token == s.token && resume.Seq <= session.seq
+	s.mu.Unlock()
+	if !valid {
+		_ = s.send(conn, OpInvalidSession, false)
+		return
+	}
+
+	// replay missed events before RESUMED
+	session.mu.Lock()
+	s.mu.Lock()
+	if conn.closed {
+		s.mu.Unlock()
+		session.mu.Unlock()
+		return
+	}
+	s.attach(conn, session)
+	missed := append([][]byte(nil), session.history[resume.Seq:]...)
+	s.mu.Unlock()
+	for _, payload := range missed {
+		if err := conn.write(payload); err != nil {
+			session.mu.Unlock()
+			return
+		}
+	}
+	session.mu.Unlock()
+
+	if err := s.dispatch(session, "RESUMED", map[string]interface{}{"_trace": []string{"disgordtest"}}); err != nil {
+		return
+	}
+	s.markReady(session)
+}
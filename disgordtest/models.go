@@ -0,0 +1,174 @@
+package disgordtest
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/andersfylling/disgord/internal/util"
+)
+
+type Snowflake = util.Snowflake
+
+// discordEpoch is the first second of 2015, in milliseconds
+const discordEpoch = 1420070400000
+
+var snowflakeIncrement uint64
+
+// NewSnowflake creates a unique snowflake based on the current time, just like Discord does.
+func NewSnowflake() Snowflake {
+	ms := uint64(time.Now().UnixNano()/int64(time.Millisecond)) - discordEpoch
+	inc := atomic.AddUint64(&snowflakeIncrement, 1) & 0xFFF
+	return util.NewSnowflake(ms<<22 | inc)
+}
+
+// Timestamp formats a time the way Discord does in JSON payloads
+func Timestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000+00:00")
+}
+
+// Channel types
+const (
+	ChannelTypeGuildText uint = iota
+	ChannelTypeDM
+	ChannelTypeGuildVoice
+	ChannelTypeGroupDM
+	ChannelTypeGuildCategory
+)
+
+// User https://discordapp.com/developers/docs/resources/user#user-object
+type User struct {
+	ID            Snowflake `json:"id"`
+	Username      string    `json:"username"`
+	Discriminator string    `json:"discriminator"`
+	Avatar        *string   `json:"avatar"`
+	Bot           bool      `json:"bot,omitempty"`
+}
+
+// Role https://discordapp.com/developers/docs/topics/permissions#role-object
+type Role struct {
+	ID          Snowflake `json:"id"`
+	Name        string    `json:"name"`
+	Color       uint      `json:"color"`
+	Hoist       bool      `json:"hoist"`
+	Position    int       `json:"position"`
+	Permissions uint64    `json:"permissions"`
+	Managed     bool      `json:"managed"`
+	Mentionable bool      `json:"mentionable"`
+}
+
+// PermissionOverwrite https://discordapp.com/developers/docs/resources/channel#overwrite-object
+type PermissionOverwrite struct {
+	ID    Snowflake `json:"id"`
+	Type  string    `json:"type"`
+	Allow uint64    `json:"allow"`
+	Deny  uint64    `json:"deny"`
+}
+
+// Channel https://discordapp.com/developers/docs/resources/channel#channel-object
+type Channel struct {
+	ID                   Snowflake             `json:"id"`
+	Type                 uint                  `json:"type"`
+	GuildID              Snowflake             `json:"guild_id,omitempty"`
+	Position             int                   `json:"position,omitempty"`
+	PermissionOverwrites []PermissionOverwrite `json:"permission_overwrites"`
+	Name                 string                `json:"name,omitempty"`
+	Topic                string                `json:"topic,omitempty"`
+	NSFW                 bool                  `json:"nsfw,omitempty"`
+	LastMessageID        Snowflake             `json:"last_message_id,omitempty"`
+	Bitrate              uint                  `json:"bitrate,omitempty"`
+	UserLimit            uint                  `json:"user_limit,omitempty"`
+	RateLimitPerUser     uint                  `json:"rate_limit_per_user,omitempty"`
+	Recipients           []User                `json:"recipients,omitempty"`
+	ParentID             Snowflake             `json:"parent_id,omitempty"`
+}
+
+// Member https://discordapp.com/developers/docs/resources/guild#guild-member-object
+type Member struct {
+	GuildID  Snowflake   `json:"guild_id,omitempty"`
+	User     User        `json:"user"`
+	Nick     string      `json:"nick,omitempty"`
+	Roles    []Snowflake `json:"roles"`
+	JoinedAt string      `json:"joined_at"`
+	Deaf     bool        `json:"deaf"`
+	Mute     bool        `json:"mute"`
+}
+
+// Emoji https://discordapp.com/developers/docs/resources/emoji#emoji-object
+type Emoji struct {
+	ID   Snowflake `json:"id"`
+	Name string    `json:"name"`
+}
+
+// Guild https://discordapp.com/developers/docs/resources/guild#guild-object
+type Guild struct {
+	ID          Snowflake `json:"id"`
+	Name        string    `json:"name"`
+	Icon        *string   `json:"icon"`
+	OwnerID     Snowflake `json:"owner_id"`
+	Region      string    `json:"region"`
+	Roles       []Role    `json:"roles"`
+	Emojis      []Emoji   `json:"emojis"`
+	MemberCount uint      `json:"member_count,omitempty"`
+	PremiumTier uint      `json:"premium_tier"`
+}
+
+// Attachment https://discordapp.com/developers/docs/resources/channel#attachment-object
+type Attachment struct {
+	ID       Snowflake `json:"id"`
+	Filename string    `json:"filename"`
+	Size     int       `json:"size"`
+	URL      string    `json:"url"`
+	ProxyURL string    `json:"proxy_url"`
+
+	// Data holds the uploaded file content. It is not part of the JSON payload.
+	Data []byte `json:"-"`
+}
+
+// Reaction https://discordapp.com/developers/docs/resources/channel#reaction-object
+type Reaction struct {
+	Count uint  `json:"count"`
+	Me    bool  `json:"me"`
+	Emoji Emoji `json:"emoji"`
+
+	users []Snowflake
+}
+
+// Message https://discordapp.com/developers/docs/resources/channel#message-object
+type Message struct {
+	ID              Snowflake         `json:"id"`
+	ChannelID       Snowflake         `json:"channel_id"`
+	GuildID         Snowflake         `json:"guild_id,omitempty"`
+	Author          User              `json:"author"`
+	Content         string            `json:"content"`
+	Timestamp       string            `json:"timestamp"`
+	EditedTimestamp *string           `json:"edited_timestamp"`
+	Tts             bool              `json:"tts"`
+	MentionEveryone bool              `json:"mention_everyone"`
+	Mentions        []User            `json:"mentions"`
+	MentionRoles    []Snowflake       `json:"mention_roles"`
+	Attachments     []Attachment      `json:"attachments"`
+	Embeds          []json.RawMessage `json:"embeds"`
+	Reactions       []Reaction        `json:"reactions,omitempty"`
+	Nonce           string            `json:"nonce,omitempty"`
+	Pinned          bool              `json:"pinned"`
+	WebhookID       Snowflake         `json:"webhook_id,omitempty"`
+	Type            uint              `json:"type"`
+}
+
+// Webhook https://discordapp.com/developers/docs/resources/webhook#webhook-object
+type Webhook struct {
+	ID        Snowflake `json:"id"`
+	GuildID   Snowflake `json:"guild_id,omitempty"`
+	ChannelID Snowflake `json:"channel_id"`
+	User      *User     `json:"user,omitempty"`
+	Name      string    `json:"name"`
+	Avatar    *string   `json:"avatar"`
+	Token     string    `json:"token"`
+}
+
+// Ban https://discordapp.com/developers/docs/resources/guild#ban-object
+type Ban struct {
+	Reason *string `json:"reason"`
+	User   User    `json:"user"`
+}
@@ -0,0 +1,103 @@
+package disgordtest
+
+import (
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Default rate limit applied to every route bucket. Most Discord routes allows a handful of
+// requests per a few seconds, but a shorter window keeps unit tests fast.
+const (
+	DefaultRateLimit       = 5
+	DefaultRateLimitWindow = time.Second
+)
+
+type rateLimitBucket struct {
+	hash      string
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+type rateLimiter struct {
+	sync.Mutex
+	disabled bool
+	limit    int
+	window   time.Duration
+	buckets  map[string]*rateLimitBucket
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		buckets: make(map[string]*rateLimitBucket),
+	}
+}
+
+func bucketHash(route string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(route))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// take consumes a request from the bucket identified by the route and major parameter. The returned
+// bucket is a snapshot that is safe to read without locking. ok is false when the bucket is exhausted.
+func (r *rateLimiter) take(route, majorParam string) (bucket rateLimitBucket, ok bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	key := route + ":" + majorParam
+	b, exists := r.buckets[key]
+	now := time.Now()
+	if !exists {
+		b = &rateLimitBucket{
+			hash:  bucketHash(route),
+			limit: r.limit,
+			reset: now.Add(r.window),
+		}
+		b.remaining = b.limit
+		r.buckets[key] = b
+	}
+	if now.After(b.reset) {
+		b.remaining = b.limit
+		b.reset = now.Add(r.window)
+	}
+
+	if r.disabled {
+		return *b, true
+	}
+	if b.remaining == 0 {
+		return *b, false
+	}
+	b.remaining--
+	return *b, true
+}
+
+func formatSeconds(d time.Duration, precise bool) string {
+	if precise {
+		return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+	}
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
+
+// writeHeaders populates the rate limit header fields like Discord. The precision header
+// decides if the timestamps uses seconds or milliseconds.
+func (b *rateLimitBucket) writeHeaders(header http.Header, precise bool) {
+	now := time.Now()
+	resetAfter := b.reset.Sub(now)
+	if resetAfter < 0 {
+		resetAfter = 0
+	}
+	epoch := time.Duration(b.reset.UnixNano())
+
+	header.Set("X-RateLimit-Bucket", b.hash)
+	header.Set("X-RateLimit-Limit", strconv.Itoa(b.limit))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(b.remaining))
+	header.Set("X-RateLimit-Reset", formatSeconds(epoch, precise))
+	header.Set("X-RateLimit-Reset-After", formatSeconds(resetAfter, precise))
+}
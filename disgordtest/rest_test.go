@@ -0,0 +1,301 @@
+package disgordtest_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andersfylling/disgord"
+	"github.com/andersfylling/disgord/disgordtest"
+)
+
+func newClient(t *testing.T, srv *disgordtest.RESTServer) *disgord.Client {
+	client, err := disgord.NewClient(disgord.Config{
+		BotToken:    srv.BotToken(),
+		RESTBaseURL: srv.URL(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestRESTServer_Messages(t *testing.T) {
+	srv := disgordtest.NewRESTServer()
+	defer srv.Close()
+
+	guild := srv.AddGuild(disgordtest.Guild{Name: "test"})
+	channel := srv.AddChannel(disgordtest.Channel{GuildID: guild.ID, Name: "general"})
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	msg, err := client.CreateMessage(ctx, channel.ID, &disgord.CreateMessageParams{
+		Content: "hello",
+		Files: []disgord.CreateMessageFileParams{
+			{Reader: bytes.NewBufferString("data"), FileName: "file.txt"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Content != "hello" || msg.ChannelID != channel.ID || msg.GuildID != guild.ID {
+		t.Errorf("unexpected message %+v", msg)
+	}
+	if len(msg.Attachments) != 1 || msg.Attachments[0].Filename != "file.txt" {
+		t.Errorf("expected one attachment named file.txt. Got %+v", msg.Attachments)
+	}
+
+	stored := srv.Messages(channel.ID)
+	if len(stored) != 1 {
+		t.Fatalf("expected 1 stored message, got %d", len(stored))
+	}
+	if data := string(stored[0].Attachments[0].Data); data != "data" {
+		t.Errorf("expected uploaded file content 'data', got '%s'", data)
+	}
+
+	if err = client.CreateReaction(ctx, channel.ID, msg.ID, "👍"); err != nil {
+		t.Fatal(err)
+	}
+	if reactions := srv.Messages(channel.ID)[0].Reactions; len(reactions) != 1 || !reactions[0].Me {
+		t.Errorf("expected one reaction by the bot. Got %+v", reactions)
+	}
+
+	if err = client.DeleteMessage(ctx, channel.ID, msg.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetMessage(ctx, channel.ID, msg.ID, disgord.IgnoreCache); err == nil {
+		t.Fatal("expected an error for a deleted message")
+	} else if restErr, ok := err.(*disgord.ErrRest); !ok || restErr.Code != disgordtest.ErrCodeUnknownMessage {
+		t.Errorf("expected error code %d. Got %v", disgordtest.ErrCodeUnknownMessage, err)
+	}
+}
+
+func TestRESTServer_Members(t *testing.T) {
+	srv := disgordtest.NewRESTServer()
+	defer srv.Close()
+
+	guild := srv.AddGuild(disgordtest.Guild{Name: "test"})
+	role, _ := srv.AddRole(guild.ID, disgordtest.Role{Name: "mod"})
+	member := srv.AddMember(guild.ID, disgordtest.Member{User: disgordtest.User{Username: "anders"}})
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	if err := client.AddGuildMemberRole(ctx, guild.ID, member.User.ID, role.ID); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.GetMember(ctx, guild.ID, member.User.ID, disgord.IgnoreCache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Roles) != 1 || got.Roles[0] != role.ID {
+		t.Errorf("expected member to have role %s. Got %+v", role.ID, got.Roles)
+	}
+
+	if err = client.BanMember(ctx, guild.ID, member.User.ID, &disgord.BanMemberParams{Reason: "spam"}); err != nil {
+		t.Fatal(err)
+	}
+	if bans := srv.Bans(guild.ID); len(bans) != 1 || *bans[0].Reason != "spam" {
+		t.Errorf("expected the member to be banned for spam. Got %+v", bans)
+	}
+	if _, ok := srv.Member(guild.ID, member.User.ID); ok {
+		t.Error("expected banned user to no longer be a member")
+	}
+}
+
+func TestRESTServer_Webhook(t *testing.T) {
+	srv := disgordtest.NewRESTServer()
+	defer srv.Close()
+
+	channel := srv.AddChannel(disgordtest.Channel{Name: "general"})
+	webhook := srv.AddWebhook(disgordtest.Webhook{ChannelID: channel.ID, Name: "hook"})
+	client := newClient(t, srv)
+
+	params, _ := disgord.NewExecuteWebhookParams(webhook.ID, webhook.Token)
+	params.Content = "from a webhook"
+	if err := client.ExecuteWebhook(context.Background(), params, false, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := srv.Messages(channel.ID)
+	if len(msgs) != 1 || msgs[0].WebhookID != webhook.ID || msgs[0].Content != "from a webhook" {
+		t.Errorf("expected the webhook message to be stored. Got %+v", msgs)
+	}
+
+	params.Token = "wrong"
+	if err := client.ExecuteWebhook(context.Background(), params, false, ""); err == nil {
+		t.Error("expected an error for an invalid webhook token")
+	}
+}
+
+func TestRESTServer_Unauthorized(t *testing.T) {
+	srv := disgordtest.NewRESTServer()
+	defer srv.Close()
+
+	client, err := disgord.NewClient(disgord.Config{
+		BotToken:    "invalid",
+		RESTBaseURL: srv.URL(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetCurrentUser(context.Background(), disgord.IgnoreCache)
+	if restErr, ok := err.(*disgord.ErrRest); !ok || restErr.HTTPCode != http.StatusUnauthorized {
+		t.Errorf("expected a 401 response. Got %v", err)
+	}
+}
+
+func TestRESTServer_RateLimit(t *testing.T) {
+	srv := disgordtest.NewRESTServer(disgordtest.WithRateLimit(1, 200*time.Millisecond))
+	defer srv.Close()
+
+	channel := srv.AddChannel(disgordtest.Channel{Name: "general"})
+	url := srv.URL() + "/v6/channels/" + channel.ID.String()
+
+	get := func() *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("Authorization", "Bot "+srv.BotToken())
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	if resp := get(); resp.StatusCode != http.StatusOK || resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("expected a 200 response with no remaining requests. Got %d, %s",
+			resp.StatusCode, resp.Header.Get("X-RateLimit-Remaining"))
+	}
+	if resp := get(); resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("expected a 429 response with the Retry-After header. Got %d", resp.StatusCode)
+	}
+
+	// the client must respect the rate limit headers and wait for the bucket to reset
+	time.Sleep(200 * time.Millisecond)
+	client := newClient(t, srv)
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetChannel(context.Background(), channel.ID, disgord.IgnoreCache); err != nil {
+			t.Error(err)
+		}
+	}
+	if requests := srv.Requests(); len(requests) < 3 || requests[0].Path != "/channels/"+channel.ID.String() {
+		t.Errorf("expected the requests to be recorded. Got %+v", requests)
+	}
+}
+
+func TestRESTServer_FailNext(t *testing.T) {
+	srv := disgordtest.NewRESTServer()
+	defer srv.Close()
+
+	channel := srv.AddChannel(disgordtest.Channel{Name: "general"})
+	client := newClient(t, srv)
+
+	srv.FailNext(http.MethodPost, "/channels/"+channel.ID.String()+"/messages", http.StatusForbidden, disgordtest.ErrCodeMissingPermissions, "Missing Permissions")
+	if _, err := client.SendMsg(context.Background(), channel.ID, "hi"); err == nil {
+		t.Error("expected injected error")
+	}
+	if _, err := client.SendMsg(context.Background(), channel.ID, "hi"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRESTServer_Upload(t *testing.T) {
+	srv := disgordtest.NewRESTServer()
+	defer srv.Close()
+
+	boosted := srv.AddGuild(disgordtest.Guild{Name: "boosted", PremiumTier: 2})
+	channel := srv.AddChannel(disgordtest.Channel{GuildID: boosted.ID, Name: "general"})
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	// unknown size, so the client must look up the upload limit of the guild
+	size := int(disgord.UploadLimitDefault) + 1
+	var uploaded int64
+	_, err := client.CreateMessage(ctx, channel.ID, &disgord.CreateMessageParams{
+		Files: []disgord.CreateMessageFileParams{{
+			Reader:   io.LimitReader(bytes.NewReader(make([]byte, size)), int64(size)),
+			FileName: "large.bin",
+			OnProgress: func(n, total int64) {
+				uploaded = n
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uploaded != int64(size) {
+		t.Errorf("expected progress to report %d bytes. Got %d", size, uploaded)
+	}
+
+	// the file size is known, so no upload must happen
+	unboosted := srv.AddGuild(disgordtest.Guild{Name: "unboosted"})
+	hookChannel := srv.AddChannel(disgordtest.Channel{GuildID: unboosted.ID, Name: "hooks"})
+	webhook := srv.AddWebhook(disgordtest.Webhook{GuildID: unboosted.ID, ChannelID: hookChannel.ID, Name: "hook"})
+	params, _ := disgord.NewExecuteWebhookParams(webhook.ID, webhook.Token)
+	params.Files = []disgord.CreateMessageFileParams{
+		{Reader: bytes.NewReader(make([]byte, size)), FileName: "large.bin"},
+	}
+	err = client.ExecuteWebhook(ctx, params, false, "")
+	if _, ok := err.(*disgord.ErrorFileTooLarge); !ok {
+		t.Errorf("expected ErrorFileTooLarge. Got %v", err)
+	}
+
+	params.Files = []disgord.CreateMessageFileParams{
+		{Reader: bytes.NewBufferString("small"), FileName: "small.txt"},
+	}
+	if err = client.ExecuteWebhook(ctx, params, false, ""); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := srv.Messages(hookChannel.ID); len(msgs) != 1 || len(msgs[0].Attachments) != 1 {
+		t.Errorf("expected one message with an attachment. Got %+v", msgs)
+	}
+}
+
+func TestRESTServer_UpdateWebhook(t *testing.T) {
+	srv := disgordtest.NewRESTServer(disgordtest.WithoutRateLimit())
+	defer srv.Close()
+
+	channel := srv.AddChannel(disgordtest.Channel{Name: "general"})
+	webhook := srv.AddWebhook(disgordtest.Webhook{ChannelID: channel.ID, Name: "hook"})
+
+	patch := func(body string) int {
+		req, err := http.NewRequest(http.MethodPatch, srv.URL()+"/v6/webhooks/"+webhook.ID.String(), strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bot "+srv.BotToken())
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := patch(`{"name":"renamed","channel_id":"486833611564253184"}`); status != http.StatusNotFound {
+		t.Errorf("expected an unknown channel. Got %d", status)
+	}
+	if w, _ := srv.Webhook(webhook.ID); w.Name != "hook" {
+		t.Errorf("expected a rejected update not to be applied. Got %s", w.Name)
+	}
+
+	// responses are encoded while the server state is locked
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if status := patch(`{"name":"hook ` + strconv.Itoa(i) + `"}`); status != http.StatusOK {
+				t.Errorf("expected the webhook to be renamed. Got %d", status)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
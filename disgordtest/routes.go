@@ -0,0 +1,1383 @@
+package disgordtest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type handlerFunc func(s *RESTServer, r *request) (status int, resp interface{})
+
+type route struct {
+	method  string
+	pattern string // segments written as "{}" are parameters
+	handler handlerFunc
+
+	// major is true when the first parameter is a major parameter: a channel, guild or webhook id
+	major bool
+
+	// tokenAuth is true for webhook routes authorized by the token in the path
+	tokenAuth bool
+}
+
+var routes []*route
+
+func handle(method, pattern string, handler handlerFunc) *route {
+	rt := &route{
+		method:  method,
+		pattern: pattern,
+		handler: handler,
+		major: strings.HasPrefix(pattern, "/channels/{}") ||
+			strings.HasPrefix(pattern, "/guilds/{}") ||
+			strings.HasPrefix(pattern, "/webhooks/{}"),
+	}
+	routes = append(routes, rt)
+	return rt
+}
+
+// matchRoute finds the route matching the method and path. When the path is known but the method
+// is not supported, the route is nil while params is not.
+func matchRoute(method, path string) (match *route, params []string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, rt := range routes {
+		patternSegments := strings.Split(strings.Trim(rt.pattern, "/"), "/")
+		if len(patternSegments) != len(segments) {
+			continue
+		}
+
+		p := []string{}
+		for i := range segments {
+			if patternSegments[i] == "{}" {
+				p = append(p, segments[i])
+			} else if patternSegments[i] != segments[i] {
+				p = nil
+				break
+			}
+		}
+		if p == nil {
+			continue
+		}
+		if rt.method == method {
+			return rt, p
+		}
+		params = []string{}
+	}
+	return nil, params
+}
+
+func init() {
+	handle(http.MethodGet, "/gateway", getGateway)
+	handle(http.MethodGet, "/gateway/bot", getGatewayBot)
+
+	// users
+	handle(http.MethodGet, "/users/@me", getCurrentUser)
+	handle(http.MethodGet, "/users/@me/guilds", getCurrentUserGuilds)
+	handle(http.MethodDelete, "/users/@me/guilds/{}", leaveGuild)
+	handle(http.MethodPost, "/users/@me/channels", createDM)
+	handle(http.MethodGet, "/users/{}", getUser)
+
+	// guilds
+	handle(http.MethodGet, "/guilds/{}", getGuild)
+	handle(http.MethodPatch, "/guilds/{}", updateGuild)
+	handle(http.MethodDelete, "/guilds/{}", deleteGuild)
+	handle(http.MethodGet, "/guilds/{}/channels", getGuildChannels)
+	handle(http.MethodPost, "/guilds/{}/channels", createGuildChannel)
+	handle(http.MethodGet, "/guilds/{}/members", getMembers)
+	handle(http.MethodPatch, "/guilds/{}/members/@me/nick", updateCurrentNick)
+	handle(http.MethodGet, "/guilds/{}/members/{}", getMember)
+	handle(http.MethodPatch, "/guilds/{}/members/{}", updateMember)
+	handle(http.MethodDelete, "/guilds/{}/members/{}", kickMember)
+	handle(http.MethodPut, "/guilds/{}/members/{}/roles/{}", addMemberRole)
+	handle(http.MethodDelete, "/guilds/{}/members/{}/roles/{}", removeMemberRole)
+	handle(http.MethodGet, "/guilds/{}/bans", getBans)
+	handle(http.MethodGet, "/guilds/{}/bans/{}", getBan)
+	handle(http.MethodPut, "/guilds/{}/bans/{}", createBan)
+	handle(http.MethodDelete, "/guilds/{}/bans/{}", removeBan)
+	handle(http.MethodGet, "/guilds/{}/roles", getRoles)
+	handle(http.MethodPost, "/guilds/{}/roles", createRole)
+	handle(http.MethodPatch, "/guilds/{}/roles/{}", updateRole)
+	handle(http.MethodDelete, "/guilds/{}/roles/{}", deleteRole)
+	handle(http.MethodGet, "/guilds/{}/webhooks", getGuildWebhooks)
+
+	// channels
+	handle(http.MethodGet, "/channels/{}", getChannel)
+	handle(http.MethodPatch, "/channels/{}", updateChannel)
+	handle(http.MethodDelete, "/channels/{}", deleteChannel)
+	handle(http.MethodPost, "/channels/{}/typing", triggerTyping)
+	handle(http.MethodGet, "/channels/{}/messages", getMessages)
+	handle(http.MethodPost, "/channels/{}/messages", createMessage)
+	handle(http.MethodPost, "/channels/{}/messages/bulk-delete", bulkDeleteMessages)
+	handle(http.MethodGet, "/channels/{}/messages/{}", getMessage)
+	handle(http.MethodPatch, "/channels/{}/messages/{}", updateMessage)
+	handle(http.MethodDelete, "/channels/{}/messages/{}", deleteMessage)
+	handle(http.MethodDelete, "/channels/{}/messages/{}/reactions", deleteAllReactions)
+	handle(http.MethodGet, "/channels/{}/messages/{}/reactions/{}", getReactions)
+	handle(http.MethodPut, "/channels/{}/messages/{}/reactions/{}/@me", createReaction)
+	handle(http.MethodDelete, "/channels/{}/messages/{}/reactions/{}/@me", deleteOwnReaction)
+	handle(http.MethodDelete, "/channels/{}/messages/{}/reactions/{}/{}", deleteUserReaction)
+	handle(http.MethodGet, "/channels/{}/pins", getPins)
+	handle(http.MethodPut, "/channels/{}/pins/{}", addPin)
+	handle(http.MethodDelete, "/channels/{}/pins/{}", deletePin)
+	handle(http.MethodGet, "/channels/{}/webhooks", getChannelWebhooks)
+	handle(http.MethodPost, "/channels/{}/webhooks", createWebhook)
+
+	// webhooks
+	handle(http.MethodGet, "/webhooks/{}", getWebhook)
+	handle(http.MethodPatch, "/webhooks/{}", updateWebhook)
+	handle(http.MethodDelete, "/webhooks/{}", deleteWebhook)
+	handle(http.MethodGet, "/webhooks/{}/{}", getWebhook).tokenAuth = true
+	handle(http.MethodPatch, "/webhooks/{}/{}", updateWebhook).tokenAuth = true
+	handle(http.MethodDelete, "/webhooks/{}/{}", deleteWebhook).tokenAuth = true
+	handle(http.MethodPost, "/webhooks/{}/{}", executeWebhook).tokenAuth = true
+	handle(http.MethodPost, "/webhooks/{}/{}/slack", executeWebhook).tokenAuth = true
+	handle(http.MethodPost, "/webhooks/{}/{}/github", executeWebhook).tokenAuth = true
+	handle(http.MethodPatch, "/webhooks/{}/{}/messages/{}", updateWebhookMessage).tokenAuth = true
+	handle(http.MethodDelete, "/webhooks/{}/{}/messages/{}", deleteWebhookMessage).tokenAuth = true
+}
+
+//////////////////////////////////////////////////////
+//
+// GATEWAY & USERS
+//
+//////////////////////////////////////////////////////
+
+func getGateway(s *RESTServer, r *request) (int, interface{}) {
+	return http.StatusOK, map[string]interface{}{"url": s.gatewayURL}
+}
+
+func getGatewayBot(s *RESTServer, r *request) (int, interface{}) {
+	return http.StatusOK, map[string]interface{}{
+		"url":    s.gatewayURL,
+		"shards": s.shards,
+		"session_start_limit": map[string]interface{}{
+			"total":       1000,
+			"remaining":   1000,
+			"reset_after": int64(24 * time.Hour / time.Millisecond),
+		},
+	}
+}
+
+func getCurrentUser(s *RESTServer, r *request) (int, interface{}) {
+	return http.StatusOK, s.bot
+}
+
+func getUser(s *RESTServer, r *request) (int, interface{}) {
+	user, ok := s.users[r.snowflake(0)]
+	if !ok {
+		return 0, errUnknownUser
+	}
+	return http.StatusOK, user
+}
+
+func getCurrentUserGuilds(s *RESTServer, r *request) (int, interface{}) {
+	type partialGuild struct {
+		ID    Snowflake `json:"id"`
+		Name  string    `json:"name"`
+		Icon  *string   `json:"icon"`
+		Owner bool      `json:"owner"`
+	}
+
+	guilds := []partialGuild{}
+	for id, g := range s.guilds {
+		if _, member := s.members[id][s.bot.ID]; !member {
+			continue
+		}
+		guilds = append(guilds, partialGuild{
+			ID:    g.ID,
+			Name:  g.Name,
+			Icon:  g.Icon,
+			Owner: g.OwnerID == s.bot.ID,
+		})
+	}
+	sort.Slice(guilds, func(i, j int) bool {
+		return guilds[i].ID < guilds[j].ID
+	})
+	return http.StatusOK, guilds
+}
+
+func leaveGuild(s *RESTServer, r *request) (int, interface{}) {
+	guildID := r.snowflake(0)
+	if _, ok := s.members[guildID][s.bot.ID]; !ok {
+		return 0, errUnknownGuild
+	}
+	delete(s.members[guildID], s.bot.ID)
+	s.updateMemberCount(guildID)
+	return http.StatusNoContent, nil
+}
+
+func createDM(s *RESTServer, r *request) (int, interface{}) {
+	var body struct {
+		RecipientID Snowflake `json:"recipient_id"`
+	}
+	if err := r.decode(&body); err != nil {
+		return 0, err
+	}
+	recipient, ok := s.users[body.RecipientID]
+	if !ok {
+		return 0, errUnknownUser
+	}
+
+	for _, c := range s.channels {
+		if c.Type == ChannelTypeDM && len(c.Recipients) == 1 && c.Recipients[0].ID == recipient.ID {
+			return http.StatusOK, c
+		}
+	}
+	channel := s.addChannel(Channel{
+		Type:       ChannelTypeDM,
+		Recipients: []User{*recipient},
+	})
+	return http.StatusOK, channel
+}
+
+//////////////////////////////////////////////////////
+//
+// GUILDS
+//
+//////////////////////////////////////////////////////
+
+func (s *RESTServer) guildFromPath(r *request) (*Guild, *APIError) {
+	g, ok := s.guilds[r.snowflake(0)]
+	if !ok {
+		return nil, errUnknownGuild
+	}
+	if _, member := s.members[g.ID][s.bot.ID]; !member {
+		return nil, newAPIError(http.StatusForbidden, ErrCodeMissingAccess, "Missing Access")
+	}
+	return g, nil
+}
+
+func getGuild(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	return http.StatusOK, g
+}
+
+func updateGuild(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	var body struct {
+		Name   *string `json:"name"`
+		Region *string `json:"region"`
+	}
+	if err := r.decode(&body); err != nil {
+		return 0, err
+	}
+	if body.Name != nil {
+		g.Name = *body.Name
+	}
+	if body.Region != nil {
+		g.Region = *body.Region
+	}
+	return http.StatusOK, g
+}
+
+func deleteGuild(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	if g.OwnerID != s.bot.ID {
+		return 0, newAPIError(http.StatusForbidden, ErrCodeMissingPermissions, "Missing Permissions")
+	}
+	for id, c := range s.channels {
+		if c.GuildID == g.ID {
+			delete(s.channels, id)
+			delete(s.messages, id)
+		}
+	}
+	delete(s.guilds, g.ID)
+	delete(s.members, g.ID)
+	delete(s.bans, g.ID)
+	return http.StatusNoContent, nil
+}
+
+func getGuildChannels(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	channels := []*Channel{}
+	for _, c := range s.channels {
+		if c.GuildID == g.ID {
+			channels = append(channels, c)
+		}
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].ID < channels[j].ID
+	})
+	return http.StatusOK, channels
+}
+
+func createGuildChannel(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	var channel Channel
+	if err := r.decode(&channel); err != nil {
+		return 0, err
+	}
+	if channel.Name == "" {
+		return 0, errInvalidBody
+	}
+	channel.ID = 0
+	channel.GuildID = g.ID
+	return http.StatusCreated, s.addChannel(channel)
+}
+
+func (s *RESTServer) memberFromPath(r *request) (*Member, *APIError) {
+	if _, err := s.guildFromPath(r); err != nil {
+		return nil, err
+	}
+	m, ok := s.members[r.snowflake(0)][r.snowflake(1)]
+	if !ok {
+		return nil, errUnknownMember
+	}
+	return m, nil
+}
+
+func getMembers(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	limit := 1
+	if l, convErr := strconv.Atoi(r.URL.Query().Get("limit")); convErr == nil {
+		limit = l
+	}
+	if limit < 1 || limit > 1000 {
+		return 0, errInvalidBody
+	}
+	after, _ := strconv.ParseUint(r.URL.Query().Get("after"), 10, 64)
+
+	members := []*Member{}
+	for id, m := range s.members[g.ID] {
+		if uint64(id) > after {
+			members = append(members, m)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].User.ID < members[j].User.ID
+	})
+	if len(members) > limit {
+		members = members[:limit]
+	}
+	return http.StatusOK, members
+}
+
+func getMember(s *RESTServer, r *request) (int, interface{}) {
+	m, err := s.memberFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	return http.StatusOK, m
+}
+
+func (s *RESTServer) hasRole(guildID, roleID Snowflake) bool {
+	for _, role := range s.guilds[guildID].Roles {
+		if role.ID == roleID {
+			return true
+		}
+	}
+	return false
+}
+
+func updateMember(s *RESTServer, r *request) (int, interface{}) {
+	m, err := s.memberFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	var body struct {
+		Nick  *string      `json:"nick"`
+		Roles *[]Snowflake `json:"roles"`
+		Mute  *bool        `json:"mute"`
+		Deaf  *bool        `json:"deaf"`
+	}
+	if err := r.decode(&body); err != nil {
+		return 0, err
+	}
+	if body.Roles != nil {
+		for _, roleID := range *body.Roles {
+			if !s.hasRole(m.GuildID, roleID) {
+				return 0, errUnknownRole
+			}
+		}
+		m.Roles = append([]Snowflake{}, *body.Roles...)
+	}
+	if body.Nick != nil {
+		m.Nick = *body.Nick
+	}
+	if body.Mute != nil {
+		m.Mute = *body.Mute
+	}
+	if body.Deaf != nil {
+		m.Deaf = *body.Deaf
+	}
+	return http.StatusNoContent, nil
+}
+
+func updateCurrentNick(s *RESTServer, r *request) (int, interface{}) {
+	if _, err := s.guildFromPath(r); err != nil {
+		return 0, err
+	}
+	var body struct {
+		Nick string `json:"nick"`
+	}
+	if err := r.decode(&body); err != nil {
+		return 0, err
+	}
+	s.members[r.snowflake(0)][s.bot.ID].Nick = body.Nick
+	return http.StatusOK, body
+}
+
+func kickMember(s *RESTServer, r *request) (int, interface{}) {
+	m, err := s.memberFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	delete(s.members[m.GuildID], m.User.ID)
+	s.updateMemberCount(m.GuildID)
+	return http.StatusNoContent, nil
+}
+
+func addMemberRole(s *RESTServer, r *request) (int, interface{}) {
+	m, err := s.memberFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	roleID := r.snowflake(2)
+	if !s.hasRole(m.GuildID, roleID) {
+		return 0, errUnknownRole
+	}
+	for _, id := range m.Roles {
+		if id == roleID {
+			return http.StatusNoContent, nil
+		}
+	}
+	m.Roles = append(m.Roles, roleID)
+	return http.StatusNoContent, nil
+}
+
+func removeMemberRole(s *RESTServer, r *request) (int, interface{}) {
+	m, err := s.memberFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	roleID := r.snowflake(2)
+	if !s.hasRole(m.GuildID, roleID) {
+		return 0, errUnknownRole
+	}
+	for i := range m.Roles {
+		if m.Roles[i] == roleID {
+			m.Roles = append(m.Roles[:i], m.Roles[i+1:]...)
+			break
+		}
+	}
+	return http.StatusNoContent, nil
+}
+
+func getBans(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	bans := []*Ban{}
+	for _, ban := range s.bans[g.ID] {
+		bans = append(bans, ban)
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].User.ID < bans[j].User.ID
+	})
+	return http.StatusOK, bans
+}
+
+func getBan(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	ban, ok := s.bans[g.ID][r.snowflake(1)]
+	if !ok {
+		return 0, errUnknownBan
+	}
+	return http.StatusOK, ban
+}
+
+func createBan(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	user, ok := s.users[r.snowflake(1)]
+	if !ok {
+		return 0, errUnknownUser
+	}
+
+	// API v6 uses query parameters, while later versions reads the JSON body
+	var body struct {
+		DeleteMessageDays int    `json:"delete_message_days"`
+		Reason            string `json:"reason"`
+	}
+	if err := r.decode(&body); err != nil {
+		return 0, err
+	}
+	if reason := r.URL.Query().Get("reason"); reason != "" {
+		body.Reason = reason
+	}
+	if days := r.URL.Query().Get("delete_message_days"); days != "" {
+		body.DeleteMessageDays, _ = strconv.Atoi(days)
+	}
+	if body.DeleteMessageDays < 0 || body.DeleteMessageDays > 7 {
+		return 0, errInvalidBody
+	}
+
+	ban := &Ban{User: *user}
+	if body.Reason != "" {
+		reason := body.Reason
+		ban.Reason = &reason
+	}
+	s.bans[g.ID][user.ID] = ban
+	delete(s.members[g.ID], user.ID)
+	s.updateMemberCount(g.ID)
+	return http.StatusNoContent, nil
+}
+
+func removeBan(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := s.bans[g.ID][r.snowflake(1)]; !ok {
+		return 0, errUnknownBan
+	}
+	delete(s.bans[g.ID], r.snowflake(1))
+	return http.StatusNoContent, nil
+}
+
+func getRoles(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	return http.StatusOK, g.Roles
+}
+
+// rolePayload accepts permissions both as an integer and as a string, as the latter is
+// used by newer API versions.
+type rolePayload struct {
+	Name        *string      `json:"name"`
+	Permissions *json.Number `json:"permissions"`
+	Color       *uint        `json:"color"`
+	Hoist       *bool        `json:"hoist"`
+	Mentionable *bool        `json:"mentionable"`
+}
+
+func (p *rolePayload) apply(role *Role) *APIError {
+	if p.Name != nil {
+		role.Name = *p.Name
+	}
+	if p.Permissions != nil {
+		permissions, err := strconv.ParseUint(p.Permissions.String(), 10, 64)
+		if err != nil {
+			return errInvalidBody
+		}
+		role.Permissions = permissions
+	}
+	if p.Color != nil {
+		role.Color = *p.Color
+	}
+	if p.Hoist != nil {
+		role.Hoist = *p.Hoist
+	}
+	if p.Mentionable != nil {
+		role.Mentionable = *p.Mentionable
+	}
+	return nil
+}
+
+func createRole(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	var body rolePayload
+	if err := r.decode(&body); err != nil {
+		return 0, err
+	}
+	role := Role{
+		ID:       NewSnowflake(),
+		Name:     "new role",
+		Position: len(g.Roles),
+	}
+	if err := body.apply(&role); err != nil {
+		return 0, err
+	}
+	g.Roles = append(g.Roles, role)
+	return http.StatusOK, role
+}
+
+func updateRole(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	var body rolePayload
+	if err := r.decode(&body); err != nil {
+		return 0, err
+	}
+	for i := range g.Roles {
+		if g.Roles[i].ID == r.snowflake(1) {
+			if err := body.apply(&g.Roles[i]); err != nil {
+				return 0, err
+			}
+			return http.StatusOK, g.Roles[i]
+		}
+	}
+	return 0, errUnknownRole
+}
+
+func deleteRole(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	roleID := r.snowflake(1)
+	if roleID == g.ID {
+		return 0, errInvalidBody // @everyone can not be deleted
+	}
+	for i := range g.Roles {
+		if g.Roles[i].ID != roleID {
+			continue
+		}
+		g.Roles = append(g.Roles[:i], g.Roles[i+1:]...)
+		for _, m := range s.members[g.ID] {
+			for j := range m.Roles {
+				if m.Roles[j] == roleID {
+					m.Roles = append(m.Roles[:j], m.Roles[j+1:]...)
+					break
+				}
+			}
+		}
+		return http.StatusNoContent, nil
+	}
+	return 0, errUnknownRole
+}
+
+func getGuildWebhooks(s *RESTServer, r *request) (int, interface{}) {
+	g, err := s.guildFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	return http.StatusOK, s.webhooksWhere(func(w *Webhook) bool {
+		return w.GuildID == g.ID
+	})
+}
+
+//////////////////////////////////////////////////////
+//
+// CHANNELS
+//
+//////////////////////////////////////////////////////
+
+func (s *RESTServer) channelFromPath(r *request) (*Channel, *APIError) {
+	c, ok := s.channels[r.snowflake(0)]
+	if !ok {
+		return nil, errUnknownChannel
+	}
+	return c, nil
+}
+
+func getChannel(s *RESTServer, r *request) (int, interface{}) {
+	c, err := s.channelFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	return http.StatusOK, c
+}
+
+func updateChannel(s *RESTServer, r *request) (int, interface{}) {
+	c, err := s.channelFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	var body struct {
+		Name                 *string                `json:"name"`
+		Topic                *string                `json:"topic"`
+		NSFW                 *bool                  `json:"nsfw"`
+		Position             *int                   `json:"position"`
+		RateLimitPerUser     *uint                  `json:"rate_limit_per_user"`
+		PermissionOverwrites *[]PermissionOverwrite `json:"permission_overwrites"`
+		ParentID             *Snowflake             `json:"parent_id"`
+	}
+	if err := r.decode(&body); err != nil {
+		return 0, err
+	}
+	if body.Name != nil {
+		c.Name = *body.Name
+	}
+	if body.Topic != nil {
+		c.Topic = *body.Topic
+	}
+	if body.NSFW != nil {
+		c.NSFW = *body.NSFW
+	}
+	if body.Position != nil {
+		c.Position = *body.Position
+	}
+	if body.RateLimitPerUser != nil {
+		c.RateLimitPerUser = *body.RateLimitPerUser
+	}
+	if body.PermissionOverwrites != nil {
+		c.PermissionOverwrites = *body.PermissionOverwrites
+	}
+	if body.ParentID != nil {
+		c.ParentID = *body.ParentID
+	}
+	return http.StatusOK, c
+}
+
+func deleteChannel(s *RESTServer, r *request) (int, interface{}) {
+	c, err := s.channelFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	delete(s.channels, c.ID)
+	delete(s.messages, c.ID)
+	delete(s.pins, c.ID)
+	return http.StatusOK, c
+}
+
+func triggerTyping(s *RESTServer, r *request) (int, interface{}) {
+	if _, err := s.channelFromPath(r); err != nil {
+		return 0, err
+	}
+	return http.StatusNoContent, nil
+}
+
+func getMessages(s *RESTServer, r *request) (int, interface{}) {
+	c, err := s.channelFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	query := r.URL.Query()
+	limit := 50
+	if l, convErr := strconv.Atoi(query.Get("limit")); convErr == nil {
+		limit = l
+	}
+	if limit < 1 || limit > 100 {
+		return 0, errInvalidBody
+	}
+	before, _ := strconv.ParseUint(query.Get("before"), 10, 64)
+	after, _ := strconv.ParseUint(query.Get("after"), 10, 64)
+
+	// newest first, like Discord
+	msgs := []Message{}
+	all := s.messages[c.ID]
+	for i := len(all) - 1; i >= 0; i-- {
+		id := uint64(all[i].ID)
+		if (before != 0 && id >= before) || id <= after {
+			continue
+		}
+		msgs = append(msgs, messageCopy(all[i]))
+	}
+	if len(msgs) > limit {
+		if after != 0 {
+			msgs = msgs[len(msgs)-limit:]
+		} else {
+			msgs = msgs[:limit]
+		}
+	}
+	return http.StatusOK, msgs
+}
+
+func (s *RESTServer) messageFromPath(r *request) (msg *Message, index int, err *APIError) {
+	c, err := s.channelFromPath(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	id := r.snowflake(1)
+	for i, m := range s.messages[c.ID] {
+		if m.ID == id {
+			return m, i, nil
+		}
+	}
+	return nil, 0, errUnknownMessage
+}
+
+func getMessage(s *RESTServer, r *request) (int, interface{}) {
+	msg, _, err := s.messageFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	return http.StatusOK, messageCopy(msg)
+}
+
+// messagePayload is the JSON body for creating messages and executing webhooks
+type messagePayload struct {
+	Content   *string           `json:"content"`
+	Nonce     json.RawMessage   `json:"nonce"`
+	Tts       bool              `json:"tts"`
+	Embed     json.RawMessage   `json:"embed"`
+	Embeds    []json.RawMessage `json:"embeds"`
+	Username  string            `json:"username"`
+	AvatarURL string            `json:"avatar_url"`
+
+	// slack compatible webhooks
+	Text string `json:"text"`
+}
+
+// parseMessage extracts the message payload and attachments of JSON and multipart bodies
+func (r *request) parseMessage() (payload *messagePayload, attachments []Attachment, err *APIError) {
+	payload = &messagePayload{}
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return payload, nil, r.decode(payload)
+	}
+
+	mr := multipart.NewReader(strings.NewReader(string(r.body)), params["boundary"])
+	for {
+		part, partErr := mr.NextPart()
+		if partErr != nil {
+			break
+		}
+		data, readErr := ioutil.ReadAll(part)
+		if readErr != nil {
+			return nil, nil, errInvalidBody
+		}
+		if part.FileName() == "" {
+			if part.FormName() == "payload_json" {
+				if jsonErr := json.Unmarshal(data, payload); jsonErr != nil {
+					return nil, nil, errInvalidBody
+				}
+			}
+			continue
+		}
+
+		id := NewSnowflake()
+		url := "https://cdn.discordapp.com/attachments/" + r.params[0] + "/" + id.String() + "/" + part.FileName()
+		attachments = append(attachments, Attachment{
+			ID:       id,
+			Filename: part.FileName(),
+			Size:     len(data),
+			URL:      url,
+			ProxyURL: strings.Replace(url, "cdn.discordapp.com", "media.discordapp.net", 1),
+			Data:     data,
+		})
+	}
+	return payload, attachments, nil
+}
+
+func (p *messagePayload) toMessage(channelID Snowflake, author User, attachments []Attachment) (*Message, *APIError) {
+	msg := &Message{
+		ChannelID:   channelID,
+		Author:      author,
+		Tts:         p.Tts,
+		Attachments: attachments,
+	}
+	if p.Content != nil {
+		msg.Content = *p.Content
+	} else {
+		msg.Content = p.Text
+	}
+	if len(p.Nonce) > 0 {
+		var nonce string
+		if json.Unmarshal(p.Nonce, &nonce) != nil {
+			nonce = string(p.Nonce)
+		}
+		msg.Nonce = nonce
+	}
+	if len(p.Embed) > 0 && string(p.Embed) != "null" {
+		msg.Embeds = append(msg.Embeds, p.Embed)
+	}
+	msg.Embeds = append(msg.Embeds, p.Embeds...)
+
+	if msg.Content == "" && len(msg.Embeds) == 0 && len(msg.Attachments) == 0 {
+		return nil, errEmptyMessage
+	}
+	if len(msg.Content) > 2000 {
+		return nil, errInvalidBody
+	}
+	msg.MentionEveryone = strings.Contains(msg.Content, "@everyone") || strings.Contains(msg.Content, "@here")
+	return msg, nil
+}
+
+func createMessage(s *RESTServer, r *request) (int, interface{}) {
+	c, err := s.channelFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	payload, attachments, err := r.parseMessage()
+	if err != nil {
+		return 0, err
+	}
+	msg, err := payload.toMessage(c.ID, s.bot, attachments)
+	if err != nil {
+		return 0, err
+	}
+	return http.StatusOK, messageCopy(s.addMessage(msg))
+}
+
+func updateMessage(s *RESTServer, r *request) (int, interface{}) {
+	msg, _, err := s.messageFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	if msg.Author.ID != s.bot.ID {
+		return 0, errNotAuthor
+	}
+	if err := s.editMessage(msg, r); err != nil {
+		return 0, err
+	}
+	return http.StatusOK, messageCopy(msg)
+}
+
+func (s *RESTServer) editMessage(msg *Message, r *request) *APIError {
+	var body struct {
+		Content *string            `json:"content"`
+		Embed   json.RawMessage    `json:"embed"`
+		Embeds  *[]json.RawMessage `json:"embeds"`
+	}
+	if err := r.decode(&body); err != nil {
+		return err
+	}
+	if body.Content != nil {
+		msg.Content = *body.Content
+	}
+	if len(body.Embed) > 0 {
+		if string(body.Embed) == "null" {
+			msg.Embeds = []json.RawMessage{}
+		} else {
+			msg.Embeds = []json.RawMessage{body.Embed}
+		}
+	}
+	if body.Embeds != nil {
+		msg.Embeds = *body.Embeds
+	}
+	edited := Timestamp(time.Now())
+	msg.EditedTimestamp = &edited
+	return nil
+}
+
+func (s *RESTServer) removeMessage(channelID Snowflake, index int) {
+	msgs := s.messages[channelID]
+	id := msgs[index].ID
+	s.messages[channelID] = append(msgs[:index], msgs[index+1:]...)
+
+	pins := s.pins[channelID]
+	for i := range pins {
+		if pins[i] == id {
+			s.pins[channelID] = append(pins[:i], pins[i+1:]...)
+			break
+		}
+	}
+}
+
+func deleteMessage(s *RESTServer, r *request) (int, interface{}) {
+	msg, i, err := s.messageFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	s.removeMessage(msg.ChannelID, i)
+	return http.StatusNoContent, nil
+}
+
+func bulkDeleteMessages(s *RESTServer, r *request) (int, interface{}) {
+	c, err := s.channelFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	var body struct {
+		Messages []Snowflake `json:"messages"`
+	}
+	if err := r.decode(&body); err != nil {
+		return 0, err
+	}
+	if len(body.Messages) < 2 || len(body.Messages) > 100 {
+		return 0, newAPIError(http.StatusBadRequest, ErrCodeTooManyBulkDeleteMsgs, "You can only bulk delete messages between 2 and 100")
+	}
+	for _, id := range body.Messages {
+		for i, m := range s.messages[c.ID] {
+			if m.ID == id {
+				s.removeMessage(c.ID, i)
+				break
+			}
+		}
+	}
+	return http.StatusNoContent, nil
+}
+
+// parseEmoji handles both unicode emojis and custom emojis in the name:id format
+func parseEmoji(code string) Emoji {
+	emoji := Emoji{Name: code}
+	if i := strings.LastIndex(code, ":"); i >= 0 {
+		emoji.Name = code[:i]
+		id, _ := strconv.ParseUint(code[i+1:], 10, 64)
+		emoji.ID = Snowflake(id)
+	}
+	return emoji
+}
+
+func sameEmoji(a, b Emoji) bool {
+	if !a.ID.IsZero() || !b.ID.IsZero() {
+		return a.ID == b.ID
+	}
+	return a.Name == b.Name
+}
+
+func (msg *Message) reaction(emoji Emoji) (int, *Reaction) {
+	for i := range msg.Reactions {
+		if sameEmoji(msg.Reactions[i].Emoji, emoji) {
+			return i, &msg.Reactions[i]
+		}
+	}
+	return -1, nil
+}
+
+func (s *RESTServer) react(msg *Message, emoji Emoji, userID Snowflake) {
+	_, reaction := msg.reaction(emoji)
+	if reaction == nil {
+		msg.Reactions = append(msg.Reactions, Reaction{Emoji: emoji})
+		reaction = &msg.Reactions[len(msg.Reactions)-1]
+	}
+	for _, id := range reaction.users {
+		if id == userID {
+			return
+		}
+	}
+	reaction.users = append(reaction.users, userID)
+	reaction.Count++
+	reaction.Me = reaction.Me || userID == s.bot.ID
+}
+
+func (s *RESTServer) unreact(msg *Message, emoji Emoji, userID Snowflake) {
+	i, reaction := msg.reaction(emoji)
+	if reaction == nil {
+		return
+	}
+	for j, id := range reaction.users {
+		if id != userID {
+			continue
+		}
+		reaction.users = append(reaction.users[:j], reaction.users[j+1:]...)
+		reaction.Count--
+		if userID == s.bot.ID {
+			reaction.Me = false
+		}
+		break
+	}
+	if reaction.Count == 0 {
+		msg.Reactions = append(msg.Reactions[:i], msg.Reactions[i+1:]...)
+	}
+}
+
+func createReaction(s *RESTServer, r *request) (int, interface{}) {
+	msg, _, err := s.messageFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	emoji := parseEmoji(r.params[2])
+	if !emoji.ID.IsZero() && !s.knownEmoji(emoji.ID) {
+		return 0, errUnknownEmoji
+	}
+	s.react(msg, emoji, s.bot.ID)
+	return http.StatusNoContent, nil
+}
+
+func (s *RESTServer) knownEmoji(emojiID Snowflake) bool {
+	for _, g := range s.guilds {
+		for _, e := range g.Emojis {
+			if e.ID == emojiID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func deleteOwnReaction(s *RESTServer, r *request) (int, interface{}) {
+	msg, _, err := s.messageFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	s.unreact(msg, parseEmoji(r.params[2]), s.bot.ID)
+	return http.StatusNoContent, nil
+}
+
+func deleteUserReaction(s *RESTServer, r *request) (int, interface{}) {
+	msg, _, err := s.messageFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	s.unreact(msg, parseEmoji(r.params[2]), r.snowflake(3))
+	return http.StatusNoContent, nil
+}
+
+func deleteAllReactions(s *RESTServer, r *request) (int, interface{}) {
+	msg, _, err := s.messageFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	msg.Reactions = nil
+	return http.StatusNoContent, nil
+}
+
+func getReactions(s *RESTServer, r *request) (int, interface{}) {
+	msg, _, err := s.messageFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	users := []User{}
+	if _, reaction := msg.reaction(parseEmoji(r.params[2])); reaction != nil {
+		for _, id := range reaction.users {
+			if user, ok := s.users[id]; ok {
+				users = append(users, *user)
+			}
+		}
+	}
+	return http.StatusOK, users
+}
+
+func getPins(s *RESTServer, r *request) (int, interface{}) {
+	c, err := s.channelFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	pinned := []Message{}
+	for _, m := range s.messages[c.ID] {
+		if m.Pinned {
+			pinned = append(pinned, messageCopy(m))
+		}
+	}
+	return http.StatusOK, pinned
+}
+
+func addPin(s *RESTServer, r *request) (int, interface{}) {
+	msg, _, err := s.messageFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	if !msg.Pinned {
+		msg.Pinned = true
+		s.pins[msg.ChannelID] = append(s.pins[msg.ChannelID], msg.ID)
+	}
+	return http.StatusNoContent, nil
+}
+
+func deletePin(s *RESTServer, r *request) (int, interface{}) {
+	msg, _, err := s.messageFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	msg.Pinned = false
+	pins := s.pins[msg.ChannelID]
+	for i := range pins {
+		if pins[i] == msg.ID {
+			s.pins[msg.ChannelID] = append(pins[:i], pins[i+1:]...)
+			break
+		}
+	}
+	return http.StatusNoContent, nil
+}
+
+//////////////////////////////////////////////////////
+//
+// WEBHOOKS
+//
+//////////////////////////////////////////////////////
+
+func (s *RESTServer) webhooksWhere(filter func(w *Webhook) bool) []*Webhook {
+	webhooks := []*Webhook{}
+	for _, w := range s.webhooks {
+		if filter(w) {
+			webhooks = append(webhooks, w)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].ID < webhooks[j].ID
+	})
+	return webhooks
+}
+
+func getChannelWebhooks(s *RESTServer, r *request) (int, interface{}) {
+	c, err := s.channelFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	return http.StatusOK, s.webhooksWhere(func(w *Webhook) bool {
+		return w.ChannelID == c.ID
+	})
+}
+
+func createWebhook(s *RESTServer, r *request) (int, interface{}) {
+	c, err := s.channelFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	var body struct {
+		Name   string  `json:"name"`
+		Avatar *string `json:"avatar"`
+	}
+	if err := r.decode(&body); err != nil {
+		return 0, err
+	}
+	if len(body.Name) < 2 || len(body.Name) > 32 {
+		return 0, errInvalidBody
+	}
+	bot := s.bot
+	return http.StatusOK, s.addWebhook(Webhook{
+		ChannelID: c.ID,
+		Name:      body.Name,
+		Avatar:    body.Avatar,
+		User:      &bot,
+	})
+}
+
+// webhookFromPath looks up the webhook and validates the token when one is in the path
+func (s *RESTServer) webhookFromPath(r *request) (*Webhook, *APIError) {
+	w, ok := s.webhooks[r.snowflake(0)]
+	if !ok {
+		return nil, errUnknownWebhook
+	}
+	if len(r.params) > 1 && w.Token != r.params[1] {
+		return nil, errWebhookToken
+	}
+	return w, nil
+}
+
+func getWebhook(s *RESTServer, r *request) (int, interface{}) {
+	w, err := s.webhookFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	if len(r.params) > 1 {
+		// the token endpoints does not return the user object
+		cp := *w
+		cp.User = nil
+		return http.StatusOK, cp
+	}
+	return http.StatusOK, w
+}
+
+func updateWebhook(s *RESTServer, r *request) (int, interface{}) {
+	w, err := s.webhookFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	var body struct {
+		Name      *string    `json:"name"`
+		Avatar    *string    `json:"avatar"`
+		ChannelID *Snowflake `json:"channel_id"`
+	}
+	if err := r.decode(&body); err != nil {
+		return 0, err
+	}
+	if body.ChannelID != nil {
+		if len(r.params) > 1 {
+			return 0, errInvalidBody // can not move a webhook using the token
+		}
+		if _, ok := s.channels[*body.ChannelID]; !ok {
+			return 0, errUnknownChannel
+		}
+	}
+
+	if body.Name != nil {
+		w.Name = *body.Name
+	}
+	if body.Avatar != nil {
+		w.Avatar = body.Avatar
+	}
+	if body.ChannelID != nil {
+		w.ChannelID = *body.ChannelID
+	}
+	return http.StatusOK, w
+}
+
+func deleteWebhook(s *RESTServer, r *request) (int, interface{}) {
+	w, err := s.webhookFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	delete(s.webhooks, w.ID)
+	return http.StatusNoContent, nil
+}
+
+func executeWebhook(s *RESTServer, r *request) (int, interface{}) {
+	w, err := s.webhookFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	payload, attachments, err := r.parseMessage()
+	if err != nil {
+		return 0, err
+	}
+
+	author := User{
+		ID:            w.ID,
+		Username:      w.Name,
+		Discriminator: "0000",
+		Avatar:        w.Avatar,
+		Bot:           true,
+	}
+	if payload.Username != "" {
+		author.Username = payload.Username
+	}
+	msg, err := payload.toMessage(w.ChannelID, author, attachments)
+	if err != nil {
+		return 0, err
+	}
+	msg.WebhookID = w.ID
+	msg = s.addMessage(msg)
+
+	if wait, _ := strconv.ParseBool(r.URL.Query().Get("wait")); wait {
+		return http.StatusOK, messageCopy(msg)
+	}
+	return http.StatusNoContent, nil
+}
+
+func (s *RESTServer) webhookMessageFromPath(r *request) (msg *Message, index int, err *APIError) {
+	w, err := s.webhookFromPath(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	id := r.snowflake(2)
+	for i, m := range s.messages[w.ChannelID] {
+		if m.ID != id {
+			continue
+		}
+		if m.WebhookID != w.ID {
+			return nil, 0, errNotAuthor
+		}
+		return m, i, nil
+	}
+	return nil, 0, errUnknownMessage
+}
+
+func updateWebhookMessage(s *RESTServer, r *request) (int, interface{}) {
+	msg, _, err := s.webhookMessageFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.editMessage(msg, r); err != nil {
+		return 0, err
+	}
+	return http.StatusOK, messageCopy(msg)
+}
+
+func deleteWebhookMessage(s *RESTServer, r *request) (int, interface{}) {
+	msg, i, err := s.webhookMessageFromPath(r)
+	if err != nil {
+		return 0, err
+	}
+	s.removeMessage(msg.ChannelID, i)
+	return http.StatusNoContent, nil
+}
@@ -0,0 +1,676 @@
+package disgordtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBotToken is the bot token accepted by a RESTServer unless WithBotToken is used.
+const DefaultBotToken = "disgordtest.bot.token"
+
+// RecordedRequest is a REST request received by the RESTServer.
+type RecordedRequest struct {
+	Method string
+	// Path is the endpoint without the "/api/v{version}" prefix, eg. "/channels/123/messages"
+	Path       string
+	APIVersion int
+	Query      url.Values
+	Header     http.Header
+	Body       []byte
+}
+
+type injectedError struct {
+	method string
+	path   string
+	err    *APIError
+}
+
+// RESTOption configures a RESTServer on creation.
+type RESTOption func(s *RESTServer)
+
+// WithBotToken sets the bot token the server expects in the Authorization header.
+func WithBotToken(token string) RESTOption {
+	return func(s *RESTServer) {
+		s.token = token
+	}
+}
+
+// WithBotUser overwrites the user object representing the bot, returned by /users/@me.
+func WithBotUser(user User) RESTOption {
+	return func(s *RESTServer) {
+		user.Bot = true
+		s.bot = user
+	}
+}
+
+// WithRateLimit sets how many requests each route bucket allows within the window.
+func WithRateLimit(limit int, window time.Duration) RESTOption {
+	return func(s *RESTServer) {
+		s.limiter = newRateLimiter(limit, window)
+	}
+}
+
+// WithoutRateLimit keeps sending rate limit headers, but never responds with a 429.
+func WithoutRateLimit() RESTOption {
+	return func(s *RESTServer) {
+		s.limiter.disabled = true
+	}
+}
+
+// WithGateway sets the websocket url and the recommended shard count returned by /gateway and /gateway/bot.
+func WithGateway(url string, shards uint) RESTOption {
+	return func(s *RESTServer) {
+		s.gatewayURL = url
+		s.shards = shards
+	}
+}
+
+// RESTServer is an in-memory Discord REST API emulator. All methods are safe for concurrent use.
+type RESTServer struct {
+	mu sync.Mutex
+
+	srv   *httptest.Server
+	token string
+	bot   User
+
+	gatewayURL string
+	shards     uint
+
+	users    map[Snowflake]*User
+	guilds   map[Snowflake]*Guild
+	channels map[Snowflake]*Channel
+	messages map[Snowflake][]*Message // channel id => messages sorted by creation
+	members  map[Snowflake]map[Snowflake]*Member
+	bans     map[Snowflake]map[Snowflake]*Ban
+	webhooks map[Snowflake]*Webhook
+	pins     map[Snowflake][]Snowflake
+
+	limiter  *rateLimiter
+	injected []*injectedError
+	requests []*RecordedRequest
+}
+
+// NewRESTServer creates and starts a REST emulator listening on a random local port.
+// Remember to call Close.
+func NewRESTServer(opts ...RESTOption) *RESTServer {
+	s := &RESTServer{
+		token: DefaultBotToken,
+		bot: User{
+			ID:            NewSnowflake(),
+			Username:      "disgordtest",
+			Discriminator: "0001",
+			Bot:           true,
+		},
+		gatewayURL: "wss://gateway.discord.gg",
+		shards:     1,
+		users:      make(map[Snowflake]*User),
+		guilds:     make(map[Snowflake]*Guild),
+		channels:   make(map[Snowflake]*Channel),
+		messages:   make(map[Snowflake][]*Message),
+		members:    make(map[Snowflake]map[Snowflake]*Member),
+		bans:       make(map[Snowflake]map[Snowflake]*Ban),
+		webhooks:   make(map[Snowflake]*Webhook),
+		pins:       make(map[Snowflake][]Snowflake),
+		limiter:    newRateLimiter(DefaultRateLimit, DefaultRateLimitWindow),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	bot := s.bot
+	s.users[bot.ID] = &bot
+
+	s.srv = httptest.NewServer(s)
+	return s
+}
+
+// URL returns the base url of the REST API, without the version suffix. Use it as disgord.Config.RESTBaseURL.
+func (s *RESTServer) URL() string {
+	return s.srv.URL + "/api"
+}
+
+// BotToken returns the token the server expects in requests.
+func (s *RESTServer) BotToken() string {
+	return s.token
+}
+
+// Bot returns the user object of the bot.
+func (s *RESTServer) Bot() User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bot
+}
+
+// SetGateway updates the response of /gateway and /gateway/bot.
+func (s *RESTServer) SetGateway(url string, shards uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gatewayURL = url
+	s.shards = shards
+}
+
+// Close shuts down the server and blocks until all requests have completed.
+func (s *RESTServer) Close() {
+	s.srv.Close()
+}
+
+// Requests returns every request received so far, in order.
+func (s *RESTServer) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := make([]RecordedRequest, len(s.requests))
+	for i := range s.requests {
+		requests[i] = *s.requests[i]
+	}
+	return requests
+}
+
+// ClearRequests empties the request log.
+func (s *RESTServer) ClearRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// FailNext forces the next request matching the method and path (eg. "/channels/123/messages") to respond
+// with the given error, without touching the state. Useful for testing error handling.
+func (s *RESTServer) FailNext(method, path string, httpCode, code int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.injected = append(s.injected, &injectedError{
+		method: method,
+		path:   path,
+		err:    newAPIError(httpCode, code, message),
+	})
+}
+
+//////////////////////////////////////////////////////
+//
+// STATE
+//
+//////////////////////////////////////////////////////
+
+// AddUser stores a user. A snowflake is generated if the ID is not set.
+func (s *RESTServer) AddUser(user User) User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addUser(user)
+}
+
+func (s *RESTServer) addUser(user User) User {
+	if user.ID.IsZero() {
+		user.ID = NewSnowflake()
+	}
+	if user.Discriminator == "" {
+		user.Discriminator = "0001"
+	}
+	cp := user
+	s.users[user.ID] = &cp
+	return user
+}
+
+// AddGuild stores a guild the bot is a member of. The @everyone role is created if missing, and a
+// owner is created and added as a member if OwnerID is not set.
+func (s *RESTServer) AddGuild(guild Guild) Guild {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if guild.ID.IsZero() {
+		guild.ID = NewSnowflake()
+	}
+	if guild.Region == "" {
+		guild.Region = "us-east"
+	}
+	var hasEveryone bool
+	for i := range guild.Roles {
+		if guild.Roles[i].ID == guild.ID {
+			hasEveryone = true
+		}
+	}
+	if !hasEveryone {
+		guild.Roles = append([]Role{{
+			ID:          guild.ID,
+			Name:        "@everyone",
+			Permissions: 104324673, // Discord default
+		}}, guild.Roles...)
+	}
+	if guild.Emojis == nil {
+		guild.Emojis = []Emoji{}
+	}
+	s.members[guild.ID] = make(map[Snowflake]*Member)
+	s.bans[guild.ID] = make(map[Snowflake]*Ban)
+
+	if guild.OwnerID.IsZero() {
+		owner := s.addUser(User{Username: "owner"})
+		guild.OwnerID = owner.ID
+	}
+	if owner, ok := s.users[guild.OwnerID]; ok {
+		s.addMember(guild.ID, Member{User: *owner})
+	}
+	s.addMember(guild.ID, Member{User: s.bot})
+
+	cp := guild
+	s.guilds[guild.ID] = &cp
+	s.updateMemberCount(guild.ID)
+	return s.guildCopy(&cp)
+}
+
+// Guild returns a copy of the guild.
+func (s *RESTServer) Guild(id Snowflake) (guild Guild, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[id]
+	if !ok {
+		return Guild{}, false
+	}
+	return s.guildCopy(g), true
+}
+
+// AddChannel stores a channel. A snowflake is generated if the ID is not set.
+func (s *RESTServer) AddChannel(channel Channel) Channel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addChannel(channel)
+}
+
+func (s *RESTServer) addChannel(channel Channel) Channel {
+	if channel.ID.IsZero() {
+		channel.ID = NewSnowflake()
+	}
+	if channel.PermissionOverwrites == nil {
+		channel.PermissionOverwrites = []PermissionOverwrite{}
+	}
+	cp := channel
+	s.channels[channel.ID] = &cp
+	return channel
+}
+
+// Channel returns a copy of the channel.
+func (s *RESTServer) Channel(id Snowflake) (channel Channel, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.channels[id]
+	if !ok {
+		return Channel{}, false
+	}
+	return *c, true
+}
+
+// AddMember stores a guild member, and the user if it is unknown.
+func (s *RESTServer) AddMember(guildID Snowflake, member Member) Member {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	member = s.addMember(guildID, member)
+	s.updateMemberCount(guildID)
+	return member
+}
+
+func (s *RESTServer) addMember(guildID Snowflake, member Member) Member {
+	if _, ok := s.users[member.User.ID]; !ok || member.User.ID.IsZero() {
+		member.User = s.addUser(member.User)
+	}
+	if member.JoinedAt == "" {
+		member.JoinedAt = Timestamp(time.Now())
+	}
+	if member.Roles == nil {
+		member.Roles = []Snowflake{}
+	}
+	member.GuildID = guildID
+
+	cp := member
+	if _, ok := s.members[guildID]; !ok {
+		s.members[guildID] = make(map[Snowflake]*Member)
+	}
+	s.members[guildID][member.User.ID] = &cp
+	return member
+}
+
+// Member returns a copy of the guild member.
+func (s *RESTServer) Member(guildID, userID Snowflake) (member Member, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.members[guildID][userID]
+	if !ok {
+		return Member{}, false
+	}
+	return memberCopy(m), true
+}
+
+// AddRole stores a role in the given guild.
+func (s *RESTServer) AddRole(guildID Snowflake, role Role) (Role, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return Role{}, false
+	}
+	if role.ID.IsZero() {
+		role.ID = NewSnowflake()
+	}
+	g.Roles = append(g.Roles, role)
+	return role, true
+}
+
+// AddMessage stores a message in the channel. The bot is the author unless specified.
+func (s *RESTServer) AddMessage(message Message) Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if message.Author.ID.IsZero() {
+		message.Author = s.bot
+	}
+	return *s.addMessage(&message)
+}
+
+func (s *RESTServer) addMessage(message *Message) *Message {
+	if message.ID.IsZero() {
+		message.ID = NewSnowflake()
+	}
+	if message.Timestamp == "" {
+		message.Timestamp = Timestamp(time.Now())
+	}
+	if c, ok := s.channels[message.ChannelID]; ok {
+		message.GuildID = c.GuildID
+		c.LastMessageID = message.ID
+	}
+	if message.Mentions == nil {
+		message.Mentions = []User{}
+	}
+	if message.MentionRoles == nil {
+		message.MentionRoles = []Snowflake{}
+	}
+	if message.Attachments == nil {
+		message.Attachments = []Attachment{}
+	}
+	if message.Embeds == nil {
+		message.Embeds = []json.RawMessage{}
+	}
+	s.messages[message.ChannelID] = append(s.messages[message.ChannelID], message)
+	return message
+}
+
+// Messages returns copies of the messages in a channel, oldest first.
+func (s *RESTServer) Messages(channelID Snowflake) []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msgs := make([]Message, len(s.messages[channelID]))
+	for i := range s.messages[channelID] {
+		msgs[i] = messageCopy(s.messages[channelID][i])
+	}
+	return msgs
+}
+
+// AddWebhook stores a webhook for a channel. The ID and token are generated if missing.
+func (s *RESTServer) AddWebhook(webhook Webhook) Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addWebhook(webhook)
+}
+
+func (s *RESTServer) addWebhook(webhook Webhook) Webhook {
+	if webhook.ID.IsZero() {
+		webhook.ID = NewSnowflake()
+	}
+	if webhook.Token == "" {
+		webhook.Token = "token-" + webhook.ID.String()
+	}
+	if c, ok := s.channels[webhook.ChannelID]; ok {
+		webhook.GuildID = c.GuildID
+	}
+	cp := webhook
+	s.webhooks[webhook.ID] = &cp
+	return webhook
+}
+
+// Webhook returns a copy of the webhook.
+func (s *RESTServer) Webhook(id Snowflake) (webhook Webhook, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.webhooks[id]
+	if !ok {
+		return Webhook{}, false
+	}
+	return *w, true
+}
+
+// Bans returns the bans of a guild.
+func (s *RESTServer) Bans(guildID Snowflake) []Ban {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bans := make([]Ban, 0, len(s.bans[guildID]))
+	for _, ban := range s.bans[guildID] {
+		bans = append(bans, *ban)
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].User.ID < bans[j].User.ID
+	})
+	return bans
+}
+
+func (s *RESTServer) updateMemberCount(guildID Snowflake) {
+	if g, ok := s.guilds[guildID]; ok {
+		g.MemberCount = uint(len(s.members[guildID]))
+	}
+}
+
+func (s *RESTServer) guildCopy(g *Guild) Guild {
+	cp := *g
+	cp.Roles = append([]Role(nil), g.Roles...)
+	cp.Emojis = append([]Emoji(nil), g.Emojis...)
+	return cp
+}
+
+func memberCopy(m *Member) Member {
+	cp := *m
+	cp.Roles = append([]Snowflake{}, m.Roles...)
+	return cp
+}
+
+func messageCopy(m *Message) Message {
+	cp := *m
+	cp.Reactions = make([]Reaction, len(m.Reactions))
+	for i := range m.Reactions {
+		cp.Reactions[i] = m.Reactions[i]
+		cp.Reactions[i].users = nil
+	}
+	if len(cp.Reactions) == 0 {
+		cp.Reactions = nil
+	}
+	return cp
+}
+
+//////////////////////////////////////////////////////
+//
+// HTTP
+//
+//////////////////////////////////////////////////////
+
+type request struct {
+	*http.Request
+	version int
+	path    string
+	params  []string
+	body    []byte
+	bot     bool // authenticated as the bot
+}
+
+// snowflake returns the path parameter at the given index as a snowflake
+func (r *request) snowflake(i int) Snowflake {
+	id, _ := strconv.ParseUint(r.params[i], 10, 64)
+	return Snowflake(id)
+}
+
+func (r *request) decode(v interface{}) *APIError {
+	if len(r.body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(r.body, v); err != nil {
+		return errInvalidBody
+	}
+	return nil
+}
+
+func (r *request) precise() bool {
+	return r.Header.Get("X-RateLimit-Precision") == "millisecond"
+}
+
+// splitPath removes the "/api" and "/v{version}" prefixes
+func splitPath(p string) (path string, version int) {
+	path = strings.TrimPrefix(p, "/api")
+	if strings.HasPrefix(path, "/v") {
+		end := strings.Index(path[1:], "/")
+		if end < 0 {
+			end = len(path) - 1
+		}
+		if v, err := strconv.Atoi(path[2 : end+1]); err == nil {
+			version = v
+			path = path[end+1:]
+		}
+	}
+	return path, version
+}
+
+func (s *RESTServer) ServeHTTP(w http.ResponseWriter, httpReq *http.Request) {
+	body, _ := ioutil.ReadAll(httpReq.Body)
+	_ = httpReq.Body.Close()
+
+	path, version := splitPath(httpReq.URL.Path)
+	req := &request{
+		Request: httpReq,
+		version: version,
+		path:    path,
+		body:    body,
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, &RecordedRequest{
+		Method:     httpReq.Method,
+		Path:       path,
+		APIVersion: version,
+		Query:      httpReq.URL.Query(),
+		Header:     httpReq.Header.Clone(),
+		Body:       body,
+	})
+	s.mu.Unlock()
+
+	rt, params := matchRoute(httpReq.Method, path)
+	if rt == nil {
+		if params != nil {
+			writeError(w, errMethod)
+		} else {
+			writeError(w, errNotFound)
+		}
+		return
+	}
+	req.params = params
+
+	// webhook token routes authenticate through the token in the url
+	req.bot = httpReq.Header.Get("Authorization") == "Bot "+s.token
+	if !rt.tokenAuth && !req.bot {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	var major string
+	if rt.major && len(params) > 0 {
+		major = params[0]
+	}
+	bucket, allowed := s.limiter.take(httpReq.Method+" "+rt.pattern, major)
+	bucket.writeHeaders(w.Header(), req.precise())
+	if !allowed {
+		writeRateLimited(w, &bucket)
+		return
+	}
+
+	s.mu.Lock()
+	if err := s.popInjectedError(httpReq.Method, path); err != nil {
+		s.mu.Unlock()
+		writeError(w, err)
+		return
+	}
+	status, resp := rt.handler(s, req)
+	if err, ok := resp.(*APIError); ok {
+		s.mu.Unlock()
+		writeError(w, err)
+		return
+	}
+
+	// the response points into the state of the server, and must be encoded before it is unlocked
+	status, encoded := encodeJSON(status, resp)
+	s.mu.Unlock()
+	writeBody(w, status, encoded)
+}
+
+func (s *RESTServer) popInjectedError(method, path string) *APIError {
+	for i := range s.injected {
+		if s.injected[i].method == method && s.injected[i].path == path {
+			err := s.injected[i].err
+			s.injected = append(s.injected[:i], s.injected[i+1:]...)
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	status, body := encodeJSON(status, v)
+	writeBody(w, status, body)
+}
+
+// encodeJSON returns the body of the response, or an error response when v can not be encoded
+func encodeJSON(status int, v interface{}) (int, []byte) {
+	if status == http.StatusNoContent || v == nil {
+		return status, nil
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return encodeJSON(http.StatusInternalServerError, newAPIError(http.StatusInternalServerError, ErrCodeGeneral, err.Error()))
+	}
+	return status, buf.Bytes()
+}
+
+func writeBody(w http.ResponseWriter, status int, body []byte) {
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, err *APIError) {
+	writeJSON(w, err.HTTPCode, err)
+}
+
+func writeRateLimited(w http.ResponseWriter, bucket *rateLimitBucket) {
+	retryAfter := time.Until(bucket.reset)
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+	ms := int64(retryAfter / time.Millisecond)
+	w.Header().Set("Retry-After", strconv.FormatInt(ms, 10))
+	writeJSON(w, http.StatusTooManyRequests, &struct {
+		Message    string `json:"message"`
+		RetryAfter int64  `json:"retry_after"`
+		Global     bool   `json:"global"`
+	}{"You are being rate limited.", ms, false})
+}
@@ -0,0 +1,134 @@
+package disgord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/andersfylling/disgord/internal/gateway"
+	"github.com/andersfylling/disgord/internal/util"
+)
+
+//////////////////////////////////////////////////////
+//
+// Event bus: Splits a bot into a gateway process, that owns the shards, and one or more worker processes.
+//
+// The gateway Client publishes every event to an EventSink, while the worker Clients are connected to
+// an EventSource in stead of Discord. Gateway commands issued by a worker, eg. UpdateStatus, are sent
+// back through the EventSource to the gateway Client, which emits them on the owning shard.
+// See EventBusServer and EventBusClient for a reference transport.
+//
+//////////////////////////////////////////////////////
+
+// EventSink receives every gateway event, after it was cached. See Config.EventSink.
+type EventSink interface {
+	Publish(evt *RawEvent) error
+}
+
+// CommandEmitter emits gateway commands forwarded from a worker, see Client.EmitCommand.
+type CommandEmitter interface {
+	EmitCommand(cmd *GatewayCommand) (unhandledGuildIDs []Snowflake, err error)
+}
+
+// EventSource replaces the shards of a worker Client. The events are dispatched as if they came from
+// Discord, and the gateway commands are forwarded to the Client owning the shards. See Config.EventSource.
+type EventSource interface {
+	CommandEmitter
+
+	// Events returns the incoming events. The channel is closed when the source is closed.
+	Events() <-chan *RawEvent
+}
+
+// GatewayCommand is a gateway command in transit from a worker to the Client that owns the shards.
+// The payload is the JSON encoded command payload, eg. UpdateStatusPayload.
+type GatewayCommand struct {
+	Name    string          `json:"name"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// EmitCommand emits a gateway command received from a worker, on the shards of this Client.
+func (c *Client) EmitCommand(cmd *GatewayCommand) (unhandledGuildIDs []Snowflake, err error) {
+	if cmd == nil {
+		return nil, errors.New("command can not be nil")
+	}
+
+	var payload gatewayCmdPayload
+	switch gatewayCmdName(cmd.Name) {
+	case RequestGuildMembers:
+		payload = &RequestGuildMembersPayload{}
+	case UpdateVoiceState:
+		payload = &UpdateVoiceStatePayload{}
+	case UpdateStatus:
+		payload = &UpdateStatusPayload{}
+	default:
+		return nil, errors.New("unsupported gateway command: " + cmd.Name)
+	}
+
+	if err = util.Unmarshal(cmd.Payload, payload); err != nil {
+		return nil, err
+	}
+	return c.Emit(gatewayCmdName(cmd.Name), payload)
+}
+
+// emitToSource forwards a gateway command to the Client that owns the shards
+func (c *Client) emitToSource(name gatewayCmdName, payload gatewayCmdPayload) (unhandledGuildIDs []Snowflake, err error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.config.EventSource.EmitCommand(&GatewayCommand{
+		Name:    string(name),
+		Payload: data,
+	})
+}
+
+// connectToSource dispatches the events of the EventSource, in stead of connecting to Discord
+func (c *Client) connectToSource(ctx context.Context) error {
+	c.setupConnectEnv()
+
+	source := c.config.EventSource
+	go func() {
+		for evt := range source.Events() {
+			select {
+			case c.eventChan <- &gateway.Event{
+				Name:     evt.Name,
+				Data:     evt.Data,
+				ShardID:  evt.ShardID,
+				Sequence: evt.Sequence,
+			}:
+			case <-c.shutdownChan:
+				return
+			}
+		}
+	}()
+
+	c.log.Info("Connected to event source")
+	return nil
+}
+
+// disconnectFromSource closes the EventSource, if it can be closed
+func (c *Client) disconnectFromSource() error {
+	if closer, ok := c.config.EventSource.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// publish sends the event to the EventSink
+func (d *dispatcher) publish(evt *gateway.Event) {
+	if d.sink == nil {
+		return
+	}
+
+	err := d.sink.Publish(&RawEvent{
+		Name:     evt.Name,
+		Sequence: evt.Sequence,
+		Data:     evt.Data,
+		ShardID:  evt.ShardID,
+	})
+	if err != nil {
+		d.session.Logger().Error("unable to publish event `", evt.Name, "`: ", err)
+	}
+}
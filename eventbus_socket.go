@@ -0,0 +1,411 @@
+package disgord
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+// the reference event bus transport sends one JSON frame per line
+const (
+	eventBusFrameEvent   = "event"
+	eventBusFrameCommand = "command"
+	eventBusFrameReply   = "reply"
+)
+
+const (
+	// eventBusWorkerQueueSize is the number of frames a worker can lag behind before it is disconnected
+	eventBusWorkerQueueSize = 1024
+
+	// eventBusWriteTimeout disconnects workers that stop reading from the connection
+	eventBusWriteTimeout = 10 * time.Second
+)
+
+type eventBusFrame struct {
+	Kind string `json:"kind"`
+
+	// ID pairs a command with the reply
+	ID uint64 `json:"id,omitempty"`
+
+	// event
+	Name     string          `json:"name,omitempty"`
+	ShardID  uint            `json:"shard_id,omitempty"`
+	Sequence uint64          `json:"sequence,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+
+	// command
+	Command *GatewayCommand `json:"command,omitempty"`
+
+	// reply
+	UnhandledGuildIDs []Snowflake `json:"unhandled_guild_ids,omitempty"`
+	Error             string      `json:"error,omitempty"`
+}
+
+type eventBusConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func newEventBusConn(conn net.Conn) *eventBusConn {
+	return &eventBusConn{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		encoder: json.NewEncoder(conn), // writes a newline after every frame
+	}
+}
+
+func (c *eventBusConn) write(frame *eventBusFrame) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.encoder.Encode(frame)
+}
+
+func (c *eventBusConn) read() (*eventBusFrame, error) {
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	frame := &eventBusFrame{}
+	if err = json.Unmarshal(line, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+//////////////////////////////////////////////////////
+//
+// Gateway process
+//
+//////////////////////////////////////////////////////
+
+// EventBusServer is an EventSink that publishes events to the workers connected over a TCP or Unix socket,
+// using line delimited JSON. Events of a guild are always sent to the same worker, as long as the number of
+// workers stays the same. Direct message events are assigned a worker by their channel instead, while
+// events with neither a guild nor a channel, such as READY, are sent to every worker.
+//
+// Every worker has a bounded queue of outgoing frames. A worker that falls too far behind, or does not
+// accept writes within a timeout, is disconnected such that it can not stall the other workers.
+//
+//  bus, err := disgord.ListenEventBus("tcp", ":7000")
+//  client := disgord.New(disgord.Config{BotToken: token, EventSink: bus})
+//  go bus.Serve(client)
+//  client.Connect(ctx)
+type EventBusServer struct {
+	listener net.Listener
+
+	mu      sync.RWMutex
+	workers []*eventBusWorker
+}
+
+// eventBusWorker is the server side of a worker connection. Frames are written by a dedicated
+// goroutine, such that a slow worker does not block the publisher.
+type eventBusWorker struct {
+	*eventBusConn
+	queue chan *eventBusFrame
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newEventBusWorker(conn net.Conn) *eventBusWorker {
+	return &eventBusWorker{
+		eventBusConn: newEventBusConn(conn),
+		queue:        make(chan *eventBusFrame, eventBusWorkerQueueSize),
+		done:         make(chan struct{}),
+	}
+}
+
+// send queues the frame without blocking. False is returned when the queue is full or the worker is closed.
+func (w *eventBusWorker) send(frame *eventBusFrame) bool {
+	select {
+	case <-w.done:
+		return false
+	default:
+	}
+
+	select {
+	case w.queue <- frame:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *eventBusWorker) writeFrames() {
+	for {
+		select {
+		case frame := <-w.queue:
+			_ = w.conn.SetWriteDeadline(time.Now().Add(eventBusWriteTimeout))
+			if err := w.write(frame); err != nil {
+				w.close()
+				return
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *eventBusWorker) close() {
+	w.once.Do(func() {
+		close(w.done)
+		_ = w.conn.Close()
+	})
+}
+
+// ListenEventBus creates an EventBusServer that listens on the address. See net.Listen.
+func ListenEventBus(network, address string) (*EventBusServer, error) {
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return NewEventBusServer(listener), nil
+}
+
+// NewEventBusServer creates an EventBusServer that accepts workers from the listener.
+func NewEventBusServer(listener net.Listener) *EventBusServer {
+	return &EventBusServer{
+		listener: listener,
+	}
+}
+
+// Addr returns the address workers can connect to, see DialEventBus.
+func (s *EventBusServer) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Serve accepts workers until the server is closed. The gateway commands of the workers are emitted
+// by the emitter, which is usually the Client owning the shards.
+func (s *EventBusServer) Serve(emitter CommandEmitter) error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		worker := newEventBusWorker(conn)
+		s.mu.Lock()
+		s.workers = append(s.workers, worker)
+		s.mu.Unlock()
+
+		go worker.writeFrames()
+		go s.serveWorker(worker, emitter)
+	}
+}
+
+func (s *EventBusServer) serveWorker(worker *eventBusWorker, emitter CommandEmitter) {
+	defer s.remove(worker)
+
+	for {
+		frame, err := worker.read()
+		if err != nil {
+			return
+		}
+		if frame.Kind != eventBusFrameCommand || frame.Command == nil {
+			continue
+		}
+
+		reply := &eventBusFrame{Kind: eventBusFrameReply, ID: frame.ID}
+		if reply.UnhandledGuildIDs, err = emitter.EmitCommand(frame.Command); err != nil {
+			reply.Error = err.Error()
+		}
+		select {
+		case worker.queue <- reply:
+		case <-worker.done:
+			return
+		}
+	}
+}
+
+func (s *EventBusServer) remove(worker *eventBusWorker) {
+	worker.close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.workers {
+		if s.workers[i] == worker {
+			s.workers = append(s.workers[:i:i], s.workers[i+1:]...)
+			break
+		}
+	}
+}
+
+// Publish queues the event for the workers. Workers with a full queue are disconnected.
+func (s *EventBusServer) Publish(evt *RawEvent) error {
+	s.mu.RLock()
+	workers := s.workers
+	s.mu.RUnlock()
+	if len(workers) == 0 {
+		return nil
+	}
+
+	// the guild id, or the channel id of direct messages
+	if key := dispatchOrderingKey(OrderByGuild, evt.Name, evt.Data); !key.IsZero() {
+		hash := (uint64(key) * 0x9E3779B97F4A7C15) >> 32
+		workers = workers[hash%uint64(len(workers)) : hash%uint64(len(workers))+1]
+	}
+
+	frame := &eventBusFrame{
+		Kind:     eventBusFrameEvent,
+		Name:     evt.Name,
+		ShardID:  evt.ShardID,
+		Sequence: evt.Sequence,
+		Data:     evt.Data,
+	}
+
+	var failed int
+	for _, worker := range workers {
+		if !worker.send(frame) {
+			failed++
+			s.remove(worker)
+		}
+	}
+	if failed > 0 {
+		return errors.New("unable to publish the event to one or more workers")
+	}
+	return nil
+}
+
+// Close stops accepting workers, and disconnects the connected workers.
+func (s *EventBusServer) Close() error {
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	workers := s.workers
+	s.workers = nil
+	s.mu.Unlock()
+	for _, worker := range workers {
+		worker.close()
+	}
+	return err
+}
+
+//////////////////////////////////////////////////////
+//
+// Worker process
+//
+//////////////////////////////////////////////////////
+
+// EventBusClient is an EventSource that receives events from an EventBusServer.
+//
+//  bus, err := disgord.DialEventBus("tcp", "gateway:7000")
+//  client := disgord.New(disgord.Config{BotToken: token, EventSource: bus})
+//  client.Connect(ctx)
+type EventBusClient struct {
+	conn   *eventBusConn
+	events chan *RawEvent
+	closed chan struct{}
+	once   sync.Once
+
+	commandID atomic.Uint64
+	mu        sync.Mutex
+	pending   map[uint64]chan *eventBusFrame
+}
+
+var _ EventSource = (*EventBusClient)(nil)
+var _ io.Closer = (*EventBusClient)(nil)
+
+// DialEventBus connects to an EventBusServer. See net.Dial.
+func DialEventBus(network, address string) (*EventBusClient, error) {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return NewEventBusClient(conn), nil
+}
+
+// NewEventBusClient creates an EventBusClient from a connection to an EventBusServer.
+func NewEventBusClient(conn net.Conn) *EventBusClient {
+	c := &EventBusClient{
+		conn:    newEventBusConn(conn),
+		events:  make(chan *RawEvent),
+		closed:  make(chan struct{}),
+		pending: make(map[uint64]chan *eventBusFrame),
+	}
+	go c.receive()
+	return c
+}
+
+func (c *EventBusClient) receive() {
+	defer close(c.events)
+	defer c.Close()
+
+	for {
+		frame, err := c.conn.read()
+		if err != nil {
+			return
+		}
+
+		switch frame.Kind {
+		case eventBusFrameEvent:
+			evt := &RawEvent{
+				Name:     frame.Name,
+				Sequence: frame.Sequence,
+				Data:     frame.Data,
+				ShardID:  frame.ShardID,
+			}
+			select {
+			case c.events <- evt:
+			case <-c.closed:
+				return
+			}
+		case eventBusFrameReply:
+			c.mu.Lock()
+			reply, ok := c.pending[frame.ID]
+			delete(c.pending, frame.ID)
+			c.mu.Unlock()
+			if ok {
+				reply <- frame
+			}
+		}
+	}
+}
+
+// Events returns the events published by the EventBusServer. The channel is closed when the connection is lost.
+func (c *EventBusClient) Events() <-chan *RawEvent {
+	return c.events
+}
+
+// EmitCommand forwards the gateway command to the EventBusServer, and waits for the result.
+func (c *EventBusClient) EmitCommand(cmd *GatewayCommand) (unhandledGuildIDs []Snowflake, err error) {
+	id := c.commandID.Inc()
+	reply := make(chan *eventBusFrame, 1)
+	c.mu.Lock()
+	c.pending[id] = reply
+	c.mu.Unlock()
+
+	if err = c.conn.write(&eventBusFrame{Kind: eventBusFrameCommand, ID: id, Command: cmd}); err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return nil, err
+	}
+
+	select {
+	case frame := <-reply:
+		if frame.Error != "" {
+			err = errors.New(frame.Error)
+		}
+		return frame.UnhandledGuildIDs, err
+	case <-c.closed:
+		return nil, errors.New("event bus connection was closed")
+	}
+}
+
+// Close disconnects from the EventBusServer.
+func (c *EventBusClient) Close() (err error) {
+	c.once.Do(func() {
+		close(c.closed)
+		err = c.conn.conn.Close()
+	})
+	return err
+}
@@ -0,0 +1,284 @@
+package disgord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andersfylling/disgord/disgordtest"
+	"github.com/andersfylling/disgord/internal/gateway"
+)
+
+type recordingEmitter struct {
+	sync.Mutex
+	commands []*GatewayCommand
+}
+
+func (e *recordingEmitter) EmitCommand(cmd *GatewayCommand) ([]Snowflake, error) {
+	e.Lock()
+	defer e.Unlock()
+	e.commands = append(e.commands, cmd)
+	if cmd.Name == string(RequestGuildMembers) {
+		return []Snowflake{486833611564253184}, errors.New("shard is not connected")
+	}
+	return nil, nil
+}
+
+func (e *recordingEmitter) received() []*GatewayCommand {
+	e.Lock()
+	defer e.Unlock()
+	return append([]*GatewayCommand(nil), e.commands...)
+}
+
+func newEventBus(t *testing.T, workers int) (*EventBusServer, []*EventBusClient, *recordingEmitter) {
+	t.Helper()
+	srv, err := ListenEventBus("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	emitter := &recordingEmitter{}
+	go srv.Serve(emitter)
+
+	clients := make([]*EventBusClient, 0, workers)
+	for i := 0; i < workers; i++ {
+		client, err := DialEventBus("tcp", srv.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		clients = append(clients, client)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		srv.mu.RLock()
+		connected := len(srv.workers)
+		srv.mu.RUnlock()
+		if connected == workers {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d workers, got %d", workers, connected)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return srv, clients, emitter
+}
+
+func receiveRawEvent(client *EventBusClient, timeout time.Duration) *RawEvent {
+	select {
+	case evt := <-client.Events():
+		return evt
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+func TestEventBus_Publish(t *testing.T) {
+	srv, clients, _ := newEventBus(t, 2)
+	defer srv.Close()
+
+	ready := &RawEvent{Name: EvtReady, ShardID: 1, Sequence: 1, Data: []byte(`{"v":6}`)}
+	if err := srv.Publish(ready); err != nil {
+		t.Fatal(err)
+	}
+	for i, client := range clients {
+		evt := receiveRawEvent(client, time.Second)
+		if evt == nil {
+			t.Fatalf("expected worker %d to receive events without a guild", i)
+		}
+		if evt.Name != EvtReady || evt.ShardID != 1 || evt.Sequence != 1 || string(evt.Data) != `{"v":6}` {
+			t.Errorf("unexpected event %+v", evt)
+		}
+	}
+
+	message := &RawEvent{Name: EvtMessageCreate, Data: []byte(`{"guild_id":"1234","content":"hi"}`)}
+	for i := 0; i < 3; i++ {
+		if err := srv.Publish(message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var received []int
+	for i, client := range clients {
+		for receiveRawEvent(client, 50*time.Millisecond) != nil {
+			received = append(received, i)
+		}
+	}
+	if len(received) != 3 || received[0] != received[1] || received[1] != received[2] {
+		t.Errorf("expected every event of the guild to reach the same worker, got %+v", received)
+	}
+}
+
+func TestEventBus_PublishSlowWorker(t *testing.T) {
+	// the worker never reads, so the writer blocks on the first frame
+	conn, remote := net.Pipe()
+	defer remote.Close()
+
+	worker := newEventBusWorker(conn)
+	go worker.writeFrames()
+	srv := &EventBusServer{workers: []*eventBusWorker{worker}}
+
+	ready := &RawEvent{Name: EvtReady, Data: []byte(`{}`)}
+	var err error
+	for i := 0; i <= eventBusWorkerQueueSize+1 && err == nil; i++ {
+		err = srv.Publish(ready)
+	}
+	if err == nil {
+		t.Fatal("expected an error when the queue of the worker overflows")
+	}
+
+	srv.mu.RLock()
+	connected := len(srv.workers)
+	srv.mu.RUnlock()
+	if connected != 0 {
+		t.Errorf("expected the slow worker to be disconnected, got %d workers", connected)
+	}
+	select {
+	case <-worker.done:
+	default:
+		t.Error("expected the worker to be closed")
+	}
+}
+
+func TestEventBus_EmitCommand(t *testing.T) {
+	srv, clients, emitter := newEventBus(t, 1)
+	defer srv.Close()
+
+	unhandled, err := clients[0].EmitCommand(&GatewayCommand{Name: string(UpdateStatus), Payload: []byte(`{}`)})
+	if err != nil || len(unhandled) != 0 {
+		t.Errorf("expected command to succeed, got %v, %+v", err, unhandled)
+	}
+
+	unhandled, err = clients[0].EmitCommand(&GatewayCommand{Name: string(RequestGuildMembers), Payload: []byte(`{}`)})
+	if err == nil || err.Error() != "shard is not connected" {
+		t.Errorf("expected the error of the emitter, got %v", err)
+	}
+	if len(unhandled) != 1 || unhandled[0] != 486833611564253184 {
+		t.Errorf("expected the unhandled guild ids, got %+v", unhandled)
+	}
+	if got := len(emitter.received()); got != 2 {
+		t.Errorf("expected 2 commands, got %d", got)
+	}
+
+	if err = clients[0].Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, open := <-clients[0].Events(); open {
+		t.Error("expected the events channel to be closed")
+	}
+	if _, err = clients[0].EmitCommand(&GatewayCommand{Name: string(UpdateStatus)}); err == nil {
+		t.Error("expected an error after closing")
+	}
+}
+
+func TestClient_EventSource(t *testing.T) {
+	rest := disgordtest.NewRESTServer()
+	defer rest.Close()
+
+	srv, clients, emitter := newEventBus(t, 1)
+	defer srv.Close()
+
+	worker := New(Config{
+		BotToken:     rest.BotToken(),
+		RESTBaseURL:  rest.URL(),
+		DisableCache: true,
+		EventSource:  clients[0],
+	})
+	messages := make(chan *MessageCreate, 1)
+	worker.On(EvtMessageCreate, func(s Session, evt *MessageCreate) {
+		messages <- evt
+	})
+	if err := worker.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	err := srv.Publish(&RawEvent{Name: EvtMessageCreate, ShardID: 2, Sequence: 9, Data: []byte(`{"content":"from gateway"}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-messages:
+		if msg.Message.Content != "from gateway" || msg.ShardID != 2 {
+			t.Errorf("unexpected message %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the worker to dispatch the event")
+	}
+
+	if err = worker.UpdateStatusString("working"); err != nil {
+		t.Fatal(err)
+	}
+	commands := emitter.received()
+	if len(commands) != 1 || commands[0].Name != string(UpdateStatus) {
+		t.Fatalf("expected the command to be forwarded, got %+v", commands)
+	}
+	status := &UpdateStatusPayload{}
+	if err = json.Unmarshal(commands[0].Payload, status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Game == nil || status.Game.Name != "working" {
+		t.Errorf("unexpected status payload %s", string(commands[0].Payload))
+	}
+
+	if err = worker.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_EmitCommand(t *testing.T) {
+	c := New(Config{
+		BotToken:     "testing",
+		DisableCache: true,
+	})
+
+	if _, err := c.EmitCommand(&GatewayCommand{Name: "UNKNOWN", Payload: []byte(`{}`)}); err == nil {
+		t.Error("expected unknown commands to fail")
+	}
+	if _, err := c.EmitCommand(&GatewayCommand{Name: string(UpdateStatus), Payload: []byte(`{`)}); err == nil {
+		t.Error("expected invalid payloads to fail")
+	}
+	if _, err := c.EmitCommand(&GatewayCommand{Name: string(UpdateStatus), Payload: []byte(`{}`)}); err == nil {
+		t.Error("expected an error as no shards are connected")
+	}
+}
+
+type recordingSink chan *RawEvent
+
+func (s recordingSink) Publish(evt *RawEvent) error {
+	s <- evt
+	return nil
+}
+
+func TestDemultiplexer_EventSink(t *testing.T) {
+	sink := make(recordingSink, 2)
+	c := New(Config{
+		BotToken:  "testing",
+		EventSink: sink,
+	})
+	defer close(c.dispatcher.shutdown)
+
+	input := make(chan *gateway.Event)
+	go demultiplexer(c.dispatcher, input, c.cache)
+
+	input <- &gateway.Event{Name: EvtChannelCreate, Data: []byte(`{"id":"486833611564253184","name":"general"}`), Sequence: 3}
+	input <- &gateway.Event{Name: "SOMETHING_NEW", Data: []byte(`{}`), Sequence: 4}
+
+	for _, expects := range []string{EvtChannelCreate, "SOMETHING_NEW"} {
+		select {
+		case evt := <-sink:
+			if evt.Name != expects {
+				t.Errorf("expected %s, got %s", expects, evt.Name)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %s to be published", expects)
+		}
+	}
+
+	// the event is published after it was cached
+	if _, err := c.cache.GetChannel(486833611564253184); err != nil {
+		t.Errorf("expected the channel to be cached before the event was published: %v", err)
+	}
+}
@@ -157,6 +157,23 @@ type evtResource interface {
 
 // ---------------------------
 
+// EvtRaw is used to register handlers for every event, including events that are not yet supported by
+// DisGord. The handlers receive the unparsed event, see RawEvent.
+//  Client.On(disgord.EvtRaw, func(s disgord.Session, evt *disgord.RawEvent) {})
+const EvtRaw = "*"
+
+// RawEvent is an event as received from the gateway, before it is parsed. Data must not be modified
+// as it is shared between the handlers.
+type RawEvent struct {
+	Name     string          `json:"-"`
+	Sequence uint64          `json:"-"`
+	Data     []byte          `json:"-"`
+	Ctx      context.Context `json:"-"`
+	ShardID  uint            `json:"-"`
+}
+
+// ---------------------------
+
 // Ready contains the initial state information
 type Ready struct {
 	APIVersion int                 `json:"v"`
@@ -393,6 +393,11 @@ func (h *PresenceUpdate) setShardID(id uint)                  { h.ShardID = id }
 
 // ---------------------------
 
+func (h *RawEvent) registerContext(ctx context.Context) { h.Ctx = ctx }
+func (h *RawEvent) setShardID(id uint)                  { h.ShardID = id }
+
+// ---------------------------
+
 // EvtReady The ready event is dispatched when a client has completed the initial handshake with the gateway (for new sessions).
 // // The ready event can be the largest and most complex event the gateway will send, as it contains all the state
 // // required for a client to begin interacting with the rest of the platform.
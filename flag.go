@@ -11,6 +11,10 @@ func (f Flag) IgnoreEmptyParams() bool {
 	return (f & IgnoreEmptyParams) > 0
 }
 
+func (f Flag) validatePermissions() bool {
+	return (f & ValidatePermissions) > 0
+}
+
 func (f Flag) Sort() bool {
 	flags := SortByID | SortByName
 	flags |= OrderAscending | OrderDescending
@@ -32,6 +36,10 @@ const (
 	// ordering
 	OrderAscending // default when sorting
 	OrderDescending
+
+	// ValidatePermissions checks the permissions and role hierarchy of the bot before a moderation request,
+	// eg. KickMember, is sent. A typed error is returned in stead of Discord responding with a 403.
+	ValidatePermissions
 )
 
 func mergeFlags(flags []Flag) (f Flag) {
@@ -81,7 +81,7 @@ func main() {
 	}
 
 	for _, event := range events {
-		if event.Docs == nil {
+		if event.Docs == nil && !nonDiscordEvents[event.varName] {
 			fmt.Fprintf(os.Stderr, "WARNING: %s is defined in events.go, but has no docs in event/events.go!\n", event.varName)
 		}
 	}
@@ -89,6 +89,7 @@ func main() {
 	// And finally pass the event information to different templates to generate some files
 	makeFile(events, "generate/events/events.gohtml", "events_gen.go")
 	makeFile(events, "generate/events/reactor.gotpl", "reactor_gen.go")
+	makeFile(events, "generate/events/register.gotpl", "reactor_register_gen.go")
 }
 
 func makeFile(events []*eventName, tplFile, target string) {
@@ -113,6 +114,11 @@ func makeFile(events []*eventName, tplFile, target string) {
 	}
 }
 
+// nonDiscordEvents are events created by DisGord, which has no Discord event name
+var nonDiscordEvents = map[string]bool{
+	"RawEvent": true,
+}
+
 type eventName struct {
 	varName string
 	Docs    *string
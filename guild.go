@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/andersfylling/disgord/internal/endpoint"
 	"github.com/andersfylling/disgord/internal/httd"
@@ -27,6 +28,40 @@ import (
 type PermissionBit = uint64
 type PermissionBits = PermissionBit
 
+// permissionBitsJSON decodes permissions from both numbers and strings, and encodes them as strings, as
+// Discord serializes permissions as strings from v8.
+type permissionBitsJSON PermissionBits
+
+var _ json.Marshaler = (*permissionBitsJSON)(nil)
+var _ json.Unmarshaler = (*permissionBitsJSON)(nil)
+
+func (b permissionBitsJSON) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.FormatUint(uint64(b), 10) + `"`), nil
+}
+
+func (b *permissionBitsJSON) UnmarshalJSON(data []byte) error {
+	str := strings.Trim(string(data), `"`)
+	if str == "" || str == "null" {
+		*b = 0
+		return nil
+	}
+
+	v, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return err
+	}
+	*b = permissionBitsJSON(v)
+	return nil
+}
+
+// permissionsParam returns the permissions in the format expected by the given Discord API version.
+func permissionsParam(apiVersion int, permissions PermissionBits) interface{} {
+	if apiVersion >= 8 {
+		return permissionBitsJSON(permissions)
+	}
+	return permissions
+}
+
 // Constants for the different bit offsets of text channel permissions
 const (
 	PermissionReadMessages PermissionBit = 1 << (iota + 10)
@@ -202,6 +237,8 @@ type Guild struct {
 	WidgetEnabled               bool                          `json:"widget_enabled,omit_empty"`    //   |
 	WidgetChannelID             Snowflake                     `json:"widget_channel_id,omit_empty"` //   |?
 	SystemChannelID             Snowflake                     `json:"system_channel_id,omitempty"`  //   |?
+	PremiumTier                 PremiumTier                   `json:"premium_tier"`
+	PremiumSubscriptionCount    uint                          `json:"premium_subscription_count,omitempty"`
 
 	// JoinedAt must be a pointer, as we can't hide non-nil structs
 	JoinedAt    *Time           `json:"joined_at,omitempty"`    // ?*|
@@ -274,6 +311,8 @@ func (g *Guild) copyOverToCache(other interface{}) (err error) {
 	guild.MemberCount = g.MemberCount
 	guild.Splash = g.Splash
 	guild.Icon = g.Icon
+	guild.PremiumTier = g.PremiumTier
+	guild.PremiumSubscriptionCount = g.PremiumSubscriptionCount
 
 	// pointers
 	if !g.ApplicationID.IsZero() {
@@ -317,9 +356,20 @@ func (g *Guild) GetMemberWithHighestSnowflake() *Member {
 	return highest
 }
 
-// func (g *Guild) UnmarshalJSON(data []byte) (err error) {
-// 	return json.Unmarshal(data, &g.guildJSON)
-// }
+// UnmarshalJSON see interface json.Unmarshaler
+func (g *Guild) UnmarshalJSON(data []byte) (err error) {
+	type guildJSON Guild
+	j := struct {
+		*guildJSON
+		Permissions permissionBitsJSON `json:"permissions,omitempty"`
+	}{guildJSON: (*guildJSON)(g), Permissions: permissionBitsJSON(g.Permissions)}
+	if err = unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	g.Permissions = PermissionBits(j.Permissions)
+	return nil
+}
 
 // MarshalJSON see interface json.Marshaler
 // TODO: fix copying of mutex lock
@@ -754,6 +804,8 @@ func (g *Guild) CopyOverTo(other interface{}) (err error) {
 	guild.MemberCount = g.MemberCount
 	guild.Splash = g.Splash
 	guild.Icon = g.Icon
+	guild.PremiumTier = g.PremiumTier
+	guild.PremiumSubscriptionCount = g.PremiumSubscriptionCount
 
 	// pointers
 	if !g.ApplicationID.IsZero() {
@@ -1074,7 +1126,7 @@ func (m *Member) UpdateNick(client nickUpdater, nickname string, flags ...Flag)
 	return client.UpdateGuildMember(m.GuildID, m.userID, flags...).SetNick(nickname).Execute()
 }
 
-func (m *Member) GetPermissions(ctx context.Context, s Session) (p uint64, err error) {
+func (m *Member) GetPermissions(ctx context.Context, s Session) (p PermissionBits, err error) {
 	uID := m.userID
 	if uID.IsZero() {
 		usr, err := m.GetUser(ctx, s)
@@ -1337,11 +1389,19 @@ func (c *Client) CreateGuildChannel(ctx context.Context, guildID Snowflake, chan
 		params.Name = channelName
 	}
 
+	var body interface{} = params
+	if v := c.req.APIVersion(); v >= 8 && len(params.PermissionOverwrites) > 0 {
+		body = &struct {
+			*CreateGuildChannelParams
+			PermissionOverwrites interface{} `json:"permission_overwrites"`
+		}{params, permissionOverwritesParam(v, params.PermissionOverwrites)}
+	}
+
 	r := c.newRESTRequest(&httd.Request{
 		Method:      httd.MethodPost,
 		Ctx:         ctx,
 		Endpoint:    endpoint.GuildChannels(guildID),
-		Body:        params,
+		Body:        body,
 		ContentType: httd.ContentTypeJSON,
 		Reason:      params.Reason,
 	}, flags)
@@ -1725,8 +1785,14 @@ func (c *Client) UpdateGuildMember(ctx context.Context, guildID, userID Snowflak
 //  Endpoint                /guilds/{guild.id}/members/{user.id}/roles/{role.id}
 //  Discord documentation   https://discordapp.com/developers/docs/resources/guild#add-guild-member-role
 //  Reviewed                2018-08-18
-//  Comment                 -
+//  Comment                 The ValidatePermissions flag checks the permissions and role hierarchy first
 func (c *Client) AddGuildMemberRole(ctx context.Context, guildID, userID, roleID Snowflake, flags ...Flag) (err error) {
+	if mergeFlags(flags).validatePermissions() {
+		if err = c.validateRoleModeration(ctx, guildID, roleID, PermissionManageRoles, flags); err != nil {
+			return err
+		}
+	}
+
 	r := c.newRESTRequest(&httd.Request{
 		Method:   httd.MethodPut,
 		Endpoint: endpoint.GuildMemberRole(guildID, userID, roleID),
@@ -1763,8 +1829,14 @@ func (c *Client) RemoveGuildMemberRole(ctx context.Context, guildID, userID, rol
 //  Endpoint                /guilds/{guild.id}/members/{user.id}
 //  Discord documentation   https://discordapp.com/developers/docs/resources/guild#remove-guild-member
 //  Reviewed                2018-08-18
-//  Comment                 -
+//  Comment                 The ValidatePermissions flag checks the permissions and role hierarchy first
 func (c *Client) KickMember(ctx context.Context, guildID, userID Snowflake, reason string, flags ...Flag) (err error) {
+	if mergeFlags(flags).validatePermissions() {
+		if err = c.validateMemberModeration(ctx, guildID, userID, PermissionKickMembers, flags); err != nil {
+			return err
+		}
+	}
+
 	r := c.newRESTRequest(&httd.Request{
 		Method:   httd.MethodDelete,
 		Endpoint: endpoint.GuildMember(guildID, userID),
@@ -1826,8 +1898,8 @@ func (c *Client) GetGuildBan(ctx context.Context, guildID, userID Snowflake, fla
 // BanMemberParams ...
 // https://discordapp.com/developers/docs/resources/guild#create-guild-ban-query-string-params
 type BanMemberParams struct {
-	DeleteMessageDays int    `urlparam:"delete_message_days,omitempty"` // number of days to delete messages for (0-7)
-	Reason            string `urlparam:"reason,omitempty"`              // reason for being banned
+	DeleteMessageDays int    `urlparam:"delete_message_days,omitempty" json:"delete_message_days,omitempty"` // number of days to delete messages for (0-7)
+	Reason            string `urlparam:"reason,omitempty" json:"reason,omitempty"`                           // reason for being banned
 }
 
 var _ URLQueryStringer = (*BanMemberParams)(nil)
@@ -1845,7 +1917,7 @@ func (b *BanMemberParams) FindErrors() error {
 //  Endpoint                /guilds/{guild.id}/bans/{user.id}
 //  Discord documentation   https://discordapp.com/developers/docs/resources/guild#create-guild-ban
 //  Reviewed                2018-08-18
-//  Comment                 -
+//  Comment                 The ValidatePermissions flag checks the permissions and role hierarchy first
 func (c *Client) BanMember(ctx context.Context, guildID, userID Snowflake, params *BanMemberParams, flags ...Flag) (err error) {
 	if params == nil {
 		return errors.New("params was nil")
@@ -1853,10 +1925,16 @@ func (c *Client) BanMember(ctx context.Context, guildID, userID Snowflake, param
 	if err = params.FindErrors(); err != nil {
 		return err
 	}
+	if mergeFlags(flags).validatePermissions() {
+		if err = c.validateMemberModeration(ctx, guildID, userID, PermissionBanMembers, flags); err != nil {
+			return err
+		}
+	}
 
 	r := c.newRESTRequest(&httd.Request{
 		Method:   httd.MethodPut,
-		Endpoint: endpoint.GuildBan(guildID, userID) + params.URLQueryString(),
+		Endpoint: endpoint.GuildBan(guildID, userID),
+		Params:   params,
 		Ctx:      ctx,
 		Reason:   params.Reason,
 	}, flags)
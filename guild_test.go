@@ -1,8 +1,12 @@
 package disgord
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/andersfylling/disgord/internal/util"
@@ -161,3 +165,74 @@ func TestGuild_DeleteChannel(t *testing.T) {
 		t.Error("no error given when requesting a deleted channel")
 	}
 }
+
+func TestPermissionBits_UnmarshalJSON(t *testing.T) {
+	// v6 sends permissions as numbers, v8 as strings
+	for _, data := range []string{`{"permissions":2048}`, `{"permissions":"2048"}`} {
+		role := Role{}
+		if err := util.Unmarshal([]byte(data), &role); err != nil {
+			t.Fatal(err)
+		}
+		if role.Permissions != PermissionSendMessages {
+			t.Errorf("expected permissions %d from %s. Got %d", PermissionSendMessages, data, role.Permissions)
+		}
+	}
+
+	overwrite := PermissionOverwrite{}
+	if err := util.Unmarshal([]byte(`{"id":"486833611564253184","allow":"2048","deny":1024}`), &overwrite); err != nil {
+		t.Fatal(err)
+	}
+	if overwrite.ID != 486833611564253184 || overwrite.Allow != PermissionSendMessages || overwrite.Deny != PermissionReadMessages {
+		t.Errorf("unexpected overwrite %+v", overwrite)
+	}
+
+	guild := Guild{Permissions: PermissionSendMessages}
+	if err := util.Unmarshal([]byte(`{"id":"486833611564253184","name":"test"}`), &guild); err != nil {
+		t.Fatal(err)
+	}
+	if guild.Name != "test" || guild.Permissions != PermissionSendMessages {
+		t.Errorf("expected permissions to be untouched when missing. Got %+v", guild.Permissions)
+	}
+}
+
+func TestPermissionBits_APIVersion(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"486833611564253184","permissions":"2048"}`))
+	}))
+	defer srv.Close()
+
+	expects := map[int]string{6: `"permissions":2048`, 8: `"permissions":"2048"`}
+	for version, expect := range expects {
+		c, err := NewClient(Config{BotToken: "test", RESTBaseURL: srv.URL, APIVersion: version})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		role, err := c.CreateGuildRole(context.Background(), 486833611564253185, &CreateGuildRoleParams{
+			Name:        "test",
+			Permissions: PermissionSendMessages,
+		}, IgnoreCache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if role.Permissions != PermissionSendMessages {
+			t.Errorf("expected permissions %d. Got %d", PermissionSendMessages, role.Permissions)
+		}
+		if !strings.Contains(string(body), expect) {
+			t.Errorf("v%d: expected body to contain %s. Got %s", version, expect, string(body))
+		}
+
+		_, err = c.UpdateGuildRole(context.Background(), 486833611564253185, 486833611564253184).
+			SetPermissions(PermissionSendMessages).
+			Execute()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(body), expect) {
+			t.Errorf("v%d: expected update body to contain %s. Got %s", version, expect, string(body))
+		}
+	}
+}
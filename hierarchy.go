@@ -0,0 +1,243 @@
+package disgord
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/andersfylling/disgord/internal/constant"
+	"github.com/andersfylling/disgord/internal/httd"
+)
+
+// Discord only allows a member to moderate members and roles below its highest role. The guild owner
+// outranks everyone, while administrators are still restricted by the role hierarchy.
+// See https://discordapp.com/developers/docs/topics/permissions#permission-hierarchy
+
+func newErrorMissingPermissions(missing PermissionBits) *ErrorMissingPermissions {
+	return &ErrorMissingPermissions{
+		info:    "bot is missing the permissions " + strings.Join(permissionNames(missing), ", "),
+		Missing: missing,
+	}
+}
+
+// ErrorMissingPermissions is returned when the bot lacks the guild permissions for a request, see ValidatePermissions
+type ErrorMissingPermissions struct {
+	info    string
+	Missing PermissionBits
+}
+
+func (e *ErrorMissingPermissions) Error() string {
+	return e.info
+}
+
+func newErrorRoleHierarchy(message string) *ErrorRoleHierarchy {
+	return &ErrorRoleHierarchy{
+		info: message,
+	}
+}
+
+// ErrorRoleHierarchy is returned when the bot does not outrank the member or role of a request, see ValidatePermissions
+type ErrorRoleHierarchy struct {
+	info string
+}
+
+func (e *ErrorRoleHierarchy) Error() string {
+	return e.info
+}
+
+// roleAbove checks if role a is above role b. Roles with the same position are ordered by ID, as in SortRoles.
+func roleAbove(a, b *Role) bool {
+	if a.Position == b.Position {
+		return a.ID < b.ID
+	}
+	return a.Position > b.Position
+}
+
+func (g *Guild) highestRole(member *Member) (highest *Role) {
+	for _, role := range g.Roles {
+		if role.ID != g.ID && !memberHasRole(member, role.ID) {
+			continue // every member has the @everyone role
+		}
+		if highest == nil || roleAbove(role, highest) {
+			highest = role
+		}
+	}
+	return highest
+}
+
+func memberHasRole(member *Member, roleID Snowflake) bool {
+	for _, id := range member.Roles {
+		if id == roleID {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Guild) isOwner(member *Member) bool {
+	return !g.OwnerID.IsZero() && g.OwnerID == memberUserID(member)
+}
+
+// HighestRole returns the highest role of the member, which is the @everyone role when the member
+// has no other roles. Nil is returned when the guild roles are unknown.
+func (g *Guild) HighestRole(member *Member) *Role {
+	if constant.LockedMethods {
+		g.RLock()
+		defer g.RUnlock()
+	}
+
+	return g.highestRole(member)
+}
+
+// CanManageMember checks if the actor outranks the target, and can therefore kick, ban or change the nickname
+// of the target given the required permissions. The guild owner can not be managed, and members can not
+// manage themselves nor members with an equally high role.
+func (g *Guild) CanManageMember(actor, target *Member) bool {
+	if constant.LockedMethods {
+		g.RLock()
+		defer g.RUnlock()
+	}
+
+	switch {
+	case g.isOwner(target):
+		return false
+	case memberUserID(actor) == memberUserID(target):
+		return false
+	case g.isOwner(actor):
+		return true
+	}
+
+	actorRole, targetRole := g.highestRole(actor), g.highestRole(target)
+	if actorRole == nil {
+		return false
+	}
+	return targetRole == nil || roleAbove(actorRole, targetRole)
+}
+
+// CanManageRole checks if the actor outranks the role, and can therefore assign, update or delete the role
+// given the required permissions.
+func (g *Guild) CanManageRole(actor *Member, role *Role) bool {
+	if constant.LockedMethods {
+		g.RLock()
+		defer g.RUnlock()
+	}
+
+	if g.isOwner(actor) {
+		return true
+	}
+	highest := g.highestRole(actor)
+	return highest != nil && roleAbove(highest, role)
+}
+
+// CanManageMember checks if the actor outranks the target, see Guild.CanManageMember.
+func (c *Client) CanManageMember(ctx context.Context, guildID, actorID, targetID Snowflake, flags ...Flag) (bool, error) {
+	guild, err := c.GetGuild(ctx, guildID, flags...)
+	if err != nil {
+		return false, err
+	}
+	actor, err := c.GetMember(ctx, guildID, actorID, flags...)
+	if err != nil {
+		return false, err
+	}
+	target, err := c.GetMember(ctx, guildID, targetID, flags...)
+	if err != nil {
+		return false, err
+	}
+
+	return guild.CanManageMember(actor, target), nil
+}
+
+// CanManageRole checks if the actor outranks the role, see Guild.CanManageRole.
+func (c *Client) CanManageRole(ctx context.Context, guildID, actorID, roleID Snowflake, flags ...Flag) (bool, error) {
+	guild, err := c.GetGuild(ctx, guildID, flags...)
+	if err != nil {
+		return false, err
+	}
+	role, err := guild.Role(roleID)
+	if err != nil {
+		return false, err
+	}
+	actor, err := c.GetMember(ctx, guildID, actorID, flags...)
+	if err != nil {
+		return false, err
+	}
+
+	return guild.CanManageRole(actor, role), nil
+}
+
+// cachedMember looks up the member in the cache before requesting it, such that validating a moderation
+// request does not cost additional requests for members that are already known. See ValidatePermissions
+func (c *Client) cachedMember(ctx context.Context, guildID, userID Snowflake, flags []Flag) (*Member, error) {
+	if !mergeFlags(flags).Ignorecache() {
+		if member, err := c.cache.GetGuildMember(guildID, userID); err == nil && member != nil {
+			return member, nil
+		}
+	}
+	return c.GetMember(ctx, guildID, userID, flags...)
+}
+
+// validateModeration checks that the bot has the required guild permissions, see ValidatePermissions
+func (c *Client) validateModeration(ctx context.Context, guildID Snowflake, required PermissionBits, flags []Flag) (guild *Guild, bot *Member, err error) {
+	if guild, err = c.GetGuild(ctx, guildID, flags...); err != nil {
+		return nil, nil, err
+	}
+
+	botID := c.botID()
+	if botID.IsZero() {
+		var usr *User
+		if usr, err = c.GetCurrentUser(ctx, flags...); err != nil {
+			return nil, nil, err
+		}
+		botID = usr.ID
+	}
+	if bot, err = c.cachedMember(ctx, guildID, botID, flags); err != nil {
+		return nil, nil, err
+	}
+
+	if missing := required &^ memberBasePermissions(guild, bot); missing != 0 {
+		return nil, nil, newErrorMissingPermissions(missing)
+	}
+	return guild, bot, nil
+}
+
+// validateMemberModeration checks that the bot has the required guild permissions, and outranks the target.
+// Users that are not a member of the guild, eg. when banned by ID, are not restricted by the role hierarchy.
+func (c *Client) validateMemberModeration(ctx context.Context, guildID, userID Snowflake, required PermissionBits, flags []Flag) error {
+	guild, bot, err := c.validateModeration(ctx, guildID, required, flags)
+	if err != nil {
+		return err
+	}
+
+	target, err := c.cachedMember(ctx, guildID, userID, flags)
+	if err != nil {
+		if errRest, ok := err.(*httd.ErrREST); ok && errRest.HTTPCode == http.StatusNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if !guild.CanManageMember(bot, target) {
+		return newErrorRoleHierarchy("bot does not outrank member " + userID.String())
+	}
+	return nil
+}
+
+// validateRoleModeration checks that the bot has the required guild permissions, and outranks the role
+func (c *Client) validateRoleModeration(ctx context.Context, guildID, roleID Snowflake, required PermissionBits, flags []Flag) error {
+	guild, bot, err := c.validateModeration(ctx, guildID, required, flags)
+	if err != nil {
+		return err
+	}
+
+	role, err := guild.Role(roleID)
+	if err != nil {
+		return err
+	}
+	if role.Managed {
+		return newErrorRoleHierarchy("role " + roleID.String() + " is managed by an integration")
+	}
+	if !guild.CanManageRole(bot, role) {
+		return newErrorRoleHierarchy("bot does not outrank role " + roleID.String())
+	}
+	return nil
+}
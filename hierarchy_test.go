@@ -0,0 +1,170 @@
+package disgord
+
+import (
+	"context"
+	"testing"
+)
+
+const (
+	testGuildID  Snowflake = 486833611564253184
+	testOwnerID  Snowflake = 486833611564253185
+	testAdminID  Snowflake = 486833611564253186
+	testModID    Snowflake = 486833611564253187
+	testRoleMod  Snowflake = 486833611564253188
+	testRoleMod2 Snowflake = 486833611564253189
+	testRoleHigh Snowflake = 486833611564253190
+	testRoleAdm  Snowflake = 486833611564253191
+)
+
+func newTestHierarchyGuild() *Guild {
+	return &Guild{
+		ID:      testGuildID,
+		OwnerID: testOwnerID,
+		Roles: []*Role{
+			{ID: testGuildID, Position: 0},
+			{ID: testRoleMod, Position: 1, Permissions: PermissionKickMembers},
+			{ID: testRoleMod2, Position: 1, Permissions: PermissionBanMembers}, // created after testRoleMod
+			{ID: testRoleHigh, Position: 2},
+			{ID: testRoleAdm, Position: 3, Permissions: PermissionAdministrator},
+		},
+	}
+}
+
+func newTestMember(userID Snowflake, roles ...Snowflake) *Member {
+	return &Member{GuildID: testGuildID, User: &User{ID: userID}, Roles: roles}
+}
+
+func TestGuild_HighestRole(t *testing.T) {
+	guild := newTestHierarchyGuild()
+	tests := []struct {
+		name    string
+		roles   []Snowflake
+		expects Snowflake
+	}{
+		{"no roles", nil, testGuildID},
+		{"single role", []Snowflake{testRoleHigh}, testRoleHigh},
+		{"highest position", []Snowflake{testRoleMod, testRoleAdm, testRoleHigh}, testRoleAdm},
+		{"position tie", []Snowflake{testRoleMod2, testRoleMod}, testRoleMod},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if role := guild.HighestRole(newTestMember(testModID, test.roles...)); role == nil || role.ID != test.expects {
+				t.Errorf("expected role %d, got %+v", test.expects, role)
+			}
+		})
+	}
+
+	if role := (&Guild{}).HighestRole(newTestMember(testModID)); role != nil {
+		t.Errorf("expected no role without guild roles, got %+v", role)
+	}
+}
+
+func TestGuild_CanManageMember(t *testing.T) {
+	guild := newTestHierarchyGuild()
+	tests := []struct {
+		name          string
+		actor, target *Member
+		expects       bool
+	}{
+		{"owner", newTestMember(testOwnerID), newTestMember(testAdminID, testRoleAdm), true},
+		{"owner can not be managed", newTestMember(testAdminID, testRoleAdm), newTestMember(testOwnerID), false},
+		{"higher role", newTestMember(testModID, testRoleHigh), newTestMember(testAdminID, testRoleMod), true},
+		{"lower role", newTestMember(testModID, testRoleMod), newTestMember(testAdminID, testRoleHigh), false},
+		{"same role", newTestMember(testModID, testRoleMod), newTestMember(testAdminID, testRoleMod), false},
+		{"position tie", newTestMember(testModID, testRoleMod), newTestMember(testAdminID, testRoleMod2), true},
+		{"position tie reversed", newTestMember(testModID, testRoleMod2), newTestMember(testAdminID, testRoleMod), false},
+		{"target without roles", newTestMember(testModID, testRoleMod), newTestMember(testAdminID), true},
+		{"actor without roles", newTestMember(testModID), newTestMember(testAdminID), false},
+		{"administrator below target", newTestMember(testAdminID, testRoleMod2, testRoleAdm), newTestMember(testModID, testRoleAdm, testRoleHigh), false},
+		{"self", newTestMember(testModID, testRoleAdm), newTestMember(testModID, testRoleAdm), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if can := guild.CanManageMember(test.actor, test.target); can != test.expects {
+				t.Errorf("expected %t, got %t", test.expects, can)
+			}
+		})
+	}
+}
+
+func TestGuild_CanManageRole(t *testing.T) {
+	guild := newTestHierarchyGuild()
+	role := func(id Snowflake) *Role {
+		r, err := guild.Role(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		actor   *Member
+		role    *Role
+		expects bool
+	}{
+		{"owner", newTestMember(testOwnerID), role(testRoleAdm), true},
+		{"lower role", newTestMember(testModID, testRoleHigh), role(testRoleMod), true},
+		{"own highest role", newTestMember(testModID, testRoleHigh), role(testRoleHigh), false},
+		{"higher role", newTestMember(testModID, testRoleMod), role(testRoleHigh), false},
+		{"position tie", newTestMember(testModID, testRoleMod), role(testRoleMod2), true},
+		{"everyone", newTestMember(testModID, testRoleMod), role(testGuildID), true},
+		{"without roles", newTestMember(testModID), role(testGuildID), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if can := guild.CanManageRole(test.actor, test.role); can != test.expects {
+				t.Errorf("expected %t, got %t", test.expects, can)
+			}
+		})
+	}
+}
+
+func TestClient_ValidatePermissions(t *testing.T) {
+	c := New(Config{BotToken: "testing"})
+	c.myID = testModID
+
+	guild := newTestHierarchyGuild()
+	guild.Roles[3].Permissions = PermissionManageRoles // testRoleHigh
+	guild.Roles = append(guild.Roles, &Role{ID: 486833611564253192, Position: 1, Managed: true})
+	guild.Members = []*Member{
+		newTestMember(testModID, testRoleMod, testRoleHigh),
+		newTestMember(testAdminID, testRoleAdm),
+		newTestMember(testOwnerID),
+	}
+	c.cache.SetGuild(guild)
+
+	ctx := context.Background()
+	t.Run("missing permissions", func(t *testing.T) {
+		err := c.BanMember(ctx, testGuildID, testAdminID, &BanMemberParams{}, ValidatePermissions)
+		if e, ok := err.(*ErrorMissingPermissions); !ok || e.Missing != PermissionBanMembers {
+			t.Errorf("expected missing ban permissions, got %v", err)
+		}
+		if err != nil && err.Error() != "bot is missing the permissions BAN_MEMBERS" {
+			t.Errorf("expected the permission to be named, got %s", err)
+		}
+
+		err = newErrorMissingPermissions(PermissionKickMembers | PermissionManageRoles | 1<<40)
+		if expects := "bot is missing the permissions KICK_MEMBERS, MANAGE_ROLES, 1099511627776"; err.Error() != expects {
+			t.Errorf("expected %s, got %s", expects, err)
+		}
+	})
+	t.Run("member hierarchy", func(t *testing.T) {
+		err := c.KickMember(ctx, testGuildID, testAdminID, "", ValidatePermissions)
+		if _, ok := err.(*ErrorRoleHierarchy); !ok {
+			t.Errorf("expected a role hierarchy error, got %v", err)
+		}
+	})
+	t.Run("role hierarchy", func(t *testing.T) {
+		err := c.AddGuildMemberRole(ctx, testGuildID, testAdminID, testRoleAdm, ValidatePermissions)
+		if _, ok := err.(*ErrorRoleHierarchy); !ok {
+			t.Errorf("expected a role hierarchy error, got %v", err)
+		}
+	})
+	t.Run("managed role", func(t *testing.T) {
+		err := c.AddGuildMemberRole(ctx, testGuildID, testAdminID, 486833611564253192, ValidatePermissions)
+		if _, ok := err.(*ErrorRoleHierarchy); !ok {
+			t.Errorf("expected a role hierarchy error, got %v", err)
+		}
+	})
+}
@@ -71,6 +71,8 @@ func (g *Guild) Reset() {
 	g.WidgetEnabled = false
 	g.WidgetChannelID = 0
 	g.SystemChannelID = 0
+	g.PremiumTier = 0
+	g.PremiumSubscriptionCount = 0
 	g.JoinedAt = nil
 	g.Large = false
 	g.Unavailable = false
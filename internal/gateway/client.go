@@ -102,6 +102,10 @@ type config struct {
 
 	discordErrListener discordErrListener
 
+	// unrecoverableCloseCode stops the client from reconnecting when Discord closed the connection
+	// with the given close code. Optional.
+	unrecoverableCloseCode func(code int) bool
+
 	// messageQueueLimit number of outgoing messages that can be queued and sent correctly.
 	messageQueueLimit uint
 
@@ -269,15 +273,20 @@ func (c *client) getLogPrefix() string {
 func (c *client) disconnect() (err error) {
 	c.Lock()
 	defer c.Unlock()
-	if c.conn.Disconnected() || !c.haveConnectedOnce.Load() || c.cancel == nil {
+	if c.cancel == nil {
 		_ = c.conn.Close() // just to be safe, but ignore errors
 		c.isConnected.Store(false)
 		return errors.New("already disconnected")
 	}
 
-	// stop emitter, receiver and behaviors
+	// stop emitter, receiver and behaviors. Also when Discord closed the connection,
+	// otherwise the heartbeats of the old connection keeps going.
 	c.cancel()
 	c.cancel = nil
+	if c.conn.Disconnected() || !c.haveConnectedOnce.Load() {
+		c.isConnected.Store(false)
+		return errors.New("already disconnected")
+	}
 
 	// use the emitter to dispatch the close message
 	err = c.conn.Close()
@@ -503,6 +512,17 @@ func (c *client) receiver(ctx context.Context) {
 		if packet, err = c.conn.Read(context.Background()); err != nil {
 			if e, ok := err.(*CloseErr); ok && c.conf.discordErrListener != nil && e.code >= 4000 && e.code < 5000 {
 				go c.conf.discordErrListener(e.code, e.info)
+				if e.code == discordErrShardScalingRequired {
+					// the shard manager disconnects and re-identifies every shard with the new shard count
+					c.log.Debug(c.getLogPrefix(), "closing receiver, shards must scale")
+					once.Do(cancel)
+					return
+				}
+				if c.conf.unrecoverableCloseCode != nil && c.conf.unrecoverableCloseCode(e.code) {
+					c.log.Debug(c.getLogPrefix(), "closing receiver, close code ", e.code, " can not be recovered from")
+					once.Do(cancel)
+					return
+				}
 			}
 			if _, ok := err.(*CloseErr); !ok {
 				c.log.Debug(c.getLogPrefix(), err)
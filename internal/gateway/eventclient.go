@@ -49,6 +49,8 @@ func NewEventClient(shardID uint, conf *EvtConfig) (client *EvtClient, err error
 		conn:              conf.conn,
 		messageQueueLimit: conf.MessageQueueLimit,
 
+		discordErrListener: conf.discordErrListener,
+
 		SystemShutdown: conf.SystemShutdown,
 	}, client.internalConnect)
 	if err != nil {
@@ -82,6 +84,9 @@ type Event struct {
 	Name    string
 	Data    []byte
 	ShardID uint
+
+	// Sequence is the gateway sequence number of the event, which is unique for the shard session
+	Sequence uint64
 }
 
 // EvtConfig ws
@@ -283,9 +288,10 @@ func (c *EvtClient) onDiscordEvent(v interface{}) (err error) {
 
 	// dispatch event through out the DisGord system
 	c.eventChan <- &Event{
-		Name:    p.EventName,
-		Data:    p.Data,
-		ShardID: c.ShardID,
+		Name:     p.EventName,
+		Data:     p.Data,
+		ShardID:  c.ShardID,
+		Sequence: p.SequenceNumber,
 	}
 
 	return nil
@@ -428,6 +434,7 @@ func (c *EvtClient) openConnection(ctx context.Context) error {
 	// we can now interact with Discord
 	c.haveConnectedOnce.Store(true)
 	c.isConnected.Store(true)
+	c.requestedDisconnect.Store(false)
 	go c.receiver(ctx)
 	go c.emitter(ctx)
 	go c.startBehaviors(ctx)
@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"go.uber.org/atomic"
 
+	"github.com/andersfylling/disgord/disgordtest"
 	"github.com/andersfylling/disgord/internal/constant"
+	"github.com/andersfylling/disgord/internal/event"
 	"github.com/andersfylling/disgord/internal/gateway/cmd"
 	"github.com/andersfylling/disgord/internal/gateway/opcode"
 	"github.com/andersfylling/disgord/internal/logger"
@@ -266,3 +269,108 @@ func TestEvtClient_communication(t *testing.T) {
 
 	<-time.After(10 * time.Millisecond)
 }
+
+func TestEvtClient_fakeGateway(t *testing.T) {
+	srv := disgordtest.NewGatewayServer()
+	defer srv.Close()
+
+	eChan := make(chan *Event, 100)
+	shutdown := make(chan interface{})
+	defer close(shutdown)
+
+	m, err := NewEventClient(0, &EvtConfig{
+		Browser:             "disgord",
+		Device:              "disgord",
+		GuildLargeThreshold: 250,
+		ShardCount:          1,
+
+		Endpoint: srv.URL(),
+		Version:  constant.DiscordVersion,
+		Encoding: constant.JSONEncoding,
+		Logger:   &logger.Empty{},
+
+		BotToken: disgordtest.DefaultBotToken,
+		DiscordPktPool: &sync.Pool{
+			New: func() interface{} {
+				return &DiscordPacket{}
+			},
+		},
+		connectQueue: func(shardID uint, cb func() error) error {
+			return cb()
+		},
+
+		EventChan:      eChan,
+		SystemShutdown: shutdown,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.timeoutMultiplier = 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	waitForEvent := func(name string) *Event {
+		for {
+			select {
+			case evt := <-eChan:
+				if evt.Name == name {
+					return evt
+				}
+			case <-ctx.Done():
+				t.Fatal("did not receive event", name)
+			}
+		}
+	}
+
+	if err = m.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Disconnect()
+
+	session, err := srv.WaitForShard(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForEvent(event.Ready)
+
+	if err = srv.Dispatch(0, event.MessageCreate, map[string]string{"content": "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if evt := waitForEvent(event.MessageCreate); !strings.Contains(string(evt.Data), "hello") {
+		t.Errorf("unexpected event data %s", string(evt.Data))
+	}
+
+	// both a reconnect request and a resumable close code must result in a resume
+	resumed := func() {
+		srv.ClearCommands()
+		if _, err := srv.WaitForCommand(ctx, disgordtest.OpResume, nil); err != nil {
+			t.Fatal(err)
+		}
+		waitForEvent(event.Resumed)
+		if s, err := srv.WaitForShard(ctx, 0); err != nil || s.ID != session.ID {
+			t.Errorf("expected session %s to be resumed. Got %+v, %v", session.ID, s, err)
+		}
+	}
+	if err = srv.Reconnect(0); err != nil {
+		t.Fatal(err)
+	}
+	resumed()
+	if err = srv.CloseShard(0, disgordtest.CloseUnknownError, "unknown error"); err != nil {
+		t.Fatal(err)
+	}
+	resumed()
+
+	// an invalid session that can not be resumed forces a new identify
+	srv.ClearCommands()
+	if err = srv.InvalidateSession(0, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = srv.WaitForCommand(ctx, disgordtest.OpIdentify, nil); err != nil {
+		t.Fatal(err)
+	}
+	waitForEvent(event.Ready)
+	if s, err := srv.WaitForShard(ctx, 0); err != nil || s.ID == session.ID {
+		t.Errorf("expected a new session. Got %+v, %v", s, err)
+	}
+}
@@ -81,6 +81,17 @@ type VoiceSessionDescription struct {
 	SecretKey [32]byte `json:"secret_key"`
 }
 
+// VoiceSpeaking is sent by Discord when a user starts speaking, and maps the SSRC of the audio to the user
+type VoiceSpeaking struct {
+	UserID Snowflake `json:"user_id"`
+	SSRC   uint32    `json:"ssrc"`
+}
+
+// VoiceClientDisconnect is sent by Discord when a user leaves the voice channel
+type VoiceClientDisconnect struct {
+	UserID Snowflake `json:"user_id"`
+}
+
 type voiceIdentify struct {
 	GuildID   Snowflake `json:"server_id"` // Yay for eventual consistency
 	UserID    Snowflake `json:"user_id"`
@@ -202,6 +213,11 @@ type helloPacket struct {
 	HeartbeatInterval uint `json:"heartbeat_interval"`
 }
 
+// voiceHelloPacket holds a float interval since voice gateway v4, eg. 41250.0
+type voiceHelloPacket struct {
+	HeartbeatInterval float64 `json:"heartbeat_interval"`
+}
+
 // discordPacketJSON is used when we need to fall back on the unmarshaler logic
 type discordPacketJSON struct {
 	Op             opcode.OpCode `json:"op"`
@@ -273,13 +273,13 @@ func (s *shardMngr) initShards() error {
 				s.conf.ShardCount, newShards = s.conf.OnScalingRequired(s.ShardIDs())
 				s.conf.ShardIDs = append(s.conf.ShardIDs, newShards...)
 
-				_ = s.Disconnect()
+				_ = s.disconnect()
 				if err := s.initShards(); err != nil {
 					s.conf.Logger.Error("scaling", "init-shards", err)
 					return
 				}
 				s.conf.Logger.Info("scaling", "connecting shards")
-				if err := s.Connect(); err != nil {
+				if err := s.connect(); err != nil {
 					s.conf.Logger.Error("scaling", "connect", err)
 				}
 				s.conf.Logger.Info("scaling", "connected")
@@ -291,6 +291,9 @@ func (s *shardMngr) initShards() error {
 	for _, id := range s.conf.ShardIDs {
 		if shard, alreadyConfigured := s.shards[id]; alreadyConfigured {
 			shard.evtConf.ShardCount = s.conf.ShardCount
+			shard.idMu.Lock()
+			shard.identity.Shard = &[2]uint{id, s.conf.ShardCount}
+			shard.idMu.Unlock()
 			continue
 		}
 
@@ -308,7 +311,11 @@ func (s *shardMngr) initShards() error {
 func (s *shardMngr) Connect() (err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.connect()
+}
 
+// connect requires the lock to be held
+func (s *shardMngr) connect() (err error) {
 	if len(s.conf.ShardIDs) == 0 {
 		return errors.New("no shard ids has been registered")
 	}
@@ -327,10 +334,15 @@ func (s *shardMngr) Connect() (err error) {
 	}
 	return nil
 }
+
 func (s *shardMngr) Disconnect() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.disconnect()
+}
 
+// disconnect requires the lock to be held
+func (s *shardMngr) disconnect() error {
 	for _, shard := range s.shards {
 		err := shard.Disconnect()
 		if err != nil {
@@ -371,7 +383,11 @@ func (s *shardMngr) ShardIDs() (shardIDs []uint) {
 func (s *shardMngr) Emit(cmd string, payload CmdPayload) (guildIDs []Snowflake, err error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	return s.emit(cmd, payload)
+}
 
+// emit requires the lock to be held
+func (s *shardMngr) emit(cmd string, payload CmdPayload) (guildIDs []Snowflake, err error) {
 	if len(s.shards) == 0 {
 		return nil, errors.New("can not use Emit before Connected")
 	}
@@ -386,7 +402,7 @@ func (s *shardMngr) Emit(cmd string, payload CmdPayload) (guildIDs []Snowflake,
 
 		requests := make(map[uint][]Snowflake)
 		for i := range t.GuildIDs {
-			shardID := GetShardForGuildID(t.GuildIDs[i], s.ShardCount())
+			shardID := GetShardForGuildID(t.GuildIDs[i], s.conf.ShardCount)
 			requests[shardID] = append(requests[shardID], t.GuildIDs[i])
 		}
 
@@ -403,7 +419,7 @@ func (s *shardMngr) Emit(cmd string, payload CmdPayload) (guildIDs []Snowflake,
 			}
 		}
 	case *UpdateVoiceStatePayload:
-		shardID := GetShardForGuildID(t.GuildID, s.ShardCount())
+		shardID := GetShardForGuildID(t.GuildID, s.conf.ShardCount)
 		if shard, ok := s.shards[shardID]; ok {
 			err = shard.Emit(cmd, payload)
 		} else {
@@ -458,10 +474,10 @@ func (s *shardMngr) scale(code int, reason string) {
 			return
 		}
 
-		_ = s.Disconnect()
+		_ = s.disconnect()
 
 		s.conf.URL = data.URL
-		for i := uint(len(s.conf.ShardIDs) - 1); i < data.Shards; i++ {
+		for i := uint(len(s.conf.ShardIDs)); i < data.Shards; i++ {
 			s.conf.ShardIDs = append(s.conf.ShardIDs, i)
 			s.conf.ShardCount++
 		}
@@ -469,7 +485,7 @@ func (s *shardMngr) scale(code int, reason string) {
 			s.conf.Logger.Error("autoscaling", "init-shards", err)
 			return
 		}
-		if err := s.Connect(); err != nil {
+		if err := s.connect(); err != nil {
 			s.conf.Logger.Error("autoscaling", "connect", err)
 		}
 	})
@@ -524,7 +540,7 @@ func (s *shardMngr) redistributeMsgs(scaleShards func()) (unhandledGuildIDs []Sn
 		}
 
 		if payload, ok := m.Data.(CmdPayload); ok {
-			gIDs, _ := s.Emit(m.CmdName, payload)
+			gIDs, _ := s.emit(m.CmdName, payload)
 			unhandledGuildIDs = append(unhandledGuildIDs, gIDs...)
 			messages[i] = nil
 		}
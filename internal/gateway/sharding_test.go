@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/andersfylling/disgord/disgordtest"
 	"github.com/andersfylling/disgord/internal/event"
 	"github.com/andersfylling/disgord/internal/gateway/cmd"
 	"github.com/andersfylling/disgord/internal/logger"
@@ -244,3 +245,69 @@ func TestIdentifyRateLimiting(t *testing.T) {
 		}
 	}
 }
+
+func TestShardMngr_scale(t *testing.T) {
+	srv := disgordtest.NewGatewayServer()
+	defer srv.Close()
+
+	mock := &GatewayBotGetterMock{
+		get: func() (gateway *GatewayBot, err error) {
+			return &GatewayBot{
+				Shards:  srv.RequiredShards(),
+				Gateway: Gateway{srv.URL()},
+			}, nil
+		},
+	}
+	config := ShardManagerConfig{
+		ShardConfig: ShardConfig{
+			ShardRateLimit: time.Millisecond,
+		},
+		BotToken:     disgordtest.DefaultBotToken,
+		ShutdownChan: make(chan interface{}),
+		EventChan:    make(chan *Event, 100),
+		Logger:       &logger.Empty{},
+		RESTClient:   mock,
+	}
+	defer close(config.ShutdownChan)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := ConfigureShardConfig(ctx, mock, &config.ShardConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	mngr := NewShardMngr(config)
+	if err := mngr.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer mngr.Disconnect()
+	if _, err := srv.WaitForShard(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Discord closes the connection with 4011 when more shards are required
+	srv.RequireShards(2)
+	for id := uint(0); id < 2; id++ {
+		_, err := srv.WaitForSession(ctx, func(session disgordtest.GatewaySession) bool {
+			return session.ShardID == id && session.ShardCount == 2
+		})
+		if err != nil {
+			t.Fatal("shard", id, err)
+		}
+	}
+	if mngr.ShardCount() != 2 || mngr.LocalShardCount() != 2 {
+		t.Errorf("expected 2 shards. Got %d total and %d local", mngr.ShardCount(), mngr.LocalShardCount())
+	}
+
+	if _, err := mngr.Emit(cmd.UpdateStatus, &UpdateStatusPayload{Status: "idle"}); err != nil {
+		t.Fatal(err)
+	}
+	for id := 0; id < 2; id++ {
+		_, err := srv.WaitForCommand(ctx, disgordtest.OpStatusUpdate, func(c disgordtest.GatewayCommand) bool {
+			return c.ShardID == id
+		})
+		if err != nil {
+			t.Error("shard", id, err)
+		}
+	}
+}
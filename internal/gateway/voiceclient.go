@@ -15,6 +15,33 @@ import (
 	"github.com/andersfylling/disgord/internal/logger"
 )
 
+// voice close codes, see https://discordapp.com/developers/docs/topics/opcodes-and-status-codes#voice-voice-close-event-codes
+const (
+	VoiceCloseNotAuthenticated     = 4003
+	VoiceCloseAuthenticationFailed = 4004
+	VoiceCloseAlreadyAuthenticated = 4005
+	VoiceCloseSessionNoLongerValid = 4006
+	VoiceCloseSessionTimeout       = 4009
+	VoiceCloseServerNotFound       = 4011
+	VoiceCloseUnknownProtocol      = 4012
+	VoiceCloseDisconnected         = 4014
+	VoiceCloseServerCrashed        = 4015
+	VoiceCloseUnknownEncryption    = 4016
+)
+
+// voiceCloseCodeResumable checks if the voice session can be resumed after Discord closed the connection.
+// Otherwise the voice client stops reconnecting, and a new voice client must be created.
+func voiceCloseCodeResumable(code int) bool {
+	switch code {
+	case VoiceCloseNotAuthenticated, VoiceCloseAuthenticationFailed, VoiceCloseAlreadyAuthenticated,
+		VoiceCloseSessionNoLongerValid, VoiceCloseSessionTimeout, VoiceCloseServerNotFound,
+		VoiceCloseUnknownProtocol, VoiceCloseDisconnected, VoiceCloseUnknownEncryption:
+		return false
+	default:
+		return true
+	}
+}
+
 type VoiceConfig struct {
 	// Guild ID to connect to
 	GuildID Snowflake
@@ -40,6 +67,22 @@ type VoiceConfig struct {
 	Logger logger.Logger
 
 	SystemShutdown chan interface{}
+
+	// OnSpeaking is called when a user in the voice channel starts speaking
+	OnSpeaking func(evt *VoiceSpeaking)
+
+	// OnClientDisconnect is called when a user leaves the voice channel
+	OnClientDisconnect func(evt *VoiceClientDisconnect)
+
+	// OnResuming is called when the connection was lost, before every attempt to resume the session
+	OnResuming func()
+
+	// OnResumed is called when the session was resumed
+	OnResumed func()
+
+	// OnClose is called when Discord closed the connection with a voice close code. The voice client does
+	// not reconnect when the session can not be resumed, eg. VoiceCloseSessionNoLongerValid.
+	OnClose func(code int, reason string)
 }
 
 type VoiceClient struct {
@@ -48,6 +91,9 @@ type VoiceClient struct {
 
 	haveIdentifiedOnce bool
 
+	// heartbeatNonce is echoed by Discord in the heartbeat ACK
+	heartbeatNonce uint64
+
 	SystemShutdown chan interface{}
 }
 
@@ -70,6 +116,14 @@ func NewVoiceClient(conf *VoiceConfig) (client *VoiceClient, err error) {
 		},
 		messageQueueLimit: conf.MessageQueueLimit,
 		SystemShutdown:    conf.SystemShutdown,
+		discordErrListener: func(code int, reason string) {
+			if conf.OnClose != nil {
+				conf.OnClose(code, reason)
+			}
+		},
+		unrecoverableCloseCode: func(code int) bool {
+			return !voiceCloseCodeResumable(code)
+		},
 	}, client.internalConnect)
 	if err != nil {
 		return nil, err
@@ -97,6 +151,9 @@ func (c *VoiceClient) setupBehaviors() {
 			opcode.VoiceHeartbeatAck:       c.onHeartbeatAck,
 			opcode.VoiceHello:              c.onHello,
 			opcode.VoiceSessionDescription: c.onVoiceSessionDescription,
+			opcode.VoiceSpeaking:           c.onSpeaking,
+			opcode.VoiceClientDisconnect:   c.onClientDisconnect,
+			opcode.VoiceResumed:            c.onResumed,
 		},
 	})
 
@@ -130,33 +187,52 @@ func (c *VoiceClient) onReady(v interface{}) (err error) {
 	return nil
 }
 
+func (c *VoiceClient) onResumed(v interface{}) (err error) {
+	if ch := c.onceChannels.Acquire(opcode.VoiceResumed); ch != nil {
+		ch <- struct{}{}
+	}
+	if c.conf.OnResumed != nil {
+		c.conf.OnResumed()
+	}
+	return nil
+}
+
 func (c *VoiceClient) onHeartbeatRequest(v interface{}) error {
 	// https://discordapp.com/developers/docs/topics/gateway#heartbeating
-	return c.emit(cmd.VoiceHeartbeat, nil)
+	return c.sendHeartbeat(nil)
 }
 
-func (c *VoiceClient) onHeartbeatAck(v interface{}) error {
-	// heartbeat received
+func (c *VoiceClient) onHeartbeatAck(v interface{}) (err error) {
+	p := v.(*DiscordPacket)
+
+	var nonce uint64
+	if err = util.Unmarshal(p.Data, &nonce); err != nil {
+		return err
+	}
+
 	c.Lock()
+	defer c.Unlock()
+	if nonce != c.heartbeatNonce {
+		// an ACK of an older heartbeat, the latest heartbeat is still not acknowledged
+		c.log.Debug(c.getLogPrefix(), "ignored heartbeat ACK with nonce ", nonce)
+		return nil
+	}
 	c.lastHeartbeatAck = time.Now()
-	c.Unlock()
-
+	c.heartbeatLatency = c.lastHeartbeatAck.Sub(c.lastHeartbeatSent)
 	return nil
 }
 
 func (c *VoiceClient) onHello(v interface{}) (err error) {
 	p := v.(*DiscordPacket)
 
-	helloPk := &helloPacket{}
+	helloPk := &voiceHelloPacket{}
 	if err = util.Unmarshal(p.Data, helloPk); err != nil {
 		return err
 	}
 	c.Lock()
-	// From: https://discordapp.com/developers/docs/topics/voice-connections#heartbeating
-	// There is currently a bug in the Hello payload heartbeat interval.
-	// Until it is fixed, please take your heartbeat interval as `heartbeat_interval` * .75.
-	// TODO This warning will be removed and a changelog published when the bug is fixed.
-	c.heartbeatInterval = uint(float64(helloPk.HeartbeatInterval) * .75)
+	// the interval bug of earlier versions, which required the interval to be multiplied
+	// by .75, is fixed in v4
+	c.heartbeatInterval = uint(helloPk.HeartbeatInterval)
 	c.Unlock()
 
 	c.activateHeartbeats <- true
@@ -179,14 +255,51 @@ func (c *VoiceClient) onVoiceSessionDescription(v interface{}) (err error) {
 	return nil
 }
 
+func (c *VoiceClient) onSpeaking(v interface{}) (err error) {
+	if c.conf.OnSpeaking == nil {
+		return nil
+	}
+	p := v.(*DiscordPacket)
+
+	speakingPk := &VoiceSpeaking{}
+	if err = util.Unmarshal(p.Data, speakingPk); err != nil {
+		return err
+	}
+
+	c.conf.OnSpeaking(speakingPk)
+	return nil
+}
+
+func (c *VoiceClient) onClientDisconnect(v interface{}) (err error) {
+	if c.conf.OnClientDisconnect == nil {
+		return nil
+	}
+	p := v.(*DiscordPacket)
+
+	disconnectPk := &VoiceClientDisconnect{}
+	if err = util.Unmarshal(p.Data, disconnectPk); err != nil {
+		return err
+	}
+
+	c.conf.OnClientDisconnect(disconnectPk)
+	return nil
+}
+
 //////////////////////////////////////////////////////
 //
 // BEHAVIOR: heartbeat
 //
 //////////////////////////////////////////////////////
 
+// sendHeartbeat sends a heartbeat with a new nonce, see onHeartbeatAck
 func (c *VoiceClient) sendHeartbeat(i interface{}) error {
-	return c.emit(cmd.VoiceHeartbeat, nil)
+	// https://discordapp.com/developers/docs/topics/voice-connections#heartbeating
+	c.Lock()
+	c.heartbeatNonce = uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	nonce := c.heartbeatNonce
+	c.Unlock()
+
+	return c.emit(cmd.VoiceHeartbeat, nonce)
 }
 
 //////////////////////////////////////////////////////
@@ -217,10 +330,20 @@ func (c *VoiceClient) internalConnect() (evt interface{}, err error) {
 		panic("missing websocket endpoint. Must be set before constructing the sockets")
 	}
 
+	// a new session is ready once Discord sends READY, while a resumed session
+	// is ready on RESUMED. See sendVoiceHelloPacket
+	waitFor := opcode.VoiceReady
+	if c.haveIdentifiedOnce {
+		waitFor = opcode.VoiceResumed
+		if c.conf.OnResuming != nil {
+			c.conf.OnResuming()
+		}
+	}
+
 	waitingChan := make(chan interface{}, 2)
-	c.onceChannels.Add(opcode.VoiceReady, waitingChan)
+	c.onceChannels.Add(waitFor, waitingChan)
 	defer func() {
-		c.onceChannels.Acquire(opcode.VoiceReady)
+		c.onceChannels.Acquire(waitFor)
 		close(waitingChan)
 	}()
 
@@ -254,7 +377,7 @@ func (c *VoiceClient) internalConnect() (evt interface{}, err error) {
 		c.isConnected.Store(false)
 	case <-time.After(5 * time.Second):
 		c.isConnected.Store(false)
-		err = errors.New("did not receive desired event in time. opcode " + strconv.Itoa(int(opcode.VoiceReady)))
+		err = errors.New("did not receive desired event in time. opcode " + strconv.Itoa(int(waitFor)))
 	}
 	return evt, err
 }
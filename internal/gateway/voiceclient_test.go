@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/andersfylling/disgord/internal/gateway/cmd"
+	"github.com/andersfylling/disgord/internal/logger"
+)
+
+func TestVoiceClient_heartbeat(t *testing.T) {
+	c, err := NewVoiceClient(&VoiceConfig{
+		Endpoint:       "wss://testing",
+		Logger:         logger.Empty{},
+		SystemShutdown: make(chan interface{}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.haveConnectedOnce.Store(true)
+
+	c.lastHeartbeatSent = time.Now()
+	if err = c.sendHeartbeat(nil); err != nil {
+		t.Fatal(err)
+	}
+	msg := <-c.internalEmitChan
+	if msg.CmdName != cmd.VoiceHeartbeat || msg.Data != c.heartbeatNonce {
+		t.Fatalf("expected a heartbeat with the nonce %d, got %+v", c.heartbeatNonce, msg)
+	}
+
+	ack := func(nonce uint64) {
+		if err := c.onHeartbeatAck(&DiscordPacket{Op: 6, Data: []byte(strconv.FormatUint(nonce, 10))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ack(c.heartbeatNonce - 1)
+	if _, err = c.HeartbeatLatency(); err == nil || c.lastHeartbeatAck.After(c.lastHeartbeatSent) {
+		t.Error("expected an ACK of an older heartbeat to be ignored")
+	}
+
+	time.Sleep(time.Millisecond)
+	ack(c.heartbeatNonce)
+	if latency, err := c.HeartbeatLatency(); err != nil || latency < time.Millisecond {
+		t.Errorf("expected the latency to be measured, got %s, %v", latency, err)
+	}
+}
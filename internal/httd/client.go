@@ -11,12 +11,14 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/andersfylling/disgord/internal/util"
 )
 
 // defaults and string format's for Discord interaction
 const (
+	// BaseURL is the default REST root for v6 and v7. See apiSpec for the base url of each version.
 	BaseURL = "https://discordapp.com/api"
 
 	RegexpURLSnowflakes = `\/([0-9]+)\/?`
@@ -79,6 +81,7 @@ func (e *ErrREST) Error() string {
 // Client is the httd client for handling Discord requests
 type Client struct {
 	url                          string // base url with API version
+	spec                         *apiSpec
 	reqHeader                    http.Header
 	httpClient                   *http.Client // TODO: decouple to allow better unit testing of REST requests
 	cancelRequestWhenRateLimited bool
@@ -89,20 +92,14 @@ func (c *Client) BucketGrouping() (group map[string][]string) {
 	return c.buckets.BucketGrouping()
 }
 
+// APIVersion returns the Discord API version used for every request.
+func (c *Client) APIVersion() int {
+	return c.spec.version
+}
+
 // SupportsDiscordAPIVersion check if a given discord api version is supported by this package.
 func SupportsDiscordAPIVersion(version int) bool {
-	supports := []int{
-		6,
-	}
-
-	var supported bool
-	for _, supportedVersion := range supports {
-		if supportedVersion == version {
-			supported = true
-			break
-		}
-	}
-
+	_, supported := getAPISpec(version)
 	return supported
 }
 
@@ -118,14 +115,11 @@ func copyHeader(h http.Header) http.Header {
 
 // NewClient ...
 func NewClient(conf *Config) (*Client, error) {
-	if !SupportsDiscordAPIVersion(conf.APIVersion) {
+	spec, supported := getAPISpec(conf.APIVersion)
+	if !supported {
 		return nil, errors.New(fmt.Sprintf("Discord API version %d is not supported", conf.APIVersion))
 	}
 
-	if conf.BotToken == "" {
-		return nil, errors.New("no Discord Bot Token was provided")
-	}
-
 	// if no http client was provided, create a new one
 	if conf.HTTPClient == nil {
 		// no need for a timeout, everything uses context.Context now
@@ -144,17 +138,21 @@ func NewClient(conf *Config) (*Client, error) {
 	}
 
 	// setup the required http request header fields
-	authorization := fmt.Sprintf(AuthorizationFormat, conf.BotToken)
 	userAgent := fmt.Sprintf(UserAgentFormat, conf.UserAgentSourceURL, conf.UserAgentVersion, conf.UserAgentExtra)
-	header := map[string][]string{
-		XRateLimitPrecision: {"millisecond"},
-		"Authorization":     {authorization},
-		"User-Agent":        {userAgent},
-		"Accept-Encoding":   {"gzip"},
+	header := spec.header(userAgent)
+	if conf.BotToken != "" {
+		header.Set("Authorization", fmt.Sprintf(AuthorizationFormat, conf.BotToken))
 	}
 
+	baseURL := conf.BaseURL
+	if baseURL == "" {
+		baseURL = spec.baseURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
 	return &Client{
-		url:        BaseURL + "/v" + strconv.Itoa(conf.APIVersion),
+		url:        baseURL + "/v" + strconv.Itoa(conf.APIVersion),
+		spec:       spec,
 		reqHeader:  header,
 		httpClient: conf.HTTPClient,
 		buckets:    conf.RESTBucketManager,
@@ -165,7 +163,13 @@ func NewClient(conf *Config) (*Client, error) {
 // sent to Discord.
 type Config struct {
 	APIVersion int
-	BotToken   string
+
+	// BotToken is optional, as some endpoints authenticate through the url. eg. webhooks with a token
+	BotToken string
+
+	// BaseURL is the REST API root without the version suffix, eg. "https://discordapp.com/api".
+	// Defaults to the Discord url of the given APIVersion when empty. Useful for proxies or for testing against a fake Discord server.
+	BaseURL string
 
 	HTTPClient *http.Client
 
@@ -218,9 +222,17 @@ func (c *Client) decodeResponseBody(resp *http.Response) (body []byte, err error
 }
 
 func (c *Client) Do(r *Request) (resp *http.Response, body []byte, err error) {
+	if err = c.spec.prepare(r); err != nil {
+		return nil, nil, err
+	}
 	if err = r.init(); err != nil {
 		return nil, nil, err
 	}
+	// the http client closes the body once sent, but the request might be dropped before that. eg. when
+	// rate limited. Streamed bodies must be closed regardless, otherwise the writer blocks forever.
+	if closer, ok := r.bodyReader.(io.Closer); ok {
+		defer closer.Close()
+	}
 
 	// create request
 	req, err := http.NewRequestWithContext(r.Ctx, r.Method.String(), c.url+r.Endpoint, r.bodyReader)
@@ -236,6 +248,11 @@ func (c *Client) Do(r *Request) (resp *http.Response, body []byte, err error) {
 		// the header is a map, so it's a shared memory resource
 		req.Header.Del(XAuditLogReason)
 	}
+	for k, vs := range r.Header {
+		for i := range vs {
+			header.Add(k, vs[i])
+		}
+	}
 	req.Header = header
 
 	// send request
@@ -254,7 +271,7 @@ func (c *Client) Do(r *Request) (resp *http.Response, body []byte, err error) {
 			}
 
 			// normalize Discord header fields
-			resp.Header, err = NormalizeDiscordHeader(resp.StatusCode, resp.Header, body)
+			resp.Header, err = normalizeDiscordHeader(resp.StatusCode, resp.Header, body, c.spec.retryAfter)
 			return resp, body, err
 		})
 	})
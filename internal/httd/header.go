@@ -37,19 +37,30 @@ func HeaderToTime(header http.Header) (t time.Time, err error) {
 }
 
 type RateLimitResponseStructure struct {
-	Message    string `json:"message"`     // A message saying you are being rate limited.
-	RetryAfter int64  `json:"retry_after"` // The number of milliseconds to wait before submitting another request.
-	Global     bool   `json:"global"`      // A value indicating if you are being globally rate limited or not
+	Message    string  `json:"message"`     // A message saying you are being rate limited.
+	RetryAfter float64 `json:"retry_after"` // The time to wait before submitting another request. Milliseconds before v8, seconds after.
+	Global     bool    `json:"global"`      // A value indicating if you are being globally rate limited or not
 }
 
 // NormalizeDiscordHeader overrides header fields with body content and make sure every header field
 // uses milliseconds and not seconds. Regards rate limits only.
 func NormalizeDiscordHeader(statusCode int, header http.Header, body []byte) (h http.Header, err error) {
+	return normalizeDiscordHeader(statusCode, header, body, time.Millisecond)
+}
+
+// normalizeDiscordHeader see NormalizeDiscordHeader. The unit of Retry-After changed
+// from milliseconds to seconds in v8, so it must be specified.
+func normalizeDiscordHeader(statusCode int, header http.Header, body []byte, retryAfterUnit time.Duration) (h http.Header, err error) {
+	toMilliseconds := func(retryAfter float64) int64 {
+		return int64(retryAfter * float64(retryAfterUnit/time.Millisecond))
+	}
+
 	// don't care about 2 different time delay estimates for the ltBucket reset.
 	// So lets take Retry-After and X-RateLimit-Reset-After to set the reset
 	var delay int64
 	if retryAfter := header.Get(RateLimitRetryAfter); retryAfter != "" {
-		delay, _ = strconv.ParseInt(retryAfter, 10, 64)
+		retryAfterF, _ := strconv.ParseFloat(retryAfter, 64)
+		delay = toMilliseconds(retryAfterF)
 	}
 	if retry := header.Get(XRateLimitResetAfter); delay == 0 && retry != "" {
 		delayF, _ := strconv.ParseFloat(retry, 64)
@@ -67,7 +78,7 @@ func NormalizeDiscordHeader(statusCode int, header http.Header, body []byte) (h
 			header.Set(XRateLimitGlobal, "true")
 		}
 		if delay == 0 && rateLimitBodyInfo.RetryAfter > 0 {
-			delay = rateLimitBodyInfo.RetryAfter
+			delay = toMilliseconds(rateLimitBodyInfo.RetryAfter)
 		}
 	}
 
@@ -39,6 +39,13 @@ type Request struct {
 	// Reason is a X-Audit-Log-Reason header field that will show up on the audit log for this action.
 	Reason string
 
+	// Params are appended to the endpoint as a query string, or sent as the JSON body if
+	// the Discord API version in use expects them in the body.
+	Params Params
+
+	// Header holds additional header fields for this request. eg. X-GitHub-Event for GitHub compatible webhooks.
+	Header http.Header
+
 	bodyReader     io.Reader
 	hashedEndpoint string
 }
@@ -0,0 +1,107 @@
+package httd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Params are request parameters that, depending on the Discord API version, are sent as a
+// query string or as a JSON body. The JSON tags must match the url parameter names.
+type Params interface {
+	URLQueryString() string
+}
+
+// apiSpec holds the behaviour that differs between the supported Discord REST API versions.
+// Everything version specific should be described here, such that the rest of the library can
+// stay version agnostic.
+type apiSpec struct {
+	version int
+
+	// baseURL is the default REST root, without the version suffix
+	baseURL string
+
+	// precisionHeader requests millisecond precision for the rate limit reset headers.
+	// From v8 and onwards the precision is always milliseconds and the header is ignored.
+	precisionHeader bool
+
+	// retryAfter is the unit of the Retry-After header and the retry_after field of a 429 response
+	retryAfter time.Duration
+
+	// paramsInBody holds the routes where Discord expects the parameters as a JSON body
+	// rather than as a query string. See Request.Params.
+	paramsInBody map[string]bool
+}
+
+var apiSpecs = map[int]*apiSpec{
+	6: {
+		version:         6,
+		baseURL:         "https://discordapp.com/api",
+		precisionHeader: true,
+		retryAfter:      time.Millisecond,
+	},
+	7: {
+		version:         7,
+		baseURL:         "https://discordapp.com/api",
+		precisionHeader: true,
+		retryAfter:      time.Millisecond,
+	},
+	8: {
+		version:    8,
+		baseURL:    "https://discord.com/api",
+		retryAfter: time.Second,
+		paramsInBody: map[string]bool{
+			"PUT:/guilds/{id}/bans/{id}": true,
+		},
+	},
+}
+
+func getAPISpec(version int) (spec *apiSpec, ok bool) {
+	spec, ok = apiSpecs[version]
+	return spec, ok
+}
+
+// route creates a version agnostic identifier for the endpoint, where every snowflake is replaced with {id}.
+// eg. PUT:/guilds/{id}/bans/{id}
+func route(method httpMethod, endpoint string) string {
+	endpoint = strings.Split(endpoint, "?")[0]
+	endpoint = regexpURLSnowflakes.ReplaceAllString(endpoint+"/", "/{id}/")
+	return method.String() + ":" + strings.TrimSuffix(endpoint, "/")
+}
+
+// header returns the default request header fields for this version
+func (spec *apiSpec) header(userAgent string) http.Header {
+	header := map[string][]string{
+		"User-Agent":      {userAgent},
+		"Accept-Encoding": {"gzip"},
+	}
+	if spec.precisionHeader {
+		header[XRateLimitPrecision] = []string{"millisecond"}
+	}
+	return header
+}
+
+// prepare applies the version specific changes to a request before it is sent
+func (spec *apiSpec) prepare(r *Request) (err error) {
+	if r.Params == nil {
+		return nil
+	}
+
+	if !spec.paramsInBody[route(r.Method, r.Endpoint)] {
+		r.Endpoint += r.Params.URLQueryString()
+		return nil
+	}
+
+	if r.Body != nil {
+		return nil // a custom body has already been set
+	}
+	var data []byte
+	if data, err = json.Marshal(r.Params); err != nil {
+		return err
+	}
+	r.Body = bytes.NewReader(data)
+	r.ContentType = ContentTypeJSON
+	return nil
+}
@@ -0,0 +1,113 @@
+package httd
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type banParams struct {
+	DeleteMessageDays int    `json:"delete_message_days,omitempty"`
+	Reason            string `json:"reason,omitempty"`
+}
+
+func (p *banParams) URLQueryString() string {
+	return "?delete_message_days=1&reason=spam"
+}
+
+func newVersionedClient(t *testing.T, version int, baseURL string) *Client {
+	client, err := NewClient(&Config{
+		APIVersion:         version,
+		BotToken:           "test",
+		BaseURL:            baseURL,
+		UserAgentSourceURL: "https://github.com/andersfylling/disgord",
+		UserAgentVersion:   "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestClient_APIVersions(t *testing.T) {
+	table := []struct {
+		version         int
+		baseURL         string
+		precisionHeader bool
+		query           string
+		body            string
+	}{
+		{6, "https://discordapp.com/api/v6", true, "delete_message_days=1&reason=spam", ""},
+		{7, "https://discordapp.com/api/v7", true, "delete_message_days=1&reason=spam", ""},
+		{8, "https://discord.com/api/v8", false, "", `{"delete_message_days":1,"reason":"spam"}`},
+	}
+
+	for _, test := range table {
+		if url := newVersionedClient(t, test.version, "").url; url != test.baseURL {
+			t.Errorf("v%d: expected default url %s. Got %s", test.version, test.baseURL, url)
+		}
+
+		var req *http.Request
+		var body []byte
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req = r
+			body, _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		client := newVersionedClient(t, test.version, srv.URL+"/api/")
+		_, _, err := client.Do(&Request{
+			Ctx:      context.Background(),
+			Method:   MethodPut,
+			Endpoint: "/guilds/123/bans/456",
+			Params:   &banParams{DeleteMessageDays: 1, Reason: "spam"},
+		})
+		srv.Close()
+		if err != nil {
+			t.Fatalf("v%d: %s", test.version, err)
+		}
+
+		if path := "/api/v" + strconv.Itoa(test.version) + "/guilds/123/bans/456"; req.URL.Path != path {
+			t.Errorf("v%d: expected path %s. Got %s", test.version, path, req.URL.Path)
+		}
+		if req.URL.RawQuery != test.query {
+			t.Errorf("v%d: expected query '%s'. Got '%s'", test.version, test.query, req.URL.RawQuery)
+		}
+		if string(body) != test.body {
+			t.Errorf("v%d: expected body '%s'. Got '%s'", test.version, test.body, string(body))
+		}
+		if hasHeader := req.Header.Get(XRateLimitPrecision) != ""; hasHeader != test.precisionHeader {
+			t.Errorf("v%d: expected precision header to be %t", test.version, test.precisionHeader)
+		}
+	}
+}
+
+func TestClient_UnsupportedAPIVersion(t *testing.T) {
+	if _, err := NewClient(&Config{APIVersion: 5, BotToken: "test"}); err == nil {
+		t.Error("expected an error for an unsupported api version")
+	}
+}
+
+func TestNormalizeDiscordHeader_RetryAfterUnit(t *testing.T) {
+	date := time.Now().UTC().Truncate(time.Second)
+	for _, unit := range []time.Duration{time.Millisecond, time.Second} {
+		header := http.Header{}
+		header.Set(RateLimitRetryAfter, "2")
+		header.Set("date", date.Format(time.RFC1123))
+
+		header, err := normalizeDiscordHeader(http.StatusTooManyRequests, header, nil, unit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reset, _ := strconv.ParseInt(header.Get(XRateLimitReset), 10, 64)
+		expected := date.Add(2*unit).UnixNano() / int64(time.Millisecond)
+		if reset != expected {
+			t.Errorf("expected reset at %d. Got %d", expected, reset)
+		}
+	}
+}
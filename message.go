@@ -1,7 +1,6 @@
 package disgord
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -9,6 +8,7 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -602,7 +602,7 @@ type CreateMessageParams struct {
 	SpoilerTagAllAttachments bool `json:"-"`
 }
 
-func (p *CreateMessageParams) prepare() (postBody interface{}, contentType string, err error) {
+func (p *CreateMessageParams) prepare(uploadLimit int64) (postBody interface{}, contentType string, err error) {
 	// spoiler tag
 	if p.SpoilerTagContent && len(p.Content) > 0 {
 		p.Content = "|| " + p.Content + " ||"
@@ -633,33 +633,80 @@ func (p *CreateMessageParams) prepare() (postBody interface{}, contentType strin
 		}
 	}
 
-	// Set up a new multipart writer, as we'll be using this for the POST body instead
-	buf := new(bytes.Buffer)
-	mp := multipart.NewWriter(buf)
+	return newMultipartBody(p, p.Files, uploadLimit)
+}
 
-	// Write the existing JSON payload
-	var payload []byte
-	payload, err = json.Marshal(p)
-	if err != nil {
-		return
+// newMultipartBody streams the JSON payload and the files as a multipart body, such that the files are
+// never buffered in memory. The upload is aborted with a ErrorFileTooLarge if the files exceeds the upload limit.
+func newMultipartBody(payload interface{}, files []CreateMessageFileParams, uploadLimit int64) (body io.Reader, contentType string, err error) {
+	var payloadJSON []byte
+	if payloadJSON, err = json.Marshal(payload); err != nil {
+		return nil, "", err
 	}
-	if err = mp.WriteField("payload_json", string(payload)); err != nil {
-		return
+
+	// fail early when the size is known ahead of time
+	if size, known := uploadSize(files); known && uploadLimit > 0 && size > uploadLimit {
+		return nil, "", &ErrorFileTooLarge{Size: size, Limit: uploadLimit}
 	}
 
-	// Iterate through all the files and write them to the multipart blob
-	for i, file := range p.Files {
-		if err = file.write(i, mp); err != nil {
-			return
+	pr, pw := io.Pipe()
+	mp := multipart.NewWriter(pw)
+	go func() {
+		err := mp.WriteField("payload_json", string(payloadJSON))
+		uploaded := &uploadCounter{limit: uploadLimit}
+		for i := range files {
+			if err != nil {
+				break
+			}
+			err = files[i].write(i, mp, uploaded)
+		}
+		if err == nil {
+			err = mp.Close()
 		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	return pr, mp.FormDataContentType(), nil
+}
+
+// uploadSize sums up the size of every file. Known is false if the size of one or more files could not be detected.
+func uploadSize(files []CreateMessageFileParams) (size int64, known bool) {
+	for i := range files {
+		fileSize := files[i].size()
+		if fileSize < 0 {
+			return size, false
+		}
+		size += fileSize
 	}
+	return size, true
+}
 
-	mp.Close()
+// exceedsUploadLimit checks if the files might be larger than the given upload limit.
+func exceedsUploadLimit(files []CreateMessageFileParams, limit int64) bool {
+	size, known := uploadSize(files)
+	return !known || size > limit
+}
 
-	postBody = buf
-	contentType = mp.FormDataContentType()
+// ErrorFileTooLarge is returned when the files of a message exceeds the upload limit of the guild.
+// See PremiumTier.UploadLimit.
+type ErrorFileTooLarge struct {
+	FileName string // the file that exceeded the limit. Empty if detected before the upload started
+	Size     int64  // number of bytes, at least
+	Limit    int64
+}
 
-	return
+func (e *ErrorFileTooLarge) Error() string {
+	msg := "upload of " + strconv.FormatInt(e.Size, 10) + " bytes exceeds the limit of " + strconv.FormatInt(e.Limit, 10) + " bytes"
+	if e.FileName != "" {
+		msg += ", aborted at file " + e.FileName
+	}
+	return msg
+}
+
+// uploadCounter keeps track of the total number of bytes uploaded for a message
+type uploadCounter struct {
+	written int64
+	limit   int64
 }
 
 // CreateMessageFileParams contains the information needed to upload a file to Discord, it is part of the
@@ -672,10 +719,35 @@ type CreateMessageFileParams struct {
 	// Current Discord behaviour is that whenever a message with one or more images is marked as
 	// spoiler tag, all the images in that message are blurred out. (independent of msg.Content)
 	SpoilerTag bool `json:"-"`
+
+	// Size is the number of bytes in the Reader, used to validate the upload limit before sending.
+	// Optional, as the size is detected for readers such as *os.File, *bytes.Buffer and *strings.Reader.
+	Size int64 `json:"-"`
+
+	// OnProgress is called every time a chunk of the file has been uploaded, with the number of bytes
+	// uploaded so far. Total is -1 when the size of the file is unknown.
+	OnProgress func(uploaded, total int64) `json:"-"`
+}
+
+// size returns the number of bytes that will be uploaded, or -1 if unknown
+func (f *CreateMessageFileParams) size() int64 {
+	if f.Size > 0 {
+		return f.Size
+	}
+
+	switch r := f.Reader.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case interface{ Stat() (os.FileInfo, error) }:
+		if info, err := r.Stat(); err == nil && info.Mode().IsRegular() {
+			return info.Size()
+		}
+	}
+	return -1
 }
 
 // write helper for file uploading in messages
-func (f *CreateMessageFileParams) write(i int, mp *multipart.Writer) error {
+func (f *CreateMessageFileParams) write(i int, mp *multipart.Writer, uploaded *uploadCounter) error {
 	var filename string
 	if f.SpoilerTag {
 		filename = AttachmentSpoilerPrefix + f.FileName
@@ -687,18 +759,61 @@ func (f *CreateMessageFileParams) write(i int, mp *multipart.Writer) error {
 		return err
 	}
 
-	if _, err = io.Copy(w, f.Reader); err != nil {
-		return err
+	_, err = io.Copy(&fileWriter{w: w, file: f, total: f.size(), uploaded: uploaded}, f.Reader)
+	return err
+}
+
+// fileWriter enforces the upload limit and reports progress while a file is streamed
+type fileWriter struct {
+	w        io.Writer
+	file     *CreateMessageFileParams
+	written  int64
+	total    int64
+	uploaded *uploadCounter
+}
+
+func (w *fileWriter) Write(p []byte) (n int, err error) {
+	if w.uploaded.limit > 0 && w.uploaded.written+int64(len(p)) > w.uploaded.limit {
+		return 0, &ErrorFileTooLarge{
+			FileName: w.file.FileName,
+			Size:     w.uploaded.written + int64(len(p)),
+			Limit:    w.uploaded.limit,
+		}
 	}
 
-	return nil
+	n, err = w.w.Write(p)
+	w.written += int64(n)
+	w.uploaded.written += int64(n)
+	if w.file.OnProgress != nil && n > 0 {
+		w.file.OnProgress(w.written, w.total)
+	}
+	return n, err
+}
+
+// channelUploadLimit returns the upload limit of the guild the channel belongs to.
+func (c *Client) channelUploadLimit(ctx context.Context, channelID Snowflake) int64 {
+	channel, err := c.GetChannel(ctx, channelID)
+	if err != nil || channel.GuildID.IsZero() {
+		return UploadLimitDefault
+	}
+	return c.guildUploadLimit(ctx, channel.GuildID)
+}
+
+// guildUploadLimit returns the upload limit given the server boost level of the guild.
+func (c *Client) guildUploadLimit(ctx context.Context, guildID Snowflake) int64 {
+	guild, err := c.GetGuild(ctx, guildID)
+	if err != nil {
+		return UploadLimitDefault
+	}
+	return guild.PremiumTier.UploadLimit()
 }
 
 // CreateMessage [REST] Post a message to a guild text or DM channel. If operating on a guild channel, this
 // endpoint requires the 'SEND_MESSAGES' permission to be present on the current user. If the tts field is set to true,
 // the SEND_TTS_MESSAGES permission is required for the message to be spoken. Returns a message object. Fires a
 // Message Create Gateway event. See message formatting for more information on how to properly format messages.
-// The maximum request size when sending a message is 8MB.
+// The maximum request size when sending a message is 8MB, or more for boosted guilds. Files are streamed
+// and validated against the upload limit of the guild, see CreateMessageFileParams for progress callbacks.
 //  Method                  POST
 //  Endpoint                /channels/{channel.id}/messages
 //  Discord documentation   https://discordapp.com/developers/docs/resources/channel#create-message
@@ -719,7 +834,11 @@ func (c *Client) CreateMessage(ctx context.Context, channelID Snowflake, params
 		contentType string
 	)
 
-	if postBody, contentType, err = params.prepare(); err != nil {
+	uploadLimit := UploadLimitDefault
+	if len(params.Files) > 0 && exceedsUploadLimit(params.Files, uploadLimit) {
+		uploadLimit = c.channelUploadLimit(ctx, channelID)
+	}
+	if postBody, contentType, err = params.prepare(uploadLimit); err != nil {
 		return nil, err
 	}
 
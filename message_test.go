@@ -1,6 +1,12 @@
 package disgord
 
 import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"strings"
 	"testing"
 )
 
@@ -76,3 +82,85 @@ func TestMessage_DeepCopy(t *testing.T) {
 	// 	t.Errorf("expect messages to be equal after deep copy.\n Got \n%s,\n\n wants \n%s", prettyPrint(c), prettyPrint(original))
 	// }
 }
+
+func TestCreateMessageParams_prepare(t *testing.T) {
+	var progress []int64
+	params := &CreateMessageParams{
+		Content: "files",
+		Files: []CreateMessageFileParams{
+			{Reader: strings.NewReader("first"), FileName: "a.txt"},
+			{Reader: bytes.NewBufferString("second"), FileName: "b.txt", SpoilerTag: true, OnProgress: func(uploaded, total int64) {
+				if total != 6 {
+					t.Errorf("expected a total of 6 bytes. Got %d", total)
+				}
+				progress = append(progress, uploaded)
+			}},
+		},
+	}
+
+	body, contentType, err := params.prepare(UploadLimitDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, mediaParams, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expects := map[string]string{
+		"payload_json":  `{"content":"files"}`,
+		"a.txt":         "first",
+		"SPOILER_b.txt": "second",
+	}
+	mp := multipart.NewReader(body.(io.Reader), mediaParams["boundary"])
+	for {
+		part, err := mp.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		name := part.FileName()
+		if name == "" {
+			name = part.FormName()
+		}
+		data, _ := ioutil.ReadAll(part)
+		if expects[name] != string(data) {
+			t.Errorf("expected part %s to be '%s'. Got '%s'", name, expects[name], string(data))
+		}
+		delete(expects, name)
+	}
+	if len(expects) > 0 {
+		t.Errorf("missing parts %+v", expects)
+	}
+	if len(progress) == 0 || progress[len(progress)-1] != 6 {
+		t.Errorf("expected progress to end at 6 bytes. Got %+v", progress)
+	}
+}
+
+func TestCreateMessageParams_prepareUploadLimit(t *testing.T) {
+	// known size is validated before the upload starts
+	params := &CreateMessageParams{
+		Files: []CreateMessageFileParams{{Reader: strings.NewReader("123456"), FileName: "a.txt"}},
+	}
+	if _, _, err := params.prepare(5); err == nil {
+		t.Fatal("expected an error for a file larger than the upload limit")
+	} else if _, ok := err.(*ErrorFileTooLarge); !ok {
+		t.Errorf("expected ErrorFileTooLarge. Got %T", err)
+	}
+
+	// unknown size aborts the upload once the limit is reached
+	params = &CreateMessageParams{
+		Files: []CreateMessageFileParams{{Reader: io.LimitReader(strings.NewReader("123456"), 6), FileName: "a.txt"}},
+	}
+	body, _, err := params.prepare(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ioutil.ReadAll(body.(io.Reader)); err == nil {
+		t.Fatal("expected the upload to be aborted")
+	} else if e, ok := err.(*ErrorFileTooLarge); !ok || e.FileName != "a.txt" {
+		t.Errorf("expected ErrorFileTooLarge for a.txt. Got %v", err)
+	}
+}
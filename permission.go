@@ -0,0 +1,185 @@
+package disgord
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// Discord computes the permissions of a member in a channel as described in
+// https://discordapp.com/developers/docs/topics/permissions#permission-overwrites
+//
+// The guild owner and administrators are granted every permission, regardless of the overwrites.
+
+// permissionAllBits are the permissions of the guild owner and administrators. Unlike PermissionAll,
+// it includes permissions that are not defined by DisGord.
+const permissionAllBits = ^PermissionBit(0)
+
+// permissionsRequiringSend are implicitly denied in a text channel without PermissionSendMessages
+const permissionsRequiringSend = PermissionSendTTSMessages |
+	PermissionMentionEveryone |
+	PermissionAttachFiles |
+	PermissionEmbedLinks
+
+// permissionsRequiringConnect are implicitly denied in a voice channel without PermissionVoiceConnect
+const permissionsRequiringConnect = PermissionAllVoice | PermissionVoicePrioritySpeaker
+
+// permissionNameTable holds the names used by Discord for the permission bits, ordered by bit
+var permissionNameTable = []struct {
+	bit  PermissionBit
+	name string
+}{
+	{PermissionCreateInstantInvite, "CREATE_INSTANT_INVITE"},
+	{PermissionKickMembers, "KICK_MEMBERS"},
+	{PermissionBanMembers, "BAN_MEMBERS"},
+	{PermissionAdministrator, "ADMINISTRATOR"},
+	{PermissionManageChannels, "MANAGE_CHANNELS"},
+	{PermissionManageServer, "MANAGE_GUILD"},
+	{PermissionAddReactions, "ADD_REACTIONS"},
+	{PermissionViewAuditLogs, "VIEW_AUDIT_LOG"},
+	{PermissionVoicePrioritySpeaker, "PRIORITY_SPEAKER"},
+	{PermissionReadMessages, "VIEW_CHANNEL"},
+	{PermissionSendMessages, "SEND_MESSAGES"},
+	{PermissionSendTTSMessages, "SEND_TTS_MESSAGES"},
+	{PermissionManageMessages, "MANAGE_MESSAGES"},
+	{PermissionEmbedLinks, "EMBED_LINKS"},
+	{PermissionAttachFiles, "ATTACH_FILES"},
+	{PermissionReadMessageHistory, "READ_MESSAGE_HISTORY"},
+	{PermissionMentionEveryone, "MENTION_EVERYONE"},
+	{PermissionUseExternalEmojis, "USE_EXTERNAL_EMOJIS"},
+	{PermissionVoiceConnect, "CONNECT"},
+	{PermissionVoiceSpeak, "SPEAK"},
+	{PermissionVoiceMuteMembers, "MUTE_MEMBERS"},
+	{PermissionVoiceDeafenMembers, "DEAFEN_MEMBERS"},
+	{PermissionVoiceMoveMembers, "MOVE_MEMBERS"},
+	{PermissionVoiceUseVAD, "USE_VAD"},
+	{PermissionChangeNickname, "CHANGE_NICKNAME"},
+	{PermissionManageNicknames, "MANAGE_NICKNAMES"},
+	{PermissionManageRoles, "MANAGE_ROLES"},
+	{PermissionManageWebhooks, "MANAGE_WEBHOOKS"},
+	{PermissionManageEmojis, "MANAGE_EMOJIS"},
+}
+
+// permissionNames returns the Discord names of the permission bits. Bits unknown to DisGord are
+// returned as a number.
+func permissionNames(permissions PermissionBits) (names []string) {
+	for _, permission := range permissionNameTable {
+		if permissions&permission.bit != 0 {
+			names = append(names, permission.name)
+			permissions &^= permission.bit
+		}
+	}
+	if permissions != 0 {
+		names = append(names, strconv.FormatUint(uint64(permissions), 10))
+	}
+	return names
+}
+
+func memberUserID(member *Member) Snowflake {
+	if member.userID.IsZero() && member.User != nil {
+		return member.User.ID
+	}
+	return member.userID
+}
+
+// memberBasePermissions returns the guild wide permissions of the member; the @everyone role
+// and the roles of the member.
+func memberBasePermissions(guild *Guild, member *Member) (permissions PermissionBits) {
+	if !guild.OwnerID.IsZero() && guild.OwnerID == memberUserID(member) {
+		return permissionAllBits
+	}
+
+	for _, role := range guild.Roles {
+		if role.ID == guild.ID {
+			// the @everyone role shares the ID of the guild
+			permissions |= role.Permissions
+			continue
+		}
+		for _, roleID := range member.Roles {
+			if role.ID == roleID {
+				permissions |= role.Permissions
+				break
+			}
+		}
+	}
+
+	if permissions&PermissionAdministrator != 0 {
+		return permissionAllBits
+	}
+	return permissions
+}
+
+// memberChannelPermissions applies the permission overwrites of the channel to the base permissions
+// of the member, in the order: @everyone, roles and then the member.
+func memberChannelPermissions(guild *Guild, member *Member, channel *Channel) PermissionBits {
+	permissions := memberBasePermissions(guild, member)
+	if permissions&PermissionAdministrator != 0 {
+		return permissions
+	}
+
+	userID := memberUserID(member)
+	var (
+		everyone, memberOverwrite *PermissionOverwrite
+		allow, deny               PermissionBits
+	)
+	for i := range channel.PermissionOverwrites {
+		overwrite := &channel.PermissionOverwrites[i]
+		switch {
+		case overwrite.ID == guild.ID:
+			everyone = overwrite
+		case overwrite.Type == PermissionOverwriteMember:
+			if overwrite.ID == userID {
+				memberOverwrite = overwrite
+			}
+		default:
+			for _, roleID := range member.Roles {
+				if overwrite.ID == roleID {
+					allow |= overwrite.Allow
+					deny |= overwrite.Deny
+					break
+				}
+			}
+		}
+	}
+
+	if everyone != nil {
+		permissions = permissions&^everyone.Deny | everyone.Allow
+	}
+	permissions = permissions&^deny | allow
+	if memberOverwrite != nil {
+		permissions = permissions&^memberOverwrite.Deny | memberOverwrite.Allow
+	}
+
+	// a member that can not see the channel, can not do anything in it
+	if permissions&PermissionReadMessages == 0 {
+		return 0
+	}
+	switch channel.Type {
+	case ChannelTypeGuildText, ChannelTypeGuildNews:
+		if permissions&PermissionSendMessages == 0 {
+			permissions &^= permissionsRequiringSend
+		}
+	case ChannelTypeGuildVoice:
+		if permissions&PermissionVoiceConnect == 0 {
+			permissions &^= permissionsRequiringConnect
+		}
+	}
+	return permissions
+}
+
+// PermissionsIn returns the permissions of the member in the given guild channel, including the permission
+// overwrites of the channel. The guild is fetched from the cache when possible.
+func (m *Member) PermissionsIn(ctx context.Context, s Session, channel *Channel, flags ...Flag) (PermissionBits, error) {
+	if channel.GuildID.IsZero() {
+		return 0, errors.New("permissions can only be calculated for guild channels")
+	}
+	if !m.GuildID.IsZero() && m.GuildID != channel.GuildID {
+		return 0, errors.New("member and channel belong to different guilds")
+	}
+
+	guild, err := s.GetGuild(ctx, channel.GuildID, flags...)
+	if err != nil {
+		return 0, err
+	}
+	return memberChannelPermissions(guild, m, channel), nil
+}
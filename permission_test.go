@@ -0,0 +1,292 @@
+package disgord
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andersfylling/disgord/disgordtest"
+	"github.com/andersfylling/disgord/internal/util"
+)
+
+func TestMemberChannelPermissions(t *testing.T) {
+	const (
+		guildID   Snowflake = 486833611564253184
+		ownerID   Snowflake = 486833611564253185
+		userID    Snowflake = 486833611564253186
+		modRoleID Snowflake = 486833611564253187
+		djRoleID  Snowflake = 486833611564253188
+		adminID   Snowflake = 486833611564253189
+	)
+	everyone := PermissionReadMessages | PermissionSendMessages | PermissionEmbedLinks | PermissionVoiceConnect | PermissionVoiceSpeak
+	guild := &Guild{
+		ID:      guildID,
+		OwnerID: ownerID,
+		Roles: []*Role{
+			{ID: guildID, Permissions: everyone},
+			{ID: modRoleID, Permissions: PermissionManageMessages | PermissionKickMembers},
+			{ID: djRoleID, Permissions: PermissionVoicePrioritySpeaker},
+			{ID: adminID, Permissions: PermissionAdministrator},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		roles       []Snowflake
+		userID      Snowflake
+		channelType uint
+		overwrites  []PermissionOverwrite
+		data        string // channel JSON, decoded instead of using the overwrites
+		expects     PermissionBits
+	}{
+		{
+			name:    "everyone",
+			expects: everyone,
+		},
+		{
+			name:    "roles",
+			roles:   []Snowflake{modRoleID, djRoleID},
+			expects: everyone | PermissionManageMessages | PermissionKickMembers | PermissionVoicePrioritySpeaker,
+		},
+		{
+			name:   "owner",
+			userID: ownerID,
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: "role", Deny: PermissionReadMessages},
+			},
+			expects: permissionAllBits,
+		},
+		{
+			name:  "administrator ignores overwrites",
+			roles: []Snowflake{adminID},
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: "role", Deny: PermissionReadMessages},
+				{ID: userID, Type: "member", Deny: PermissionSendMessages},
+			},
+			expects: permissionAllBits,
+		},
+		{
+			name: "everyone overwrite",
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: "role", Allow: PermissionAddReactions, Deny: PermissionEmbedLinks},
+			},
+			expects: everyone&^PermissionEmbedLinks | PermissionAddReactions,
+		},
+		{
+			name:  "role overwrite allow wins over deny of another role",
+			roles: []Snowflake{modRoleID, djRoleID},
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: "role", Deny: PermissionSendMessages},
+				{ID: modRoleID, Type: "role", Deny: PermissionAttachFiles},
+				{ID: djRoleID, Type: "role", Allow: PermissionSendMessages | PermissionAttachFiles},
+			},
+			expects: everyone | PermissionManageMessages | PermissionKickMembers | PermissionVoicePrioritySpeaker | PermissionAttachFiles,
+		},
+		{
+			name:  "role overwrite of other roles is ignored",
+			roles: []Snowflake{modRoleID},
+			overwrites: []PermissionOverwrite{
+				{ID: djRoleID, Type: "role", Deny: PermissionSendMessages},
+			},
+			expects: everyone | PermissionManageMessages | PermissionKickMembers,
+		},
+		{
+			name:  "member overwrite wins over roles",
+			roles: []Snowflake{modRoleID},
+			overwrites: []PermissionOverwrite{
+				{ID: modRoleID, Type: "role", Allow: PermissionMentionEveryone},
+				{ID: userID, Type: "member", Deny: PermissionMentionEveryone | PermissionManageMessages},
+			},
+			expects: everyone | PermissionKickMembers,
+		},
+		{
+			name:  "member overwrite wins over roles decoded from v8",
+			roles: []Snowflake{modRoleID},
+			data: `{"permission_overwrites":[
+				{"id":"486833611564253187","type":0,"allow":"131072","deny":"0"},
+				{"id":"486833611564253186","type":1,"allow":"0","deny":"139264"}
+			]}`,
+			expects: everyone | PermissionKickMembers,
+		},
+		{
+			name: "member overwrite of other members is ignored",
+			overwrites: []PermissionOverwrite{
+				{ID: ownerID, Type: "member", Deny: PermissionSendMessages},
+			},
+			expects: everyone,
+		},
+		{
+			name: "no view channel denies everything",
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: "role", Deny: PermissionReadMessages},
+			},
+			expects: 0,
+		},
+		{
+			name: "view channel by member overwrite",
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: "role", Deny: PermissionReadMessages},
+				{ID: userID, Type: "member", Allow: PermissionReadMessages},
+			},
+			expects: everyone,
+		},
+		{
+			name: "no send messages denies embeds",
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: "role", Allow: PermissionAttachFiles, Deny: PermissionSendMessages},
+			},
+			expects: everyone &^ (PermissionSendMessages | PermissionEmbedLinks),
+		},
+		{
+			name:        "no send messages in a voice channel",
+			channelType: ChannelTypeGuildVoice,
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: "role", Deny: PermissionSendMessages},
+			},
+			expects: everyone &^ PermissionSendMessages,
+		},
+		{
+			name:        "no connect denies voice",
+			roles:       []Snowflake{djRoleID},
+			channelType: ChannelTypeGuildVoice,
+			overwrites: []PermissionOverwrite{
+				{ID: guildID, Type: "role", Deny: PermissionVoiceConnect},
+			},
+			expects: everyone &^ (PermissionVoiceConnect | PermissionVoiceSpeak),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			member := &Member{
+				GuildID: guildID,
+				User:    &User{ID: userID},
+				Roles:   test.roles,
+			}
+			if !test.userID.IsZero() {
+				member.User.ID = test.userID
+			}
+			channel := &Channel{
+				GuildID:              guildID,
+				Type:                 test.channelType,
+				PermissionOverwrites: test.overwrites,
+			}
+			if test.data != "" {
+				if err := util.Unmarshal([]byte(test.data), channel); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if permissions := memberChannelPermissions(guild, member, channel); permissions != test.expects {
+				t.Errorf("expected permissions %b, got %b", test.expects, permissions)
+			}
+		})
+	}
+}
+
+func TestMemberBasePermissions(t *testing.T) {
+	guild := &Guild{
+		ID: 486833611564253184,
+		Roles: []*Role{
+			{ID: 486833611564253184, Permissions: PermissionSendMessages},
+			{ID: 486833611564253185, Permissions: PermissionKickMembers},
+		},
+	}
+	member := &Member{User: &User{ID: 486833611564253186}, Roles: []Snowflake{486833611564253185}}
+
+	if permissions := memberBasePermissions(guild, member); permissions != PermissionSendMessages|PermissionKickMembers {
+		t.Errorf("expected the @everyone and member roles, got %b", permissions)
+	}
+
+	// the guild has no owner when the guild is unavailable
+	member.User.ID = 0
+	if permissions := memberBasePermissions(guild, member); permissions == permissionAllBits {
+		t.Error("expected a member without an ID not to be the owner")
+	}
+}
+
+func TestMember_PermissionsIn(t *testing.T) {
+	member := &Member{GuildID: 486833611564253184}
+	if _, err := member.PermissionsIn(context.Background(), nil, &Channel{Type: ChannelTypeDM}); err == nil {
+		t.Error("expected an error for a DM channel")
+	}
+	if _, err := member.PermissionsIn(context.Background(), nil, &Channel{GuildID: 486833611564253185}); err == nil {
+		t.Error("expected an error for a channel of another guild")
+	}
+
+	// the guild is cached, so no request should reach Discord
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := New(Config{BotToken: "testing", RESTBaseURL: srv.URL})
+	c.cache.SetGuild(&Guild{
+		ID:      486833611564253184,
+		OwnerID: 486833611564253185,
+		Roles: []*Role{
+			{ID: 486833611564253184, Permissions: PermissionReadMessages | PermissionSendMessages},
+			{ID: 486833611564253187, Permissions: PermissionManageMessages},
+		},
+	})
+	member = &Member{
+		GuildID: 486833611564253184,
+		User:    &User{ID: 486833611564253186},
+		Roles:   []Snowflake{486833611564253187},
+	}
+	channel := &Channel{
+		ID:      486833611564253188,
+		GuildID: 486833611564253184,
+		PermissionOverwrites: []PermissionOverwrite{
+			{ID: 486833611564253186, Type: PermissionOverwriteMember, Deny: PermissionManageMessages},
+		},
+	}
+
+	permissions, err := member.PermissionsIn(context.Background(), c, channel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expects := PermissionReadMessages | PermissionSendMessages; permissions != expects {
+		t.Errorf("expected permissions %b, got %b", expects, permissions)
+	}
+}
+
+func TestClient_GetMemberChannelPermissions(t *testing.T) {
+	srv := disgordtest.NewRESTServer()
+	defer srv.Close()
+
+	modRoleID := disgordtest.NewSnowflake()
+	guild := srv.AddGuild(disgordtest.Guild{
+		Name: "test",
+		Roles: []disgordtest.Role{
+			{ID: modRoleID, Name: "mod", Permissions: uint64(PermissionManageMessages | PermissionKickMembers)},
+		},
+	})
+	user := srv.AddUser(disgordtest.User{Username: "member"})
+	srv.AddMember(guild.ID, disgordtest.Member{User: user, Roles: []Snowflake{modRoleID}})
+	channel := srv.AddChannel(disgordtest.Channel{
+		GuildID: guild.ID,
+		Name:    "general",
+		PermissionOverwrites: []disgordtest.PermissionOverwrite{
+			{ID: guild.ID, Type: "role", Deny: uint64(PermissionSendMessages)},
+			{ID: modRoleID, Type: "role", Allow: uint64(PermissionSendMessages)},
+			{ID: user.ID, Type: "member", Deny: uint64(PermissionKickMembers)},
+		},
+	})
+
+	c := New(Config{BotToken: srv.BotToken(), RESTBaseURL: srv.URL(), DisableCache: true})
+	permissions, err := c.GetMemberChannelPermissions(context.Background(), channel.ID, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	everyone := PermissionBits(guild.Roles[0].Permissions)
+	if expects := everyone | PermissionManageMessages | PermissionSendMessages; permissions != expects {
+		t.Errorf("expected permissions %b, got %b", expects, permissions)
+	}
+	if _, err = c.GetMemberChannelPermissions(context.Background(), channel.ID, disgordtest.NewSnowflake()); err == nil {
+		t.Error("expected an error for an unknown member")
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"runtime/debug"
 	"sync"
 	"time"
 
+	"go.uber.org/atomic"
+
 	"github.com/andersfylling/disgord/internal/gateway"
 	"github.com/andersfylling/disgord/internal/util"
 )
@@ -42,6 +46,10 @@ func populateResource(resource evtResource, ctx context.Context, evt *gateway.Ev
 //////////////////////////////////////////////////////
 
 func demultiplexer(d *dispatcher, read <-chan *gateway.Event, cache *Cache) {
+	if d.executor != nil {
+		d.executor.start(d.shutdown)
+	}
+
 	for {
 		var evt *gateway.Event
 		var alive bool
@@ -55,13 +63,29 @@ func demultiplexer(d *dispatcher, read <-chan *gateway.Event, cache *Cache) {
 			return
 		}
 
+		if d.dedup != nil && d.dedup.duplicate(evt) {
+			d.session.Logger().Debug("event `", evt.Name, "` was already dispatched -- DECISION: IGNORED")
+			continue
+		}
+
+		ctx := d.eventContext(evt)
+		if d.hasHandlers(EvtRaw) {
+			d.submit(ctx, evt, EvtRaw, &RawEvent{
+				Name:     evt.Name,
+				Sequence: evt.Sequence,
+				Data:     evt.Data,
+				Ctx:      ctx,
+				ShardID:  evt.ShardID,
+			})
+		}
+
 		var resource evtResource
 		if resource = defineResource(evt.Name); resource == nil {
-			fmt.Printf("------\nTODO\nImplement event handler for `%s`, data: \n%+v\n------\n\n", evt.Name, string(evt.Data))
+			d.session.Logger().Info("unknown event `", evt.Name, "` is only dispatched to EvtRaw handlers")
+			d.publish(evt)
 			continue // move on to next event
 		}
 
-		ctx := context.Background()
 		if err := populateResource(resource, ctx, evt); err != nil {
 			d.session.Logger().Error(err, "EVENT DATA: `", string(evt.Data), "`, EVENT: `", evt.Name, "` -- DECISION: IGNORED")
 			continue // ignore event
@@ -72,11 +96,27 @@ func demultiplexer(d *dispatcher, read <-chan *gateway.Event, cache *Cache) {
 		if cache != nil {
 			cacheEvent(cache, evt.Name, resource, evt.Data)
 		}
+		d.publish(evt)
 
-		go d.dispatch(ctx, evt.Name, resource)
+		d.submit(ctx, evt, evt.Name, resource)
 	}
 }
 
+// submit hands the event to the executor. evtName is the name used to look up the handlers,
+// which differs from the gateway event name for raw events.
+func (d *dispatcher) submit(ctx context.Context, evt *gateway.Event, evtName string, resource resource) {
+	if d.executor == nil {
+		go d.dispatch(ctx, evtName, resource)
+		return
+	}
+	d.executor.submit(&dispatchJob{
+		ctx:     ctx,
+		evtName: evtName,
+		evt:     resource,
+		key:     d.executor.key(evt.Name, evt.Data),
+	}, d.shutdown)
+}
+
 //////////////////////////////////////////////////////
 //
 // Dispatcher
@@ -94,6 +134,33 @@ type dispatcher struct {
 	// use session to allow mocking the Client instance later on
 	session  Session
 	shutdown chan struct{}
+
+	// executor decides which goroutine runs the handlers. When nil, every
+	// event is dispatched in a new goroutine.
+	executor *dispatchExecutor
+
+	// ctx is the client lifetime, cancelled on disconnect. See eventContext.
+	ctx           context.Context
+	cancel        context.CancelFunc
+	eventDeadline time.Duration
+
+	// sink receives every event, see Config.EventSink
+	sink EventSink
+
+	// dedup discards events that were already dispatched. When nil, every event is dispatched.
+	dedup *deduplicator
+
+	// see DispatchConfig
+	onHandlerPanic func(evtName string, recovered interface{}, stack []byte)
+	onHandlerError func(evtName string, err error)
+	handlerTimeout time.Duration
+}
+
+// hasHandlers checks if any handler specification is registered for the event
+func (d *dispatcher) hasHandlers(evtName string) bool {
+	d.RLock()
+	defer d.RUnlock()
+	return len(d.handlerSpecs[evtName]) > 0
 }
 
 func (d *dispatcher) addSessionInstance(s Session) {
@@ -103,12 +170,17 @@ func (d *dispatcher) addSessionInstance(s Session) {
 // register registers handlers.
 // Note! While the dispatcher handles registration in form of a method,
 // deregistration is done automatically by checking the controller spec after each dispatch.
-// See HandlerCtrl.
+// See HandlerCtrl and Subscription.
 func (d *dispatcher) register(evt string, inputs ...interface{}) error {
+	_, err := d.subscribe(evt, inputs...)
+	return err
+}
+
+func (d *dispatcher) subscribe(evt string, inputs ...interface{}) (*Subscription, error) {
 	// detect middleware then handlers. Ordering is important.
 	spec := &handlerSpec{}
 	if err := spec.populate(inputs...); err != nil { // TODO: improve redundant checking
-		return err // if the pattern is wrong: (event,[ ...middlewares,] ...handlers[, controller])
+		return nil, err // if the pattern is wrong: (event,[ ...middlewares,] ...handlers[, controller])
 		// if you want to error check before you use the .On, you can use disgord.ValidateHandlerInputs(...)
 	}
 
@@ -123,7 +195,46 @@ func (d *dispatcher) register(evt string, inputs ...interface{}) error {
 	d.handlerSpecs[evt] = append(d.handlerSpecs[evt], spec)
 	d.Unlock()
 
-	return nil
+	return &Subscription{
+		dispatcher: d,
+		evt:        evt,
+		spec:       spec,
+	}, nil
+}
+
+// remove deletes the specs from the event, and returns the specs that were found. Dispatching
+// iterates over the specs without holding the dispatcher lock, so a new slice is always created.
+func (d *dispatcher) remove(evtName string, specs ...*handlerSpec) (removed []*handlerSpec) {
+	d.Lock()
+	defer d.Unlock()
+
+	current := d.handlerSpecs[evtName]
+	kept := make([]*handlerSpec, 0, len(current))
+	for _, spec := range current {
+		found := false
+		for i := range specs {
+			if spec == specs[i] { // compare pointers
+				found = true
+				break
+			}
+		}
+		if found {
+			removed = append(removed, spec)
+		} else {
+			kept = append(kept, spec)
+		}
+	}
+
+	d.handlerSpecs[evtName] = kept
+	return removed
+}
+
+func (d *dispatcher) notifyRemoved(specs []*handlerSpec) {
+	for i := range specs {
+		if err := specs[i].ctrl.OnRemove(d.session); err != nil {
+			d.session.Logger().Error(err)
+		}
+	}
 }
 
 func (d *dispatcher) dispatch(ctx context.Context, evtName string, evt resource) {
@@ -142,16 +253,32 @@ func (d *dispatcher) dispatch(ctx context.Context, evtName string, evt resource)
 		//	dead = append(dead, spec)
 		//	continue
 		//}
+		if spec.unsubscribed.Load() {
+			continue
+		}
+
 		spec.Lock()
+		if spec.unsubscribed.Load() {
+			spec.Unlock()
+			continue
+		}
+		unlock := spec.Unlock
 		if dead := spec.ctrl.IsDead(); !dead {
-			localEvt := spec.runMdlws(evt)
-			if localEvt == nil {
-				spec.Unlock()
-				continue
+			executed, running := d.execute(ctx, evtName, spec, evt)
+			if running != nil {
+				// the spec stays locked until the timed out handler returns, such that it never runs
+				// concurrently and the next events still reach it in order
+				spec := spec
+				unlock = func() {
+					go func() {
+						<-running
+						spec.Unlock()
+					}()
+				}
 			}
-
-			for _, handler := range spec.handlers {
-				d.trigger(handler, localEvt)
+			if !executed {
+				unlock()
+				continue
 			}
 
 			spec.ctrl.Update()
@@ -160,7 +287,7 @@ func (d *dispatcher) dispatch(ctx context.Context, evtName string, evt resource)
 		if spec.ctrl.IsDead() {
 			dead = append(dead, spec)
 		}
-		spec.Unlock()
+		unlock()
 	}
 
 	// time to remove the dead
@@ -168,34 +295,136 @@ func (d *dispatcher) dispatch(ctx context.Context, evtName string, evt resource)
 		return
 	}
 
-	d.Lock()
-
 	// make sure the dead has not already been removed, after all this is concurrent
-	specs = d.handlerSpecs[evtName]
-	for _, deadspec := range dead {
-		for i, spec := range specs {
-			if spec == deadspec { // compare pointers
-				// delete the dead spec, but keep the ordering
-				copy(specs[i:], specs[i+1:])
-				//specs[len(specs)-1] = nil // GC, setting entries to nil requires locking
-				specs = specs[:len(specs)-1]
-				break
-			}
+	if dead = d.remove(evtName, dead...); len(dead) > 0 {
+		go d.notifyRemoved(dead)
+	}
+}
+
+// execute runs the middlewares and handlers of the spec. Without a handler timeout the spec runs
+// in the current goroutine. Otherwise the handlers are given a context that expires after the
+// timeout, and a handler that does not return in time is left running such that the next specs
+// are not blocked. running is then closed once the handler returns, and the spec must not run
+// again before that. executed is false when a middleware discarded the event.
+//
+// The context is not cancelled when the handlers return, as goroutines started by the handlers
+// might keep using it. Channel handlers get the event context instead, as the event is consumed
+// after the dispatch.
+func (d *dispatcher) execute(ctx context.Context, evtName string, spec *handlerSpec, evt resource) (executed bool, running <-chan bool) {
+	if d.handlerTimeout <= 0 {
+		return d.run(evtName, spec, evt, nil), nil
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, d.handlerTimeout)
+	context.AfterFunc(ctx, cancel) // releases the timer once the context is done
+
+	// every spec gets a copy, as a timed out handler might still read the event
+	evt = withContext(evt, ctx)
+
+	done := make(chan bool, 1)
+	go func() {
+		done <- d.run(evtName, spec, evt, parent)
+	}()
+
+	select {
+	case executed = <-done:
+		return executed, nil
+	case <-ctx.Done():
+		// a cancelled parent, such as a shutdown, is not the handler's fault
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && parent.Err() == nil {
+			d.handlerFailed(evtName, newErrorHandlerTimeout(evtName, d.handlerTimeout))
 		}
+		return true, done
 	}
+}
 
-	// update the specs
-	d.handlerSpecs[evtName] = specs
-	d.Unlock()
+// run executes the spec. When set, channel handlers receive the event with the consumerCtx.
+func (d *dispatcher) run(evtName string, spec *handlerSpec, evt resource, consumerCtx context.Context) (executed bool) {
+	evt = d.runMdlws(evtName, spec, evt)
+	if evt == nil {
+		return false
+	}
 
-	// notify specs
-	go func(dead []*handlerSpec) {
-		for i := range dead {
-			if err := dead[i].ctrl.OnRemove(d.session); err != nil {
-				d.session.Logger().Error(err)
-			}
+	for _, handler := range spec.handlers {
+		if consumerCtx != nil && reflect.ValueOf(handler).Kind() == reflect.Chan {
+			d.runHandler(evtName, handler, withContext(evt, consumerCtx))
+			continue
+		}
+		d.runHandler(evtName, handler, evt)
+	}
+	return true
+}
+
+func (d *dispatcher) runMdlws(evtName string, spec *handlerSpec, evt resource) (localEvt resource) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.handlerPanicked(evtName, r, debug.Stack())
+			localEvt = nil
+		}
+	}()
+	return spec.runMdlws(evt)
+}
+
+func (d *dispatcher) runHandler(evtName string, handler Handler, evt resource) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.handlerPanicked(evtName, r, debug.Stack())
 		}
-	}(dead)
+	}()
+
+	if err := d.trigger(handler, evt); err != nil {
+		d.handlerFailed(evtName, err)
+	}
+}
+
+func (d *dispatcher) handlerPanicked(evtName string, recovered interface{}, stack []byte) {
+	if d.onHandlerPanic != nil {
+		d.onHandlerPanic(evtName, recovered, stack)
+		return
+	}
+	d.session.Logger().Error("handler panicked on ", evtName, ": ", recovered, "\n", string(stack))
+}
+
+func (d *dispatcher) handlerFailed(evtName string, err error) {
+	if d.onHandlerError != nil {
+		d.onHandlerError(evtName, err)
+		return
+	}
+	d.session.Logger().Error("handler failed on ", evtName, ": ", err)
+}
+
+// withContext returns a shallow copy of the event that holds the given context
+func withContext(evt resource, ctx context.Context) resource {
+	value := reflect.ValueOf(evt)
+	if _, ok := evt.(evtResource); !ok || value.Kind() != reflect.Ptr || value.IsNil() {
+		return evt
+	}
+
+	cp := reflect.New(value.Elem().Type())
+	cp.Elem().Set(value.Elem())
+	resource := cp.Interface().(evtResource)
+	resource.registerContext(ctx)
+	return resource
+}
+
+func newErrorHandlerTimeout(evtName string, timeout time.Duration) *ErrorHandlerTimeout {
+	return &ErrorHandlerTimeout{
+		info:    fmt.Sprintf("handlers of %s did not finish within %s", evtName, timeout),
+		Event:   evtName,
+		Timeout: timeout,
+	}
+}
+
+// ErrorHandlerTimeout is passed to DispatchConfig.OnHandlerError when handlers exceed the DispatchConfig.HandlerTimeout
+type ErrorHandlerTimeout struct {
+	info    string
+	Event   string
+	Timeout time.Duration
+}
+
+func (e *ErrorHandlerTimeout) Error() string {
+	return e.info
 }
 
 //////////////////////////////////////////////////////
@@ -222,6 +451,11 @@ type HandlerCtrl interface {
 type SimplestHandler = func()
 type SimpleHandler = func(Session)
 
+// error handlers are handlers that can fail, the error is passed to DispatchConfig.OnHandlerError.
+// See also the event specific error handlers, eg. MessageCreateErrHandler
+type SimplestErrHandler = func() error
+type SimpleErrHandler = func(Session) error
+
 // Handler needs to match one of the *Handler signatures
 type Handler = interface{}
 
@@ -239,6 +473,34 @@ type handlerSpec struct {
 	middlewares []Middleware
 	handlers    []Handler
 	ctrl        HandlerCtrl
+
+	// unsubscribed is set by Subscription.Unsubscribe, without waiting for running handlers
+	unsubscribed atomic.Bool
+}
+
+// Subscription is a registered handler specification, see Client.Subscribe.
+type Subscription struct {
+	dispatcher *dispatcher
+	evt        string
+	spec       *handlerSpec
+}
+
+// Event returns the name of the event the handlers are registered for
+func (s *Subscription) Event() string {
+	return s.evt
+}
+
+// Unsubscribe removes the middlewares, handlers and controller immediately, and calls HandlerCtrl.OnRemove.
+// Unsubscribe does not wait for running handlers, and a specification that is already being executed for an
+// event is allowed to finish. Calling Unsubscribe more than once, or after the controller has died, has no
+// effect. Unsubscribe is safe to call from within a handler.
+func (s *Subscription) Unsubscribe() {
+	if !s.spec.unsubscribed.CAS(false, true) {
+		return
+	}
+	if removed := s.dispatcher.remove(s.evt, s.spec); len(removed) > 0 {
+		s.dispatcher.notifyRemoved(removed)
+	}
 }
 
 func (hs *handlerSpec) next() bool {
@@ -312,6 +574,17 @@ func (hs *handlerSpec) runMdlws(evt interface{}) interface{} {
 //
 //////////////////////////////////////////////////////
 
+// CtrlOption adds a controller to a typed handler registration, eg. Client.OnMessageCreate. See WithCtrl.
+type CtrlOption struct {
+	ctrl HandlerCtrl
+}
+
+// WithCtrl sets the controller of a typed handler registration. Only the last controller is used.
+//  Client.OnMessageCreate(handler, disgord.WithCtrl(&disgord.Ctrl{Runs: 1}))
+func WithCtrl(ctrl HandlerCtrl) CtrlOption {
+	return CtrlOption{ctrl: ctrl}
+}
+
 // Ctrl is a handler controller that supports lifetime and max number of execution for one or several handlers.
 //  // register only the first 6 votes
 //  Client.On("MESSAGE_CREATE", filter.NonVotes, registerVoteHandler, &disgord.Ctrl{Runs: 6})
@@ -0,0 +1,89 @@
+package disgord
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/andersfylling/disgord/internal/gateway"
+)
+
+//////////////////////////////////////////////////////
+//
+// Event context: The context given to every event, eg. MessageCreate.Ctx.
+//
+// The context is derived from the client lifetime, so it is cancelled on Client.Disconnect, and holds
+// details about the origin of the event. Use the context in REST calls made by a handler, such that
+// the requests are stopped when the bot shuts down.
+//
+//////////////////////////////////////////////////////
+
+type eventCtxKey int
+
+const (
+	eventCtxKeyShardID eventCtxKey = iota
+	eventCtxKeySequence
+	eventCtxKeyTraceID
+)
+
+// EventShardID returns the id of the shard that received the event
+func EventShardID(ctx context.Context) (shardID uint, ok bool) {
+	shardID, ok = ctx.Value(eventCtxKeyShardID).(uint)
+	return shardID, ok
+}
+
+// EventSequence returns the gateway sequence number of the event. The sequence number is only
+// unique for the shard session, see EventShardID.
+func EventSequence(ctx context.Context) (sequence uint64, ok bool) {
+	sequence, ok = ctx.Value(eventCtxKeySequence).(uint64)
+	return sequence, ok
+}
+
+// EventTraceID returns an id that is unique for every event. Useful to correlate log entries
+// of the handlers, and the REST requests they make, with the event.
+func EventTraceID(ctx context.Context) (traceID string, ok bool) {
+	traceID, ok = ctx.Value(eventCtxKeyTraceID).(string)
+	return traceID, ok
+}
+
+// traceIDPrefix makes trace ids unique across processes, while the counter makes them unique within one
+var traceIDPrefix = func() string {
+	prefix := make([]byte, 4)
+	if _, err := rand.Read(prefix); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(prefix)
+}()
+
+var traceIDCounter atomic.Uint64
+
+func newTraceID() string {
+	return traceIDPrefix + "-" + strconv.FormatUint(traceIDCounter.Inc(), 16)
+}
+
+// eventContext creates the context of an incoming event. The context is never cancelled when the
+// dispatcher has no lifetime context and no event deadline, which is the case in unit tests.
+//
+// The context outlives the dispatch, as channel handlers, collectors and goroutines started by the
+// handlers keep using it. It is therefore only cancelled once the event deadline is reached, or
+// when the client disconnects.
+func (d *dispatcher) eventContext(evt *gateway.Event) context.Context {
+	ctx := d.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx = context.WithValue(ctx, eventCtxKeyShardID, evt.ShardID)
+	ctx = context.WithValue(ctx, eventCtxKeySequence, evt.Sequence)
+	ctx = context.WithValue(ctx, eventCtxKeyTraceID, newTraceID())
+	if d.eventDeadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.eventDeadline)
+		context.AfterFunc(ctx, cancel) // releases the timer once the context is done
+	}
+	return ctx
+}
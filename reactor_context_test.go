@@ -0,0 +1,134 @@
+package disgord
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andersfylling/disgord/internal/gateway"
+)
+
+func TestDispatcher_eventContext(t *testing.T) {
+	d := newDispatcher()
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+
+	evt := &gateway.Event{Name: EvtMessageCreate, ShardID: 3, Sequence: 42}
+	ctx := d.eventContext(evt)
+
+	if shardID, ok := EventShardID(ctx); !ok || shardID != 3 {
+		t.Errorf("expected shard id 3, got %d", shardID)
+	}
+	if sequence, ok := EventSequence(ctx); !ok || sequence != 42 {
+		t.Errorf("expected sequence 42, got %d", sequence)
+	}
+	traceID, ok := EventTraceID(ctx)
+	if !ok || traceID == "" {
+		t.Error("expected a trace id")
+	}
+	if other, _ := EventTraceID(d.eventContext(evt)); other == traceID {
+		t.Error("expected every event to have a unique trace id")
+	}
+	if _, hasDeadline := ctx.Deadline(); hasDeadline {
+		t.Error("expected no deadline by default")
+	}
+
+	d.cancel()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the event context to be cancelled with the dispatcher")
+	}
+}
+
+func TestDispatcher_eventContextDeadline(t *testing.T) {
+	d := newDispatcher()
+	d.eventDeadline = 10 * time.Millisecond
+
+	ctx := d.eventContext(&gateway.Event{Name: EvtMessageCreate})
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		t.Fatal("expected the context to have a deadline")
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the event context to expire")
+	}
+}
+
+func TestDemultiplexer_eventContextOutlivesDispatch(t *testing.T) {
+	c := New(Config{
+		BotToken:     "testing",
+		DisableCache: true,
+		DispatchConfig: DispatchConfig{
+			EventDeadline: time.Minute,
+		},
+	})
+	defer close(c.dispatcher.shutdown)
+
+	input := make(chan *gateway.Event)
+	go demultiplexer(c.dispatcher, input, nil)
+
+	// the channel handler returns once the event is queued, long before the consumer reads it
+	messages := make(chan *MessageCreate, 1)
+	c.On(EvtMessageCreate, messages)
+	input <- &gateway.Event{Name: EvtMessageCreate, Data: []byte(`{}`), Sequence: 1}
+
+	var evt *MessageCreate
+	select {
+	case evt = <-messages:
+	case <-time.After(time.Second):
+		t.Fatal("expected the handler to be triggered")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := evt.Ctx.Err(); err != nil {
+		t.Errorf("expected the event context to live until the deadline, got %v", err)
+	}
+	if _, hasDeadline := evt.Ctx.Deadline(); !hasDeadline {
+		t.Error("expected the event context to have a deadline")
+	}
+}
+
+func TestEventContext_missing(t *testing.T) {
+	if _, ok := EventShardID(context.Background()); ok {
+		t.Error("expected no shard id")
+	}
+	if _, ok := EventSequence(context.Background()); ok {
+		t.Error("expected no sequence")
+	}
+	if _, ok := EventTraceID(context.Background()); ok {
+		t.Error("expected no trace id")
+	}
+}
+
+func TestDemultiplexer_eventContext(t *testing.T) {
+	c := New(Config{
+		BotToken:     "testing",
+		DisableCache: true,
+	})
+	defer close(c.dispatcher.shutdown)
+
+	input := make(chan *gateway.Event)
+	go demultiplexer(c.dispatcher, input, nil)
+
+	contexts := make(chan context.Context, 1)
+	c.On(EvtMessageCreate, func(s Session, evt *MessageCreate) {
+		contexts <- evt.Ctx
+	})
+	input <- &gateway.Event{Name: EvtMessageCreate, Data: []byte(`{}`), ShardID: 1, Sequence: 7}
+
+	var ctx context.Context
+	select {
+	case ctx = <-contexts:
+	case <-time.After(time.Second):
+		t.Fatal("expected the handler to be triggered")
+	}
+	if sequence, _ := EventSequence(ctx); sequence != 7 {
+		t.Errorf("expected sequence 7, got %d", sequence)
+	}
+
+	c.dispatcher.cancel() // as done by Client.Disconnect
+	if ctx.Err() == nil {
+		t.Error("expected the event context to be cancelled on disconnect")
+	}
+}
@@ -0,0 +1,189 @@
+package disgord
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/andersfylling/disgord/internal/gateway"
+	"github.com/andersfylling/disgord/internal/util"
+)
+
+//////////////////////////////////////////////////////
+//
+// Deduplication: Discards events that were already dispatched, eg. replayed after a resume.
+//
+// An event is identified by the event name, the ids of the entities it regards and the timestamp
+// of the change. eg. MESSAGE_UPDATE:{message.id}:{message.edited_timestamp}
+// Events without a timestamp, such as reactions, are identified by the shard sequence number instead,
+// as a resume replays the events with their original sequence number.
+//
+//////////////////////////////////////////////////////
+
+// DefaultDeduplicationCapacity is the default number of events remembered for deduplication
+const DefaultDeduplicationCapacity = 10000
+
+// DeduplicationConfig enables deduplication of events, see DispatchConfig.Deduplication.
+type DeduplicationConfig struct {
+	// Events holds the events to deduplicate, and for how long an event is remembered.
+	//  Events: map[string]time.Duration{
+	//      disgord.EvtMessageCreate:      5*time.Minute,
+	//      disgord.EvtMessageReactionAdd: time.Minute,
+	//  }
+	Events map[string]time.Duration
+
+	// Capacity bounds the number of remembered events. When the capacity is reached the oldest events
+	// are forgotten, even if their window has not passed. Defaults to DefaultDeduplicationCapacity.
+	Capacity int
+}
+
+type dedupEntry struct {
+	key     string
+	expires time.Time
+}
+
+type deduplicator struct {
+	sync.Mutex
+	windows  map[string]time.Duration
+	capacity int
+	now      func() time.Time
+
+	seen  map[string]time.Time
+	order []dedupEntry // oldest first
+
+	discarded atomic.Uint64
+}
+
+func newDeduplicator(conf *DeduplicationConfig) *deduplicator {
+	if conf == nil || len(conf.Events) == 0 {
+		return nil
+	}
+
+	d := &deduplicator{
+		windows:  make(map[string]time.Duration, len(conf.Events)),
+		capacity: conf.Capacity,
+		now:      time.Now,
+		seen:     make(map[string]time.Time),
+	}
+	if d.capacity <= 0 {
+		d.capacity = DefaultDeduplicationCapacity
+	}
+	for name, window := range conf.Events {
+		if window > 0 {
+			d.windows[name] = window
+		}
+	}
+	return d
+}
+
+// duplicate checks if the event was seen within its window, and remembers it otherwise
+func (d *deduplicator) duplicate(evt *gateway.Event) bool {
+	window, ok := d.windows[evt.Name]
+	if !ok {
+		return false
+	}
+	key := dedupKey(evt)
+	if key == "" {
+		return false // the event can not be identified
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	now := d.now()
+	d.forget(now)
+	if expires, seen := d.seen[key]; seen && now.Before(expires) {
+		d.discarded.Inc()
+		return true
+	}
+
+	expires := now.Add(window)
+	d.seen[key] = expires
+	d.order = append(d.order, dedupEntry{key: key, expires: expires})
+	return false
+}
+
+// forget removes the expired events at the front of the queue, and the oldest events when at capacity.
+// Events are remembered in the order they are received, and the windows differ between the event types,
+// so an expired event can be remembered longer than needed but is never mistaken for a duplicate.
+func (d *deduplicator) forget(now time.Time) {
+	var i int
+	for ; i < len(d.order); i++ {
+		if len(d.order)-i < d.capacity && now.Before(d.order[i].expires) {
+			break
+		}
+
+		entry := d.order[i]
+		if d.seen[entry.key] == entry.expires {
+			delete(d.seen, entry.key)
+		}
+	}
+	if i > 0 {
+		d.order = append(d.order[:0:0], d.order[i:]...)
+	}
+}
+
+// dedupFields are the fields that can identify an event
+type dedupFields struct {
+	ID        Snowflake `json:"id"`
+	MessageID Snowflake `json:"message_id"`
+	ChannelID Snowflake `json:"channel_id"`
+	GuildID   Snowflake `json:"guild_id"`
+	UserID    Snowflake `json:"user_id"`
+	User      *struct {
+		ID Snowflake `json:"id"`
+	} `json:"user"`
+	Emoji *struct {
+		ID   Snowflake `json:"id"`
+		Name string    `json:"name"`
+	} `json:"emoji"`
+
+	// the timestamps are not parsed, as their format differs between events
+	Timestamp       json.RawMessage `json:"timestamp"`
+	EditedTimestamp json.RawMessage `json:"edited_timestamp"`
+	JoinedAt        json.RawMessage `json:"joined_at"`
+}
+
+// dedupKey creates a key from the event name, the ids of the entities and the timestamp of the change, or
+// the shard sequence number when the event has no timestamp. An empty key is returned when the event holds no ids.
+func dedupKey(evt *gateway.Event) string {
+	fields := &dedupFields{}
+	if err := util.Unmarshal(evt.Data, fields); err != nil {
+		return ""
+	}
+
+	userID := fields.UserID
+	if userID.IsZero() && fields.User != nil {
+		userID = fields.User.ID
+	}
+	ids := []Snowflake{fields.ID, fields.MessageID, fields.ChannelID, fields.GuildID, userID}
+
+	var identified bool
+	key := make([]string, 0, len(ids)+3)
+	key = append(key, evt.Name)
+	for _, id := range ids {
+		identified = identified || !id.IsZero()
+		key = append(key, id.String())
+	}
+	if !identified {
+		return ""
+	}
+
+	if fields.Emoji != nil {
+		key = append(key, fields.Emoji.ID.String()+fields.Emoji.Name)
+	}
+	for _, timestamp := range []json.RawMessage{fields.EditedTimestamp, fields.Timestamp, fields.JoinedAt} {
+		if len(timestamp) > 0 && string(timestamp) != "null" {
+			key = append(key, string(timestamp))
+			return strings.Join(key, ":")
+		}
+	}
+
+	// eg. a reaction that is removed and added again is identical to the first one, apart from the sequence
+	key = append(key, "#"+strconv.FormatUint(uint64(evt.ShardID), 10)+"-"+strconv.FormatUint(evt.Sequence, 10))
+	return strings.Join(key, ":")
+}
@@ -0,0 +1,145 @@
+package disgord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andersfylling/disgord/internal/gateway"
+)
+
+func TestDedupKey(t *testing.T) {
+	testCases := []struct {
+		name    string
+		evtName string
+		data    string
+		expects string
+	}{
+		{"message", EvtMessageCreate, `{"id":"486833611564253184","channel_id":"486833611564253185","timestamp":"2020-01-01T00:00:00Z"}`,
+			`MESSAGE_CREATE:486833611564253184:0:486833611564253185:0:0:"2020-01-01T00:00:00Z"`},
+		{"edited message", EvtMessageUpdate, `{"id":"486833611564253184","timestamp":"2020-01-01T00:00:00Z","edited_timestamp":"2020-01-02T00:00:00Z"}`,
+			`MESSAGE_UPDATE:486833611564253184:0:0:0:0:"2020-01-02T00:00:00Z"`},
+		{"reaction", EvtMessageReactionAdd, `{"message_id":"486833611564253184","user_id":"486833611564253186","emoji":{"id":null,"name":"x"}}`,
+			`MESSAGE_REACTION_ADD:0:486833611564253184:0:0:486833611564253186:0x:#2-42`},
+		{"member", EvtGuildMemberAdd, `{"guild_id":"486833611564253187","user":{"id":"486833611564253186"},"joined_at":"2020-01-01T00:00:00Z"}`,
+			`GUILD_MEMBER_ADD:0:0:0:486833611564253187:486833611564253186:"2020-01-01T00:00:00Z"`},
+		{"typing", EvtTypingStart, `{"channel_id":"486833611564253185","user_id":"486833611564253186","timestamp":1577836800}`,
+			`TYPING_START:0:0:486833611564253185:0:486833611564253186:1577836800`},
+		{"no ids", EvtReady, `{"v":6}`, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			evt := &gateway.Event{Name: tc.evtName, Data: []byte(tc.data), ShardID: 2, Sequence: 42}
+			if key := dedupKey(evt); key != tc.expects {
+				t.Errorf("expected key %s, got %s", tc.expects, key)
+			}
+		})
+	}
+}
+
+func TestDeduplicator(t *testing.T) {
+	d := newDeduplicator(&DeduplicationConfig{
+		Events: map[string]time.Duration{
+			EvtMessageCreate: time.Minute,
+		},
+		Capacity: 2,
+	})
+	now := time.Now()
+	d.now = func() time.Time {
+		return now
+	}
+
+	message := func(id string) *gateway.Event {
+		return &gateway.Event{Name: EvtMessageCreate, Data: []byte(`{"id":"` + id + `"}`)}
+	}
+	if d.duplicate(message("486833611564253184")) {
+		t.Error("expected the first event to be dispatched")
+	}
+	if !d.duplicate(message("486833611564253184")) {
+		t.Error("expected the second event to be discarded")
+	}
+	if d.duplicate(&gateway.Event{Name: EvtMessageDelete, Data: []byte(`{"id":"486833611564253184"}`)}) {
+		t.Error("expected events without a window to be dispatched")
+	}
+
+	now = now.Add(time.Minute)
+	if d.duplicate(message("486833611564253184")) {
+		t.Error("expected the event to be dispatched after the window")
+	}
+
+	// the capacity forgets the oldest event
+	d.duplicate(message("486833611564253185"))
+	d.duplicate(message("486833611564253186"))
+	if d.duplicate(message("486833611564253184")) {
+		t.Error("expected the oldest event to be forgotten")
+	}
+	if len(d.seen) > 2 || len(d.order) > 2 {
+		t.Errorf("expected at most 2 remembered events, got %d", len(d.seen))
+	}
+	if discarded := d.discarded.Load(); discarded != 1 {
+		t.Errorf("expected 1 discarded event, got %d", discarded)
+	}
+}
+
+func TestDeduplicator_withoutTimestamp(t *testing.T) {
+	d := newDeduplicator(&DeduplicationConfig{
+		Events: map[string]time.Duration{
+			EvtMessageReactionAdd: time.Minute,
+		},
+	})
+
+	reaction := func(sequence uint64) *gateway.Event {
+		data := `{"message_id":"486833611564253184","user_id":"486833611564253186","emoji":{"id":null,"name":"x"}}`
+		return &gateway.Event{Name: EvtMessageReactionAdd, Data: []byte(data), Sequence: sequence}
+	}
+	if d.duplicate(reaction(1)) {
+		t.Error("expected the first reaction to be dispatched")
+	}
+	if !d.duplicate(reaction(1)) {
+		t.Error("expected the replayed reaction to be discarded")
+	}
+	if d.duplicate(reaction(3)) {
+		t.Error("expected the reaction to be dispatched when added again after a removal")
+	}
+}
+
+func TestDemultiplexer_Deduplication(t *testing.T) {
+	c := New(Config{
+		BotToken:     "testing",
+		DisableCache: true,
+		DispatchConfig: DispatchConfig{
+			Workers: 1,
+			Deduplication: &DeduplicationConfig{
+				Events: map[string]time.Duration{EvtMessageCreate: time.Minute},
+			},
+		},
+	})
+	defer close(c.dispatcher.shutdown)
+
+	messages := make(chan *MessageCreate, 3)
+	c.On(EvtMessageCreate, func(s Session, evt *MessageCreate) {
+		messages <- evt
+	})
+
+	input := make(chan *gateway.Event)
+	go demultiplexer(c.dispatcher, input, nil)
+
+	replayed := []byte(`{"id":"486833611564253184","content":"reward"}`)
+	input <- &gateway.Event{Name: EvtMessageCreate, Data: replayed, Sequence: 1}
+	input <- &gateway.Event{Name: EvtMessageCreate, Data: replayed, Sequence: 1}
+	input <- &gateway.Event{Name: EvtMessageCreate, Data: []byte(`{"id":"486833611564253185","content":"next"}`), Sequence: 2}
+
+	for _, expects := range []string{"reward", "next"} {
+		select {
+		case msg := <-messages:
+			if msg.Message.Content != expects {
+				t.Errorf("expected %s, got %s", expects, msg.Message.Content)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %s to be dispatched", expects)
+		}
+	}
+	if stats := c.DispatchStats(); stats.Deduplicated != 1 {
+		t.Errorf("expected 1 deduplicated event, got %d", stats.Deduplicated)
+	}
+}
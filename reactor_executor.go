@@ -0,0 +1,369 @@
+package disgord
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/andersfylling/disgord/internal/util"
+)
+
+//////////////////////////////////////////////////////
+//
+// Executor: Decides which goroutine runs the handlers of an event.
+//
+// Events are queued by the demultiplexer and handled by a fixed number of workers. When the
+// queue is full a FullQueuePolicy decides if the demultiplexer waits, or if events are dropped.
+// With a DispatchOrdering, related events are queued to the same worker such that they are
+// handled one at a time in the order they were received.
+//
+//////////////////////////////////////////////////////
+
+// FullQueuePolicy dictates what happens to new events when the dispatch queue is full
+type FullQueuePolicy int
+
+const (
+	// FullQueueBlock waits for the queue to have room. The shards stop reading from the gateway in the
+	// mean time, which is the safest option as no events are lost.
+	FullQueueBlock FullQueuePolicy = iota
+
+	// FullQueueDropOldest removes the oldest queued event to make room for the new event
+	FullQueueDropOldest
+
+	// FullQueueDropByEventType drops new events listed in DispatchConfig.DroppableEvents. Other events block.
+	FullQueueDropByEventType
+)
+
+const (
+	DefaultDispatchWorkers   = 32
+	DefaultDispatchQueueSize = 1024
+)
+
+// DispatchOrdering decides which events must be handled in the order they were received from the gateway
+type DispatchOrdering int
+
+const (
+	// OrderNone handles events in parallel, without any ordering guarantees
+	OrderNone DispatchOrdering = iota
+
+	// OrderByGuild handles events of the same guild one at a time, in the order they were received.
+	// Events that are not related to a guild, such as direct messages, are ordered by channel.
+	OrderByGuild
+
+	// OrderByChannel handles events of the same channel one at a time, in the order they were received
+	OrderByChannel
+
+	// OrderByUser handles events triggered by, or related to, the same user one at a time, in the
+	// order they were received. eg. the message author or the guild member.
+	OrderByUser
+)
+
+// DispatchConfig decides how events are delivered to the registered handlers.
+type DispatchConfig struct {
+	// Workers is the number of goroutines that executes handlers. Defaults to DefaultDispatchWorkers.
+	// Note that a blocking handler, or an unbuffered handler channel that is not read from, keeps a worker busy.
+	Workers int
+
+	// QueueSize is the number of events that can wait for a worker. Defaults to DefaultDispatchQueueSize.
+	// When an Ordering is set, the queue is split evenly between the workers.
+	QueueSize int
+
+	// FullQueuePolicy is applied when the queue is full. Defaults to FullQueueBlock.
+	FullQueuePolicy FullQueuePolicy
+
+	// DroppableEvents are the event names that can be dropped by FullQueueDropByEventType. eg. EvtTypingStart
+	DroppableEvents []string
+
+	// Ordering guarantees that related events are handled in gateway order, while unrelated events
+	// are still handled in parallel. Every key is assigned to a single worker, so a slow handler delays
+	// the other keys of that worker. Events without the key, eg. READY, have no ordering guarantees.
+	// Defaults to OrderNone. Ignored when GoroutinePerEvent is set.
+	Ordering DispatchOrdering
+
+	// GoroutinePerEvent disables the workers and queue, and handles every event in a new goroutine.
+	// This was the default behaviour before the workers were introduced. Memory usage is unbounded.
+	GoroutinePerEvent bool
+
+	// HandlerTimeout is the time a handler specification, its middlewares and handlers, has to handle
+	// an event. The event context, eg. MessageCreate.Ctx, is cancelled when the time is up, and the
+	// next specifications are executed without waiting for the remaining handlers. An ErrorHandlerTimeout
+	// is passed to OnHandlerError. Disabled by default.
+	HandlerTimeout time.Duration
+
+	// EventDeadline is the time every event, and the REST requests made using the event context, has
+	// to complete. The event context, eg. MessageCreate.Ctx, is cancelled when the deadline is reached.
+	// Unlike HandlerTimeout, handlers are not interrupted. Disabled by default.
+	EventDeadline time.Duration
+
+	// Deduplication discards events that were already dispatched, such as events replayed after a
+	// resume, so handlers with side effects do not fire twice. Disabled by default.
+	Deduplication *DeduplicationConfig
+
+	// OnHandlerPanic is called with the recovered value and stack trace when a handler or middleware
+	// panics. Defaults to logging the panic as an error. The remaining handlers are still executed.
+	OnHandlerPanic func(evtName string, recovered interface{}, stack []byte)
+
+	// OnHandlerError receives the errors returned by error handlers, eg. MessageCreateErrHandler,
+	// and handler timeouts. Defaults to logging the error.
+	OnHandlerError func(evtName string, err error)
+}
+
+// DispatchStats holds metrics about the event dispatching, see Client.DispatchStats
+type DispatchStats struct {
+	// QueueDepth is the number of events waiting for a worker
+	QueueDepth int
+	QueueSize  int
+	Workers    int
+
+	// MaxQueueDepth is the highest number of waiting events seen
+	MaxQueueDepth int
+
+	// Dispatched is the number of events handed to the handlers, and Dropped the number of events
+	// discarded by the FullQueuePolicy
+	Dispatched uint64
+	Dropped    uint64
+
+	// Deduplicated is the number of events discarded as duplicates, see DispatchConfig.Deduplication
+	Deduplicated uint64
+}
+
+type dispatchJob struct {
+	ctx     context.Context
+	evtName string
+	evt     resource
+
+	// key identifies events that must be handled in order, see DispatchOrdering
+	key Snowflake
+}
+
+type dispatchExecutor struct {
+	conf      DispatchConfig
+	droppable map[string]bool
+	dispatch  func(ctx context.Context, evtName string, evt resource)
+	startOnce sync.Once
+
+	// queue is shared by all the workers. When events are ordered, every worker
+	// also has a queue of their own in keyed.
+	queue chan *dispatchJob
+	keyed []chan *dispatchJob
+
+	dispatched    atomic.Uint64
+	dropped       atomic.Uint64
+	maxQueueDepth atomic.Int64
+}
+
+func newDispatchExecutor(conf DispatchConfig, dispatch func(ctx context.Context, evtName string, evt resource)) *dispatchExecutor {
+	if conf.Workers <= 0 {
+		conf.Workers = DefaultDispatchWorkers
+	}
+	if conf.QueueSize <= 0 {
+		conf.QueueSize = DefaultDispatchQueueSize
+	}
+	if conf.GoroutinePerEvent {
+		conf.Ordering = OrderNone
+	}
+
+	e := &dispatchExecutor{
+		conf:      conf,
+		droppable: make(map[string]bool),
+		dispatch:  dispatch,
+	}
+	for _, name := range conf.DroppableEvents {
+		e.droppable[name] = true
+	}
+	switch {
+	case conf.GoroutinePerEvent:
+	case conf.Ordering == OrderNone:
+		e.queue = make(chan *dispatchJob, conf.QueueSize)
+	default:
+		size := conf.QueueSize / conf.Workers
+		if size < 1 {
+			size = 1
+		}
+		e.queue = make(chan *dispatchJob, size)
+		e.keyed = make([]chan *dispatchJob, conf.Workers)
+		for i := range e.keyed {
+			e.keyed[i] = make(chan *dispatchJob, size)
+		}
+	}
+	return e
+}
+
+// start spawns the workers, only the first call has any effect
+func (e *dispatchExecutor) start(shutdown <-chan struct{}) {
+	if e.conf.GoroutinePerEvent {
+		return
+	}
+	e.startOnce.Do(func() {
+		for i := 0; i < e.conf.Workers; i++ {
+			var keyed chan *dispatchJob // nil channels are never selected
+			if e.keyed != nil {
+				keyed = e.keyed[i]
+			}
+			go e.work(keyed, shutdown)
+		}
+	})
+}
+
+func (e *dispatchExecutor) work(keyed <-chan *dispatchJob, shutdown <-chan struct{}) {
+	for {
+		var job *dispatchJob
+		select {
+		case job = <-keyed:
+		case job = <-e.queue:
+		case <-shutdown:
+			return
+		}
+		e.dispatched.Inc()
+		e.dispatch(job.ctx, job.evtName, job.evt)
+	}
+}
+
+// key returns the ordering key of an event, or 0 if the event does not need to be ordered
+func (e *dispatchExecutor) key(evtName string, data []byte) Snowflake {
+	if e.keyed == nil {
+		return 0
+	}
+	return dispatchOrderingKey(e.conf.Ordering, evtName, data)
+}
+
+// queueFor returns the queue of the job. Jobs with the same key always share queue and worker.
+func (e *dispatchExecutor) queueFor(job *dispatchJob) chan *dispatchJob {
+	if job.key.IsZero() || e.keyed == nil {
+		return e.queue
+	}
+
+	// snowflakes are not evenly distributed in the lower bits, so the key is hashed first
+	hash := (uint64(job.key) * 0x9E3779B97F4A7C15) >> 32
+	return e.keyed[hash%uint64(len(e.keyed))]
+}
+
+// submit queues the event according to the FullQueuePolicy. It must only be called by one goroutine.
+func (e *dispatchExecutor) submit(job *dispatchJob, shutdown <-chan struct{}) {
+	if e.conf.GoroutinePerEvent {
+		e.dispatched.Inc()
+		go e.dispatch(job.ctx, job.evtName, job.evt)
+		return
+	}
+
+	queue := e.queueFor(job)
+	defer e.updateMaxQueueDepth()
+
+	// fast path
+	select {
+	case queue <- job:
+		return
+	default:
+	}
+
+	switch {
+	case e.conf.FullQueuePolicy == FullQueueDropOldest:
+		for {
+			select {
+			case queue <- job:
+				return
+			default:
+			}
+
+			select {
+			case <-queue:
+				e.dropped.Inc()
+			default:
+			}
+		}
+	case e.conf.FullQueuePolicy == FullQueueDropByEventType && e.droppable[job.evtName]:
+		e.dropped.Inc()
+	default:
+		select {
+		case queue <- job:
+		case <-shutdown:
+		}
+	}
+}
+
+func (e *dispatchExecutor) queueDepth() (depth int) {
+	depth = len(e.queue)
+	for _, queue := range e.keyed {
+		depth += len(queue)
+	}
+	return depth
+}
+
+func (e *dispatchExecutor) updateMaxQueueDepth() {
+	depth := int64(e.queueDepth())
+	for {
+		max := e.maxQueueDepth.Load()
+		if depth <= max || e.maxQueueDepth.CAS(max, depth) {
+			return
+		}
+	}
+}
+
+func (e *dispatchExecutor) stats() DispatchStats {
+	stats := DispatchStats{
+		QueueDepth:    e.queueDepth(),
+		QueueSize:     cap(e.queue),
+		MaxQueueDepth: int(e.maxQueueDepth.Load()),
+		Dispatched:    e.dispatched.Load(),
+		Dropped:       e.dropped.Load(),
+	}
+	for _, queue := range e.keyed {
+		stats.QueueSize += cap(queue)
+	}
+	if !e.conf.GoroutinePerEvent {
+		stats.Workers = e.conf.Workers
+	}
+	return stats
+}
+
+// dispatchOrderingKeys holds the fields of an event payload that can identify the guild,
+// channel or user it relates to
+type dispatchOrderingKeys struct {
+	ID        Snowflake `json:"id"`
+	GuildID   Snowflake `json:"guild_id"`
+	ChannelID Snowflake `json:"channel_id"`
+	UserID    Snowflake `json:"user_id"`
+	User      *struct {
+		ID Snowflake `json:"id"`
+	} `json:"user"`
+	Author *struct {
+		ID Snowflake `json:"id"`
+	} `json:"author"`
+}
+
+func dispatchOrderingKey(ordering DispatchOrdering, evtName string, data []byte) Snowflake {
+	keys := &dispatchOrderingKeys{}
+	if err := util.Unmarshal(data, keys); err != nil {
+		return 0
+	}
+
+	guildID, channelID := keys.GuildID, keys.ChannelID
+	switch evtName {
+	case EvtGuildCreate, EvtGuildUpdate, EvtGuildDelete:
+		guildID = keys.ID
+	case EvtChannelCreate, EvtChannelUpdate, EvtChannelDelete:
+		channelID = keys.ID
+	}
+
+	switch ordering {
+	case OrderByGuild:
+		if guildID.IsZero() {
+			return channelID
+		}
+		return guildID
+	case OrderByChannel:
+		return channelID
+	case OrderByUser:
+		switch {
+		case !keys.UserID.IsZero():
+			return keys.UserID
+		case keys.User != nil:
+			return keys.User.ID
+		case keys.Author != nil:
+			return keys.Author.ID
+		}
+	}
+	return 0
+}
@@ -0,0 +1,269 @@
+package disgord
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newBlockedExecutor(conf DispatchConfig) (e *dispatchExecutor, release func(), seen func() []string) {
+	var mu sync.Mutex
+	var names []string
+	gate := make(chan struct{})
+	e = newDispatchExecutor(conf, func(ctx context.Context, evtName string, evt resource) {
+		<-gate
+		mu.Lock()
+		names = append(names, evtName)
+		mu.Unlock()
+	})
+	release = func() {
+		close(gate)
+	}
+	seen = func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), names...)
+	}
+	return e, release, seen
+}
+
+func waitForDispatched(t *testing.T, e *dispatchExecutor, expects uint64) {
+	deadline := time.Now().Add(time.Second)
+	for e.stats().Dispatched < expects {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d dispatched events, got %d", expects, e.stats().Dispatched)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDispatchExecutor_Defaults(t *testing.T) {
+	e := newDispatchExecutor(DispatchConfig{}, nil)
+	stats := e.stats()
+	if stats.Workers != DefaultDispatchWorkers {
+		t.Errorf("expected %d workers, got %d", DefaultDispatchWorkers, stats.Workers)
+	}
+	if stats.QueueSize != DefaultDispatchQueueSize {
+		t.Errorf("expected queue size %d, got %d", DefaultDispatchQueueSize, stats.QueueSize)
+	}
+}
+
+func TestDispatchExecutor_Block(t *testing.T) {
+	shutdown := make(chan struct{})
+	defer close(shutdown)
+
+	e, release, seen := newBlockedExecutor(DispatchConfig{Workers: 1, QueueSize: 1})
+	e.start(shutdown)
+
+	e.submit(&dispatchJob{evtName: "1"}, shutdown)
+	waitForDispatched(t, e, 1) // worker is now busy
+	e.submit(&dispatchJob{evtName: "2"}, shutdown)
+
+	submitted := make(chan struct{})
+	go func() {
+		e.submit(&dispatchJob{evtName: "3"}, shutdown)
+		close(submitted)
+	}()
+
+	select {
+	case <-submitted:
+		t.Fatal("expected submit to block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release()
+	<-submitted
+	waitForDispatched(t, e, 3)
+
+	stats := e.stats()
+	if stats.Dropped != 0 {
+		t.Errorf("expected no dropped events, got %d", stats.Dropped)
+	}
+	if stats.MaxQueueDepth != 1 {
+		t.Errorf("expected max queue depth 1, got %d", stats.MaxQueueDepth)
+	}
+	if got := seen(); len(got) != 3 {
+		t.Errorf("expected 3 handled events, got %+v", got)
+	}
+}
+
+func TestDispatchExecutor_DropOldest(t *testing.T) {
+	shutdown := make(chan struct{})
+	defer close(shutdown)
+
+	e, release, seen := newBlockedExecutor(DispatchConfig{
+		Workers:         1,
+		QueueSize:       2,
+		FullQueuePolicy: FullQueueDropOldest,
+	})
+	e.start(shutdown)
+
+	e.submit(&dispatchJob{evtName: "1"}, shutdown)
+	waitForDispatched(t, e, 1)
+	for _, name := range []string{"2", "3", "4", "5"} {
+		e.submit(&dispatchJob{evtName: name}, shutdown)
+	}
+
+	if dropped := e.stats().Dropped; dropped != 2 {
+		t.Errorf("expected 2 dropped events, got %d", dropped)
+	}
+
+	release()
+	waitForDispatched(t, e, 3)
+	got := seen()
+	if len(got) != 3 || got[0] != "1" || got[1] != "4" || got[2] != "5" {
+		t.Errorf("expected the oldest events to be dropped, got %+v", got)
+	}
+}
+
+func TestDispatchExecutor_DropByEventType(t *testing.T) {
+	shutdown := make(chan struct{})
+	defer close(shutdown)
+
+	e, release, seen := newBlockedExecutor(DispatchConfig{
+		Workers:         1,
+		QueueSize:       1,
+		FullQueuePolicy: FullQueueDropByEventType,
+		DroppableEvents: []string{EvtTypingStart},
+	})
+	e.start(shutdown)
+
+	e.submit(&dispatchJob{evtName: EvtMessageCreate}, shutdown)
+	waitForDispatched(t, e, 1)
+	e.submit(&dispatchJob{evtName: EvtMessageCreate}, shutdown)
+	e.submit(&dispatchJob{evtName: EvtTypingStart}, shutdown) // dropped
+
+	submitted := make(chan struct{})
+	go func() {
+		e.submit(&dispatchJob{evtName: EvtMessageCreate}, shutdown) // blocks
+		close(submitted)
+	}()
+	select {
+	case <-submitted:
+		t.Fatal("expected non droppable events to block")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release()
+	<-submitted
+	waitForDispatched(t, e, 3)
+
+	if dropped := e.stats().Dropped; dropped != 1 {
+		t.Errorf("expected 1 dropped event, got %d", dropped)
+	}
+	for _, name := range seen() {
+		if name == EvtTypingStart {
+			t.Errorf("expected %s to be dropped", EvtTypingStart)
+		}
+	}
+}
+
+func TestDispatchExecutor_GoroutinePerEvent(t *testing.T) {
+	shutdown := make(chan struct{})
+	defer close(shutdown)
+
+	e, release, _ := newBlockedExecutor(DispatchConfig{GoroutinePerEvent: true})
+	e.start(shutdown)
+	defer release()
+
+	// nothing blocks, as every event gets its own goroutine
+	for i := 0; i < 100; i++ {
+		e.submit(&dispatchJob{evtName: EvtMessageCreate}, shutdown)
+	}
+
+	stats := e.stats()
+	if stats.Dispatched != 100 {
+		t.Errorf("expected 100 dispatched events, got %d", stats.Dispatched)
+	}
+	if stats.Workers != 0 || stats.QueueSize != 0 {
+		t.Errorf("expected no workers nor queue, got %+v", stats)
+	}
+}
+
+func TestDispatchExecutor_Ordering(t *testing.T) {
+	shutdown := make(chan struct{})
+	defer close(shutdown)
+
+	const keys = 10
+	const eventsPerKey = 50
+
+	var mu sync.Mutex
+	handled := make(map[Snowflake][]int)
+	wg := sync.WaitGroup{}
+	wg.Add(keys * eventsPerKey)
+
+	e := newDispatchExecutor(DispatchConfig{
+		Workers:  4,
+		Ordering: OrderByGuild,
+	}, func(ctx context.Context, evtName string, evt resource) {
+		defer wg.Done()
+		var key Snowflake
+		var seq int
+		fmt.Sscanf(evtName, "%d:%d", &key, &seq)
+		if seq%7 == 0 {
+			time.Sleep(time.Millisecond) // give later events a chance to overtake
+		}
+
+		mu.Lock()
+		handled[key] = append(handled[key], seq)
+		mu.Unlock()
+	})
+	e.start(shutdown)
+
+	for seq := 0; seq < eventsPerKey; seq++ {
+		for key := Snowflake(1); key <= keys; key++ {
+			e.submit(&dispatchJob{
+				evtName: fmt.Sprintf("%d:%d", key, seq),
+				key:     key << 22,
+			}, shutdown)
+		}
+	}
+	wg.Wait()
+
+	for key, seqs := range handled {
+		for i := range seqs {
+			if seqs[i] != i {
+				t.Fatalf("events of key %d were handled out of order: %+v", key, seqs)
+			}
+		}
+	}
+}
+
+func TestDispatchOrderingKey(t *testing.T) {
+	const guildID, channelID, userID = Snowflake(1), Snowflake(2), Snowflake(3)
+	message := []byte(`{"id":"9","guild_id":"1","channel_id":"2","author":{"id":"3"}}`)
+	directMessage := []byte(`{"id":"9","channel_id":"2","author":{"id":"3"}}`)
+	guild := []byte(`{"id":"1"}`)
+	channel := []byte(`{"id":"2","guild_id":"1"}`)
+	member := []byte(`{"guild_id":"1","user":{"id":"3"}}`)
+	typing := []byte(`{"guild_id":"1","channel_id":"2","user_id":"3"}`)
+
+	testCases := []struct {
+		ordering DispatchOrdering
+		evtName  string
+		data     []byte
+		expects  Snowflake
+	}{
+		{OrderNone, EvtMessageCreate, message, 0},
+		{OrderByGuild, EvtMessageCreate, message, guildID},
+		{OrderByGuild, EvtMessageCreate, directMessage, channelID},
+		{OrderByGuild, EvtGuildCreate, guild, guildID},
+		{OrderByGuild, EvtChannelCreate, channel, guildID},
+		{OrderByGuild, EvtReady, []byte(`{}`), 0},
+		{OrderByChannel, EvtMessageUpdate, message, channelID},
+		{OrderByChannel, EvtChannelUpdate, channel, channelID},
+		{OrderByChannel, EvtGuildCreate, guild, 0},
+		{OrderByUser, EvtMessageCreate, message, userID},
+		{OrderByUser, EvtGuildMemberAdd, member, userID},
+		{OrderByUser, EvtTypingStart, typing, userID},
+		{OrderByUser, EvtGuildCreate, guild, 0},
+	}
+
+	for i, tc := range testCases {
+		if got := dispatchOrderingKey(tc.ordering, tc.evtName, tc.data); got != tc.expects {
+			t.Errorf("[%d] %s: expected key %d, got %d", i, tc.evtName, tc.expects, got)
+		}
+	}
+}
@@ -89,138 +89,214 @@ func isHandler(h Handler) (ok bool) {
 		ok = true
 	case SimplestHandler:
 		ok = true
+	case SimpleErrHandler:
+		ok = true
+	case SimplestErrHandler:
+		ok = true
+	case RawEventHandler:
+		ok = true
+	case RawEventErrHandler:
+		ok = true
+	case chan *RawEvent:
+		ok = true
 	case chan interface{}:
 		ok = true
 	case ChannelCreateHandler:
 		ok = true
+	case ChannelCreateErrHandler:
+		ok = true
 	case chan *ChannelCreate:
 		ok = true
 	case ChannelDeleteHandler:
 		ok = true
+	case ChannelDeleteErrHandler:
+		ok = true
 	case chan *ChannelDelete:
 		ok = true
 	case ChannelPinsUpdateHandler:
 		ok = true
+	case ChannelPinsUpdateErrHandler:
+		ok = true
 	case chan *ChannelPinsUpdate:
 		ok = true
 	case ChannelUpdateHandler:
 		ok = true
+	case ChannelUpdateErrHandler:
+		ok = true
 	case chan *ChannelUpdate:
 		ok = true
 	case GuildBanAddHandler:
 		ok = true
+	case GuildBanAddErrHandler:
+		ok = true
 	case chan *GuildBanAdd:
 		ok = true
 	case GuildBanRemoveHandler:
 		ok = true
+	case GuildBanRemoveErrHandler:
+		ok = true
 	case chan *GuildBanRemove:
 		ok = true
 	case GuildCreateHandler:
 		ok = true
+	case GuildCreateErrHandler:
+		ok = true
 	case chan *GuildCreate:
 		ok = true
 	case GuildDeleteHandler:
 		ok = true
+	case GuildDeleteErrHandler:
+		ok = true
 	case chan *GuildDelete:
 		ok = true
 	case GuildEmojisUpdateHandler:
 		ok = true
+	case GuildEmojisUpdateErrHandler:
+		ok = true
 	case chan *GuildEmojisUpdate:
 		ok = true
 	case GuildIntegrationsUpdateHandler:
 		ok = true
+	case GuildIntegrationsUpdateErrHandler:
+		ok = true
 	case chan *GuildIntegrationsUpdate:
 		ok = true
 	case GuildMemberAddHandler:
 		ok = true
+	case GuildMemberAddErrHandler:
+		ok = true
 	case chan *GuildMemberAdd:
 		ok = true
 	case GuildMemberRemoveHandler:
 		ok = true
+	case GuildMemberRemoveErrHandler:
+		ok = true
 	case chan *GuildMemberRemove:
 		ok = true
 	case GuildMemberUpdateHandler:
 		ok = true
+	case GuildMemberUpdateErrHandler:
+		ok = true
 	case chan *GuildMemberUpdate:
 		ok = true
 	case GuildMembersChunkHandler:
 		ok = true
+	case GuildMembersChunkErrHandler:
+		ok = true
 	case chan *GuildMembersChunk:
 		ok = true
 	case GuildRoleCreateHandler:
 		ok = true
+	case GuildRoleCreateErrHandler:
+		ok = true
 	case chan *GuildRoleCreate:
 		ok = true
 	case GuildRoleDeleteHandler:
 		ok = true
+	case GuildRoleDeleteErrHandler:
+		ok = true
 	case chan *GuildRoleDelete:
 		ok = true
 	case GuildRoleUpdateHandler:
 		ok = true
+	case GuildRoleUpdateErrHandler:
+		ok = true
 	case chan *GuildRoleUpdate:
 		ok = true
 	case GuildUpdateHandler:
 		ok = true
+	case GuildUpdateErrHandler:
+		ok = true
 	case chan *GuildUpdate:
 		ok = true
 	case MessageCreateHandler:
 		ok = true
+	case MessageCreateErrHandler:
+		ok = true
 	case chan *MessageCreate:
 		ok = true
 	case MessageDeleteHandler:
 		ok = true
+	case MessageDeleteErrHandler:
+		ok = true
 	case chan *MessageDelete:
 		ok = true
 	case MessageDeleteBulkHandler:
 		ok = true
+	case MessageDeleteBulkErrHandler:
+		ok = true
 	case chan *MessageDeleteBulk:
 		ok = true
 	case MessageReactionAddHandler:
 		ok = true
+	case MessageReactionAddErrHandler:
+		ok = true
 	case chan *MessageReactionAdd:
 		ok = true
 	case MessageReactionRemoveHandler:
 		ok = true
+	case MessageReactionRemoveErrHandler:
+		ok = true
 	case chan *MessageReactionRemove:
 		ok = true
 	case MessageReactionRemoveAllHandler:
 		ok = true
+	case MessageReactionRemoveAllErrHandler:
+		ok = true
 	case chan *MessageReactionRemoveAll:
 		ok = true
 	case MessageUpdateHandler:
 		ok = true
+	case MessageUpdateErrHandler:
+		ok = true
 	case chan *MessageUpdate:
 		ok = true
 	case PresenceUpdateHandler:
 		ok = true
+	case PresenceUpdateErrHandler:
+		ok = true
 	case chan *PresenceUpdate:
 		ok = true
 	case ReadyHandler:
 		ok = true
+	case ReadyErrHandler:
+		ok = true
 	case chan *Ready:
 		ok = true
 	case ResumedHandler:
 		ok = true
+	case ResumedErrHandler:
+		ok = true
 	case chan *Resumed:
 		ok = true
 	case TypingStartHandler:
 		ok = true
+	case TypingStartErrHandler:
+		ok = true
 	case chan *TypingStart:
 		ok = true
 	case UserUpdateHandler:
 		ok = true
+	case UserUpdateErrHandler:
+		ok = true
 	case chan *UserUpdate:
 		ok = true
 	case VoiceServerUpdateHandler:
 		ok = true
+	case VoiceServerUpdateErrHandler:
+		ok = true
 	case chan *VoiceServerUpdate:
 		ok = true
 	case VoiceStateUpdateHandler:
 		ok = true
+	case VoiceStateUpdateErrHandler:
+		ok = true
 	case chan *VoiceStateUpdate:
 		ok = true
 	case WebhooksUpdateHandler:
 		ok = true
+	case WebhooksUpdateErrHandler:
+		ok = true
 	case chan *WebhooksUpdate:
 		ok = true
 	}
@@ -231,6 +307,8 @@ func closeChannel(channel interface{}) {
 	switch t := channel.(type) {
 	case chan interface{}:
 		close(t)
+	case chan *RawEvent:
+		close(t)
 	case chan *ChannelCreate:
 		close(t)
 	case chan *ChannelDelete:
@@ -317,215 +395,295 @@ func newDispatcher() *dispatcher {
 	return d
 }
 
-func (d *dispatcher) trigger(h Handler, evt resource) {
+// trigger executes the handler. Only the error handlers can return an error.
+func (d *dispatcher) trigger(h Handler, evt resource) error {
 	switch t := h.(type) {
 	case SimpleHandler:
 		t(d.session)
 	case SimplestHandler:
 		t()
+	case SimpleErrHandler:
+		return t(d.session)
+	case SimplestErrHandler:
+		return t()
+	case RawEventHandler:
+		t(d.session, evt.(*RawEvent))
+	case RawEventErrHandler:
+		return t(d.session, evt.(*RawEvent))
+	case chan *RawEvent:
+		t <- evt.(*RawEvent)
+	case chan<- *RawEvent:
+		t <- evt.(*RawEvent)
 	case chan interface{}:
 		t <- evt
 	case chan<- interface{}:
 		t <- evt
 	case ChannelCreateHandler:
 		t(d.session, evt.(*ChannelCreate))
+	case ChannelCreateErrHandler:
+		return t(d.session, evt.(*ChannelCreate))
 	case chan *ChannelCreate:
 		t <- evt.(*ChannelCreate)
 	case chan<- *ChannelCreate:
 		t <- evt.(*ChannelCreate)
 	case ChannelDeleteHandler:
 		t(d.session, evt.(*ChannelDelete))
+	case ChannelDeleteErrHandler:
+		return t(d.session, evt.(*ChannelDelete))
 	case chan *ChannelDelete:
 		t <- evt.(*ChannelDelete)
 	case chan<- *ChannelDelete:
 		t <- evt.(*ChannelDelete)
 	case ChannelPinsUpdateHandler:
 		t(d.session, evt.(*ChannelPinsUpdate))
+	case ChannelPinsUpdateErrHandler:
+		return t(d.session, evt.(*ChannelPinsUpdate))
 	case chan *ChannelPinsUpdate:
 		t <- evt.(*ChannelPinsUpdate)
 	case chan<- *ChannelPinsUpdate:
 		t <- evt.(*ChannelPinsUpdate)
 	case ChannelUpdateHandler:
 		t(d.session, evt.(*ChannelUpdate))
+	case ChannelUpdateErrHandler:
+		return t(d.session, evt.(*ChannelUpdate))
 	case chan *ChannelUpdate:
 		t <- evt.(*ChannelUpdate)
 	case chan<- *ChannelUpdate:
 		t <- evt.(*ChannelUpdate)
 	case GuildBanAddHandler:
 		t(d.session, evt.(*GuildBanAdd))
+	case GuildBanAddErrHandler:
+		return t(d.session, evt.(*GuildBanAdd))
 	case chan *GuildBanAdd:
 		t <- evt.(*GuildBanAdd)
 	case chan<- *GuildBanAdd:
 		t <- evt.(*GuildBanAdd)
 	case GuildBanRemoveHandler:
 		t(d.session, evt.(*GuildBanRemove))
+	case GuildBanRemoveErrHandler:
+		return t(d.session, evt.(*GuildBanRemove))
 	case chan *GuildBanRemove:
 		t <- evt.(*GuildBanRemove)
 	case chan<- *GuildBanRemove:
 		t <- evt.(*GuildBanRemove)
 	case GuildCreateHandler:
 		t(d.session, evt.(*GuildCreate))
+	case GuildCreateErrHandler:
+		return t(d.session, evt.(*GuildCreate))
 	case chan *GuildCreate:
 		t <- evt.(*GuildCreate)
 	case chan<- *GuildCreate:
 		t <- evt.(*GuildCreate)
 	case GuildDeleteHandler:
 		t(d.session, evt.(*GuildDelete))
+	case GuildDeleteErrHandler:
+		return t(d.session, evt.(*GuildDelete))
 	case chan *GuildDelete:
 		t <- evt.(*GuildDelete)
 	case chan<- *GuildDelete:
 		t <- evt.(*GuildDelete)
 	case GuildEmojisUpdateHandler:
 		t(d.session, evt.(*GuildEmojisUpdate))
+	case GuildEmojisUpdateErrHandler:
+		return t(d.session, evt.(*GuildEmojisUpdate))
 	case chan *GuildEmojisUpdate:
 		t <- evt.(*GuildEmojisUpdate)
 	case chan<- *GuildEmojisUpdate:
 		t <- evt.(*GuildEmojisUpdate)
 	case GuildIntegrationsUpdateHandler:
 		t(d.session, evt.(*GuildIntegrationsUpdate))
+	case GuildIntegrationsUpdateErrHandler:
+		return t(d.session, evt.(*GuildIntegrationsUpdate))
 	case chan *GuildIntegrationsUpdate:
 		t <- evt.(*GuildIntegrationsUpdate)
 	case chan<- *GuildIntegrationsUpdate:
 		t <- evt.(*GuildIntegrationsUpdate)
 	case GuildMemberAddHandler:
 		t(d.session, evt.(*GuildMemberAdd))
+	case GuildMemberAddErrHandler:
+		return t(d.session, evt.(*GuildMemberAdd))
 	case chan *GuildMemberAdd:
 		t <- evt.(*GuildMemberAdd)
 	case chan<- *GuildMemberAdd:
 		t <- evt.(*GuildMemberAdd)
 	case GuildMemberRemoveHandler:
 		t(d.session, evt.(*GuildMemberRemove))
+	case GuildMemberRemoveErrHandler:
+		return t(d.session, evt.(*GuildMemberRemove))
 	case chan *GuildMemberRemove:
 		t <- evt.(*GuildMemberRemove)
 	case chan<- *GuildMemberRemove:
 		t <- evt.(*GuildMemberRemove)
 	case GuildMemberUpdateHandler:
 		t(d.session, evt.(*GuildMemberUpdate))
+	case GuildMemberUpdateErrHandler:
+		return t(d.session, evt.(*GuildMemberUpdate))
 	case chan *GuildMemberUpdate:
 		t <- evt.(*GuildMemberUpdate)
 	case chan<- *GuildMemberUpdate:
 		t <- evt.(*GuildMemberUpdate)
 	case GuildMembersChunkHandler:
 		t(d.session, evt.(*GuildMembersChunk))
+	case GuildMembersChunkErrHandler:
+		return t(d.session, evt.(*GuildMembersChunk))
 	case chan *GuildMembersChunk:
 		t <- evt.(*GuildMembersChunk)
 	case chan<- *GuildMembersChunk:
 		t <- evt.(*GuildMembersChunk)
 	case GuildRoleCreateHandler:
 		t(d.session, evt.(*GuildRoleCreate))
+	case GuildRoleCreateErrHandler:
+		return t(d.session, evt.(*GuildRoleCreate))
 	case chan *GuildRoleCreate:
 		t <- evt.(*GuildRoleCreate)
 	case chan<- *GuildRoleCreate:
 		t <- evt.(*GuildRoleCreate)
 	case GuildRoleDeleteHandler:
 		t(d.session, evt.(*GuildRoleDelete))
+	case GuildRoleDeleteErrHandler:
+		return t(d.session, evt.(*GuildRoleDelete))
 	case chan *GuildRoleDelete:
 		t <- evt.(*GuildRoleDelete)
 	case chan<- *GuildRoleDelete:
 		t <- evt.(*GuildRoleDelete)
 	case GuildRoleUpdateHandler:
 		t(d.session, evt.(*GuildRoleUpdate))
+	case GuildRoleUpdateErrHandler:
+		return t(d.session, evt.(*GuildRoleUpdate))
 	case chan *GuildRoleUpdate:
 		t <- evt.(*GuildRoleUpdate)
 	case chan<- *GuildRoleUpdate:
 		t <- evt.(*GuildRoleUpdate)
 	case GuildUpdateHandler:
 		t(d.session, evt.(*GuildUpdate))
+	case GuildUpdateErrHandler:
+		return t(d.session, evt.(*GuildUpdate))
 	case chan *GuildUpdate:
 		t <- evt.(*GuildUpdate)
 	case chan<- *GuildUpdate:
 		t <- evt.(*GuildUpdate)
 	case MessageCreateHandler:
 		t(d.session, evt.(*MessageCreate))
+	case MessageCreateErrHandler:
+		return t(d.session, evt.(*MessageCreate))
 	case chan *MessageCreate:
 		t <- evt.(*MessageCreate)
 	case chan<- *MessageCreate:
 		t <- evt.(*MessageCreate)
 	case MessageDeleteHandler:
 		t(d.session, evt.(*MessageDelete))
+	case MessageDeleteErrHandler:
+		return t(d.session, evt.(*MessageDelete))
 	case chan *MessageDelete:
 		t <- evt.(*MessageDelete)
 	case chan<- *MessageDelete:
 		t <- evt.(*MessageDelete)
 	case MessageDeleteBulkHandler:
 		t(d.session, evt.(*MessageDeleteBulk))
+	case MessageDeleteBulkErrHandler:
+		return t(d.session, evt.(*MessageDeleteBulk))
 	case chan *MessageDeleteBulk:
 		t <- evt.(*MessageDeleteBulk)
 	case chan<- *MessageDeleteBulk:
 		t <- evt.(*MessageDeleteBulk)
 	case MessageReactionAddHandler:
 		t(d.session, evt.(*MessageReactionAdd))
+	case MessageReactionAddErrHandler:
+		return t(d.session, evt.(*MessageReactionAdd))
 	case chan *MessageReactionAdd:
 		t <- evt.(*MessageReactionAdd)
 	case chan<- *MessageReactionAdd:
 		t <- evt.(*MessageReactionAdd)
 	case MessageReactionRemoveHandler:
 		t(d.session, evt.(*MessageReactionRemove))
+	case MessageReactionRemoveErrHandler:
+		return t(d.session, evt.(*MessageReactionRemove))
 	case chan *MessageReactionRemove:
 		t <- evt.(*MessageReactionRemove)
 	case chan<- *MessageReactionRemove:
 		t <- evt.(*MessageReactionRemove)
 	case MessageReactionRemoveAllHandler:
 		t(d.session, evt.(*MessageReactionRemoveAll))
+	case MessageReactionRemoveAllErrHandler:
+		return t(d.session, evt.(*MessageReactionRemoveAll))
 	case chan *MessageReactionRemoveAll:
 		t <- evt.(*MessageReactionRemoveAll)
 	case chan<- *MessageReactionRemoveAll:
 		t <- evt.(*MessageReactionRemoveAll)
 	case MessageUpdateHandler:
 		t(d.session, evt.(*MessageUpdate))
+	case MessageUpdateErrHandler:
+		return t(d.session, evt.(*MessageUpdate))
 	case chan *MessageUpdate:
 		t <- evt.(*MessageUpdate)
 	case chan<- *MessageUpdate:
 		t <- evt.(*MessageUpdate)
 	case PresenceUpdateHandler:
 		t(d.session, evt.(*PresenceUpdate))
+	case PresenceUpdateErrHandler:
+		return t(d.session, evt.(*PresenceUpdate))
 	case chan *PresenceUpdate:
 		t <- evt.(*PresenceUpdate)
 	case chan<- *PresenceUpdate:
 		t <- evt.(*PresenceUpdate)
 	case ReadyHandler:
 		t(d.session, evt.(*Ready))
+	case ReadyErrHandler:
+		return t(d.session, evt.(*Ready))
 	case chan *Ready:
 		t <- evt.(*Ready)
 	case chan<- *Ready:
 		t <- evt.(*Ready)
 	case ResumedHandler:
 		t(d.session, evt.(*Resumed))
+	case ResumedErrHandler:
+		return t(d.session, evt.(*Resumed))
 	case chan *Resumed:
 		t <- evt.(*Resumed)
 	case chan<- *Resumed:
 		t <- evt.(*Resumed)
 	case TypingStartHandler:
 		t(d.session, evt.(*TypingStart))
+	case TypingStartErrHandler:
+		return t(d.session, evt.(*TypingStart))
 	case chan *TypingStart:
 		t <- evt.(*TypingStart)
 	case chan<- *TypingStart:
 		t <- evt.(*TypingStart)
 	case UserUpdateHandler:
 		t(d.session, evt.(*UserUpdate))
+	case UserUpdateErrHandler:
+		return t(d.session, evt.(*UserUpdate))
 	case chan *UserUpdate:
 		t <- evt.(*UserUpdate)
 	case chan<- *UserUpdate:
 		t <- evt.(*UserUpdate)
 	case VoiceServerUpdateHandler:
 		t(d.session, evt.(*VoiceServerUpdate))
+	case VoiceServerUpdateErrHandler:
+		return t(d.session, evt.(*VoiceServerUpdate))
 	case chan *VoiceServerUpdate:
 		t <- evt.(*VoiceServerUpdate)
 	case chan<- *VoiceServerUpdate:
 		t <- evt.(*VoiceServerUpdate)
 	case VoiceStateUpdateHandler:
 		t(d.session, evt.(*VoiceStateUpdate))
+	case VoiceStateUpdateErrHandler:
+		return t(d.session, evt.(*VoiceStateUpdate))
 	case chan *VoiceStateUpdate:
 		t <- evt.(*VoiceStateUpdate)
 	case chan<- *VoiceStateUpdate:
 		t <- evt.(*VoiceStateUpdate)
 	case WebhooksUpdateHandler:
 		t(d.session, evt.(*WebhooksUpdate))
+	case WebhooksUpdateErrHandler:
+		return t(d.session, evt.(*WebhooksUpdate))
 	case chan *WebhooksUpdate:
 		t <- evt.(*WebhooksUpdate)
 	case chan<- *WebhooksUpdate:
 		t <- evt.(*WebhooksUpdate)
 	}
+	return nil
 }
 
 //////////////////////////////////////////////////////
@@ -537,98 +695,203 @@ func (d *dispatcher) trigger(h Handler, evt resource) {
 // ChannelCreateHandler is triggered in ChannelCreate events
 type ChannelCreateHandler = func(s Session, h *ChannelCreate)
 
+// ChannelCreateErrHandler is triggered in ChannelCreate events. A returned error is passed to DispatchConfig.OnHandlerError
+type ChannelCreateErrHandler = func(s Session, h *ChannelCreate) error
+
 // ChannelDeleteHandler is triggered in ChannelDelete events
 type ChannelDeleteHandler = func(s Session, h *ChannelDelete)
 
+// ChannelDeleteErrHandler is triggered in ChannelDelete events. A returned error is passed to DispatchConfig.OnHandlerError
+type ChannelDeleteErrHandler = func(s Session, h *ChannelDelete) error
+
 // ChannelPinsUpdateHandler is triggered in ChannelPinsUpdate events
 type ChannelPinsUpdateHandler = func(s Session, h *ChannelPinsUpdate)
 
+// ChannelPinsUpdateErrHandler is triggered in ChannelPinsUpdate events. A returned error is passed to DispatchConfig.OnHandlerError
+type ChannelPinsUpdateErrHandler = func(s Session, h *ChannelPinsUpdate) error
+
 // ChannelUpdateHandler is triggered in ChannelUpdate events
 type ChannelUpdateHandler = func(s Session, h *ChannelUpdate)
 
+// ChannelUpdateErrHandler is triggered in ChannelUpdate events. A returned error is passed to DispatchConfig.OnHandlerError
+type ChannelUpdateErrHandler = func(s Session, h *ChannelUpdate) error
+
 // GuildBanAddHandler is triggered in GuildBanAdd events
 type GuildBanAddHandler = func(s Session, h *GuildBanAdd)
 
+// GuildBanAddErrHandler is triggered in GuildBanAdd events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildBanAddErrHandler = func(s Session, h *GuildBanAdd) error
+
 // GuildBanRemoveHandler is triggered in GuildBanRemove events
 type GuildBanRemoveHandler = func(s Session, h *GuildBanRemove)
 
+// GuildBanRemoveErrHandler is triggered in GuildBanRemove events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildBanRemoveErrHandler = func(s Session, h *GuildBanRemove) error
+
 // GuildCreateHandler is triggered in GuildCreate events
 type GuildCreateHandler = func(s Session, h *GuildCreate)
 
+// GuildCreateErrHandler is triggered in GuildCreate events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildCreateErrHandler = func(s Session, h *GuildCreate) error
+
 // GuildDeleteHandler is triggered in GuildDelete events
 type GuildDeleteHandler = func(s Session, h *GuildDelete)
 
+// GuildDeleteErrHandler is triggered in GuildDelete events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildDeleteErrHandler = func(s Session, h *GuildDelete) error
+
 // GuildEmojisUpdateHandler is triggered in GuildEmojisUpdate events
 type GuildEmojisUpdateHandler = func(s Session, h *GuildEmojisUpdate)
 
+// GuildEmojisUpdateErrHandler is triggered in GuildEmojisUpdate events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildEmojisUpdateErrHandler = func(s Session, h *GuildEmojisUpdate) error
+
 // GuildIntegrationsUpdateHandler is triggered in GuildIntegrationsUpdate events
 type GuildIntegrationsUpdateHandler = func(s Session, h *GuildIntegrationsUpdate)
 
+// GuildIntegrationsUpdateErrHandler is triggered in GuildIntegrationsUpdate events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildIntegrationsUpdateErrHandler = func(s Session, h *GuildIntegrationsUpdate) error
+
 // GuildMemberAddHandler is triggered in GuildMemberAdd events
 type GuildMemberAddHandler = func(s Session, h *GuildMemberAdd)
 
+// GuildMemberAddErrHandler is triggered in GuildMemberAdd events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildMemberAddErrHandler = func(s Session, h *GuildMemberAdd) error
+
 // GuildMemberRemoveHandler is triggered in GuildMemberRemove events
 type GuildMemberRemoveHandler = func(s Session, h *GuildMemberRemove)
 
+// GuildMemberRemoveErrHandler is triggered in GuildMemberRemove events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildMemberRemoveErrHandler = func(s Session, h *GuildMemberRemove) error
+
 // GuildMemberUpdateHandler is triggered in GuildMemberUpdate events
 type GuildMemberUpdateHandler = func(s Session, h *GuildMemberUpdate)
 
+// GuildMemberUpdateErrHandler is triggered in GuildMemberUpdate events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildMemberUpdateErrHandler = func(s Session, h *GuildMemberUpdate) error
+
 // GuildMembersChunkHandler is triggered in GuildMembersChunk events
 type GuildMembersChunkHandler = func(s Session, h *GuildMembersChunk)
 
+// GuildMembersChunkErrHandler is triggered in GuildMembersChunk events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildMembersChunkErrHandler = func(s Session, h *GuildMembersChunk) error
+
 // GuildRoleCreateHandler is triggered in GuildRoleCreate events
 type GuildRoleCreateHandler = func(s Session, h *GuildRoleCreate)
 
+// GuildRoleCreateErrHandler is triggered in GuildRoleCreate events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildRoleCreateErrHandler = func(s Session, h *GuildRoleCreate) error
+
 // GuildRoleDeleteHandler is triggered in GuildRoleDelete events
 type GuildRoleDeleteHandler = func(s Session, h *GuildRoleDelete)
 
+// GuildRoleDeleteErrHandler is triggered in GuildRoleDelete events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildRoleDeleteErrHandler = func(s Session, h *GuildRoleDelete) error
+
 // GuildRoleUpdateHandler is triggered in GuildRoleUpdate events
 type GuildRoleUpdateHandler = func(s Session, h *GuildRoleUpdate)
 
+// GuildRoleUpdateErrHandler is triggered in GuildRoleUpdate events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildRoleUpdateErrHandler = func(s Session, h *GuildRoleUpdate) error
+
 // GuildUpdateHandler is triggered in GuildUpdate events
 type GuildUpdateHandler = func(s Session, h *GuildUpdate)
 
+// GuildUpdateErrHandler is triggered in GuildUpdate events. A returned error is passed to DispatchConfig.OnHandlerError
+type GuildUpdateErrHandler = func(s Session, h *GuildUpdate) error
+
 // MessageCreateHandler is triggered in MessageCreate events
 type MessageCreateHandler = func(s Session, h *MessageCreate)
 
+// MessageCreateErrHandler is triggered in MessageCreate events. A returned error is passed to DispatchConfig.OnHandlerError
+type MessageCreateErrHandler = func(s Session, h *MessageCreate) error
+
 // MessageDeleteHandler is triggered in MessageDelete events
 type MessageDeleteHandler = func(s Session, h *MessageDelete)
 
+// MessageDeleteErrHandler is triggered in MessageDelete events. A returned error is passed to DispatchConfig.OnHandlerError
+type MessageDeleteErrHandler = func(s Session, h *MessageDelete) error
+
 // MessageDeleteBulkHandler is triggered in MessageDeleteBulk events
 type MessageDeleteBulkHandler = func(s Session, h *MessageDeleteBulk)
 
+// MessageDeleteBulkErrHandler is triggered in MessageDeleteBulk events. A returned error is passed to DispatchConfig.OnHandlerError
+type MessageDeleteBulkErrHandler = func(s Session, h *MessageDeleteBulk) error
+
 // MessageReactionAddHandler is triggered in MessageReactionAdd events
 type MessageReactionAddHandler = func(s Session, h *MessageReactionAdd)
 
+// MessageReactionAddErrHandler is triggered in MessageReactionAdd events. A returned error is passed to DispatchConfig.OnHandlerError
+type MessageReactionAddErrHandler = func(s Session, h *MessageReactionAdd) error
+
 // MessageReactionRemoveHandler is triggered in MessageReactionRemove events
 type MessageReactionRemoveHandler = func(s Session, h *MessageReactionRemove)
 
+// MessageReactionRemoveErrHandler is triggered in MessageReactionRemove events. A returned error is passed to DispatchConfig.OnHandlerError
+type MessageReactionRemoveErrHandler = func(s Session, h *MessageReactionRemove) error
+
 // MessageReactionRemoveAllHandler is triggered in MessageReactionRemoveAll events
 type MessageReactionRemoveAllHandler = func(s Session, h *MessageReactionRemoveAll)
 
+// MessageReactionRemoveAllErrHandler is triggered in MessageReactionRemoveAll events. A returned error is passed to DispatchConfig.OnHandlerError
+type MessageReactionRemoveAllErrHandler = func(s Session, h *MessageReactionRemoveAll) error
+
 // MessageUpdateHandler is triggered in MessageUpdate events
 type MessageUpdateHandler = func(s Session, h *MessageUpdate)
 
+// MessageUpdateErrHandler is triggered in MessageUpdate events. A returned error is passed to DispatchConfig.OnHandlerError
+type MessageUpdateErrHandler = func(s Session, h *MessageUpdate) error
+
 // PresenceUpdateHandler is triggered in PresenceUpdate events
 type PresenceUpdateHandler = func(s Session, h *PresenceUpdate)
 
+// PresenceUpdateErrHandler is triggered in PresenceUpdate events. A returned error is passed to DispatchConfig.OnHandlerError
+type PresenceUpdateErrHandler = func(s Session, h *PresenceUpdate) error
+
+// RawEventHandler is triggered in RawEvent events
+type RawEventHandler = func(s Session, h *RawEvent)
+
+// RawEventErrHandler is triggered in RawEvent events. A returned error is passed to DispatchConfig.OnHandlerError
+type RawEventErrHandler = func(s Session, h *RawEvent) error
+
 // ReadyHandler is triggered in Ready events
 type ReadyHandler = func(s Session, h *Ready)
 
+// ReadyErrHandler is triggered in Ready events. A returned error is passed to DispatchConfig.OnHandlerError
+type ReadyErrHandler = func(s Session, h *Ready) error
+
 // ResumedHandler is triggered in Resumed events
 type ResumedHandler = func(s Session, h *Resumed)
 
+// ResumedErrHandler is triggered in Resumed events. A returned error is passed to DispatchConfig.OnHandlerError
+type ResumedErrHandler = func(s Session, h *Resumed) error
+
 // TypingStartHandler is triggered in TypingStart events
 type TypingStartHandler = func(s Session, h *TypingStart)
 
+// TypingStartErrHandler is triggered in TypingStart events. A returned error is passed to DispatchConfig.OnHandlerError
+type TypingStartErrHandler = func(s Session, h *TypingStart) error
+
 // UserUpdateHandler is triggered in UserUpdate events
 type UserUpdateHandler = func(s Session, h *UserUpdate)
 
+// UserUpdateErrHandler is triggered in UserUpdate events. A returned error is passed to DispatchConfig.OnHandlerError
+type UserUpdateErrHandler = func(s Session, h *UserUpdate) error
+
 // VoiceServerUpdateHandler is triggered in VoiceServerUpdate events
 type VoiceServerUpdateHandler = func(s Session, h *VoiceServerUpdate)
 
+// VoiceServerUpdateErrHandler is triggered in VoiceServerUpdate events. A returned error is passed to DispatchConfig.OnHandlerError
+type VoiceServerUpdateErrHandler = func(s Session, h *VoiceServerUpdate) error
+
 // VoiceStateUpdateHandler is triggered in VoiceStateUpdate events
 type VoiceStateUpdateHandler = func(s Session, h *VoiceStateUpdate)
 
+// VoiceStateUpdateErrHandler is triggered in VoiceStateUpdate events. A returned error is passed to DispatchConfig.OnHandlerError
+type VoiceStateUpdateErrHandler = func(s Session, h *VoiceStateUpdate) error
+
 // WebhooksUpdateHandler is triggered in WebhooksUpdate events
 type WebhooksUpdateHandler = func(s Session, h *WebhooksUpdate)
+
+// WebhooksUpdateErrHandler is triggered in WebhooksUpdate events. A returned error is passed to DispatchConfig.OnHandlerError
+type WebhooksUpdateErrHandler = func(s Session, h *WebhooksUpdate) error
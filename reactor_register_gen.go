@@ -0,0 +1,1264 @@
+package disgord
+
+// Code generated - This file has been automatically generated by generate/events/main.go - DO NOT EDIT.
+// Warning: This file is overwritten at "go generate", instead adapt generate/events/register.gotpl and run `go generate`
+
+//////////////////////////////////////////////////////
+//
+// Typed handler registration
+//
+//////////////////////////////////////////////////////
+
+// ChannelCreateMiddleware filters or modifies ChannelCreate events before they reach the handler. Return nil to stop the event.
+type ChannelCreateMiddleware func(evt *ChannelCreate) *ChannelCreate
+
+func (ChannelCreateMiddleware) channelCreateHandlerOption() {}
+
+// ChannelCreateHandlerOption configures a handler registered by Client.OnChannelCreate. See ChannelCreateMiddleware and WithCtrl.
+type ChannelCreateHandlerOption interface {
+	channelCreateHandlerOption()
+}
+
+func (CtrlOption) channelCreateHandlerOption() {}
+
+// OnChannelCreate registers a handler for ChannelCreate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnChannelCreate(handler ChannelCreateHandler, opts ...ChannelCreateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case ChannelCreateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*ChannelCreate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtChannelCreate, inputs)
+}
+
+// ChannelDeleteMiddleware filters or modifies ChannelDelete events before they reach the handler. Return nil to stop the event.
+type ChannelDeleteMiddleware func(evt *ChannelDelete) *ChannelDelete
+
+func (ChannelDeleteMiddleware) channelDeleteHandlerOption() {}
+
+// ChannelDeleteHandlerOption configures a handler registered by Client.OnChannelDelete. See ChannelDeleteMiddleware and WithCtrl.
+type ChannelDeleteHandlerOption interface {
+	channelDeleteHandlerOption()
+}
+
+func (CtrlOption) channelDeleteHandlerOption() {}
+
+// OnChannelDelete registers a handler for ChannelDelete events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnChannelDelete(handler ChannelDeleteHandler, opts ...ChannelDeleteHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case ChannelDeleteMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*ChannelDelete)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtChannelDelete, inputs)
+}
+
+// ChannelPinsUpdateMiddleware filters or modifies ChannelPinsUpdate events before they reach the handler. Return nil to stop the event.
+type ChannelPinsUpdateMiddleware func(evt *ChannelPinsUpdate) *ChannelPinsUpdate
+
+func (ChannelPinsUpdateMiddleware) channelPinsUpdateHandlerOption() {}
+
+// ChannelPinsUpdateHandlerOption configures a handler registered by Client.OnChannelPinsUpdate. See ChannelPinsUpdateMiddleware and WithCtrl.
+type ChannelPinsUpdateHandlerOption interface {
+	channelPinsUpdateHandlerOption()
+}
+
+func (CtrlOption) channelPinsUpdateHandlerOption() {}
+
+// OnChannelPinsUpdate registers a handler for ChannelPinsUpdate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnChannelPinsUpdate(handler ChannelPinsUpdateHandler, opts ...ChannelPinsUpdateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case ChannelPinsUpdateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*ChannelPinsUpdate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtChannelPinsUpdate, inputs)
+}
+
+// ChannelUpdateMiddleware filters or modifies ChannelUpdate events before they reach the handler. Return nil to stop the event.
+type ChannelUpdateMiddleware func(evt *ChannelUpdate) *ChannelUpdate
+
+func (ChannelUpdateMiddleware) channelUpdateHandlerOption() {}
+
+// ChannelUpdateHandlerOption configures a handler registered by Client.OnChannelUpdate. See ChannelUpdateMiddleware and WithCtrl.
+type ChannelUpdateHandlerOption interface {
+	channelUpdateHandlerOption()
+}
+
+func (CtrlOption) channelUpdateHandlerOption() {}
+
+// OnChannelUpdate registers a handler for ChannelUpdate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnChannelUpdate(handler ChannelUpdateHandler, opts ...ChannelUpdateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case ChannelUpdateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*ChannelUpdate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtChannelUpdate, inputs)
+}
+
+// GuildBanAddMiddleware filters or modifies GuildBanAdd events before they reach the handler. Return nil to stop the event.
+type GuildBanAddMiddleware func(evt *GuildBanAdd) *GuildBanAdd
+
+func (GuildBanAddMiddleware) guildBanAddHandlerOption() {}
+
+// GuildBanAddHandlerOption configures a handler registered by Client.OnGuildBanAdd. See GuildBanAddMiddleware and WithCtrl.
+type GuildBanAddHandlerOption interface {
+	guildBanAddHandlerOption()
+}
+
+func (CtrlOption) guildBanAddHandlerOption() {}
+
+// OnGuildBanAdd registers a handler for GuildBanAdd events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildBanAdd(handler GuildBanAddHandler, opts ...GuildBanAddHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildBanAddMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildBanAdd)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildBanAdd, inputs)
+}
+
+// GuildBanRemoveMiddleware filters or modifies GuildBanRemove events before they reach the handler. Return nil to stop the event.
+type GuildBanRemoveMiddleware func(evt *GuildBanRemove) *GuildBanRemove
+
+func (GuildBanRemoveMiddleware) guildBanRemoveHandlerOption() {}
+
+// GuildBanRemoveHandlerOption configures a handler registered by Client.OnGuildBanRemove. See GuildBanRemoveMiddleware and WithCtrl.
+type GuildBanRemoveHandlerOption interface {
+	guildBanRemoveHandlerOption()
+}
+
+func (CtrlOption) guildBanRemoveHandlerOption() {}
+
+// OnGuildBanRemove registers a handler for GuildBanRemove events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildBanRemove(handler GuildBanRemoveHandler, opts ...GuildBanRemoveHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildBanRemoveMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildBanRemove)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildBanRemove, inputs)
+}
+
+// GuildCreateMiddleware filters or modifies GuildCreate events before they reach the handler. Return nil to stop the event.
+type GuildCreateMiddleware func(evt *GuildCreate) *GuildCreate
+
+func (GuildCreateMiddleware) guildCreateHandlerOption() {}
+
+// GuildCreateHandlerOption configures a handler registered by Client.OnGuildCreate. See GuildCreateMiddleware and WithCtrl.
+type GuildCreateHandlerOption interface {
+	guildCreateHandlerOption()
+}
+
+func (CtrlOption) guildCreateHandlerOption() {}
+
+// OnGuildCreate registers a handler for GuildCreate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildCreate(handler GuildCreateHandler, opts ...GuildCreateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildCreateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildCreate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildCreate, inputs)
+}
+
+// GuildDeleteMiddleware filters or modifies GuildDelete events before they reach the handler. Return nil to stop the event.
+type GuildDeleteMiddleware func(evt *GuildDelete) *GuildDelete
+
+func (GuildDeleteMiddleware) guildDeleteHandlerOption() {}
+
+// GuildDeleteHandlerOption configures a handler registered by Client.OnGuildDelete. See GuildDeleteMiddleware and WithCtrl.
+type GuildDeleteHandlerOption interface {
+	guildDeleteHandlerOption()
+}
+
+func (CtrlOption) guildDeleteHandlerOption() {}
+
+// OnGuildDelete registers a handler for GuildDelete events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildDelete(handler GuildDeleteHandler, opts ...GuildDeleteHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildDeleteMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildDelete)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildDelete, inputs)
+}
+
+// GuildEmojisUpdateMiddleware filters or modifies GuildEmojisUpdate events before they reach the handler. Return nil to stop the event.
+type GuildEmojisUpdateMiddleware func(evt *GuildEmojisUpdate) *GuildEmojisUpdate
+
+func (GuildEmojisUpdateMiddleware) guildEmojisUpdateHandlerOption() {}
+
+// GuildEmojisUpdateHandlerOption configures a handler registered by Client.OnGuildEmojisUpdate. See GuildEmojisUpdateMiddleware and WithCtrl.
+type GuildEmojisUpdateHandlerOption interface {
+	guildEmojisUpdateHandlerOption()
+}
+
+func (CtrlOption) guildEmojisUpdateHandlerOption() {}
+
+// OnGuildEmojisUpdate registers a handler for GuildEmojisUpdate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildEmojisUpdate(handler GuildEmojisUpdateHandler, opts ...GuildEmojisUpdateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildEmojisUpdateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildEmojisUpdate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildEmojisUpdate, inputs)
+}
+
+// GuildIntegrationsUpdateMiddleware filters or modifies GuildIntegrationsUpdate events before they reach the handler. Return nil to stop the event.
+type GuildIntegrationsUpdateMiddleware func(evt *GuildIntegrationsUpdate) *GuildIntegrationsUpdate
+
+func (GuildIntegrationsUpdateMiddleware) guildIntegrationsUpdateHandlerOption() {}
+
+// GuildIntegrationsUpdateHandlerOption configures a handler registered by Client.OnGuildIntegrationsUpdate. See GuildIntegrationsUpdateMiddleware and WithCtrl.
+type GuildIntegrationsUpdateHandlerOption interface {
+	guildIntegrationsUpdateHandlerOption()
+}
+
+func (CtrlOption) guildIntegrationsUpdateHandlerOption() {}
+
+// OnGuildIntegrationsUpdate registers a handler for GuildIntegrationsUpdate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildIntegrationsUpdate(handler GuildIntegrationsUpdateHandler, opts ...GuildIntegrationsUpdateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildIntegrationsUpdateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildIntegrationsUpdate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildIntegrationsUpdate, inputs)
+}
+
+// GuildMemberAddMiddleware filters or modifies GuildMemberAdd events before they reach the handler. Return nil to stop the event.
+type GuildMemberAddMiddleware func(evt *GuildMemberAdd) *GuildMemberAdd
+
+func (GuildMemberAddMiddleware) guildMemberAddHandlerOption() {}
+
+// GuildMemberAddHandlerOption configures a handler registered by Client.OnGuildMemberAdd. See GuildMemberAddMiddleware and WithCtrl.
+type GuildMemberAddHandlerOption interface {
+	guildMemberAddHandlerOption()
+}
+
+func (CtrlOption) guildMemberAddHandlerOption() {}
+
+// OnGuildMemberAdd registers a handler for GuildMemberAdd events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildMemberAdd(handler GuildMemberAddHandler, opts ...GuildMemberAddHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildMemberAddMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildMemberAdd)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildMemberAdd, inputs)
+}
+
+// GuildMemberRemoveMiddleware filters or modifies GuildMemberRemove events before they reach the handler. Return nil to stop the event.
+type GuildMemberRemoveMiddleware func(evt *GuildMemberRemove) *GuildMemberRemove
+
+func (GuildMemberRemoveMiddleware) guildMemberRemoveHandlerOption() {}
+
+// GuildMemberRemoveHandlerOption configures a handler registered by Client.OnGuildMemberRemove. See GuildMemberRemoveMiddleware and WithCtrl.
+type GuildMemberRemoveHandlerOption interface {
+	guildMemberRemoveHandlerOption()
+}
+
+func (CtrlOption) guildMemberRemoveHandlerOption() {}
+
+// OnGuildMemberRemove registers a handler for GuildMemberRemove events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildMemberRemove(handler GuildMemberRemoveHandler, opts ...GuildMemberRemoveHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildMemberRemoveMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildMemberRemove)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildMemberRemove, inputs)
+}
+
+// GuildMemberUpdateMiddleware filters or modifies GuildMemberUpdate events before they reach the handler. Return nil to stop the event.
+type GuildMemberUpdateMiddleware func(evt *GuildMemberUpdate) *GuildMemberUpdate
+
+func (GuildMemberUpdateMiddleware) guildMemberUpdateHandlerOption() {}
+
+// GuildMemberUpdateHandlerOption configures a handler registered by Client.OnGuildMemberUpdate. See GuildMemberUpdateMiddleware and WithCtrl.
+type GuildMemberUpdateHandlerOption interface {
+	guildMemberUpdateHandlerOption()
+}
+
+func (CtrlOption) guildMemberUpdateHandlerOption() {}
+
+// OnGuildMemberUpdate registers a handler for GuildMemberUpdate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildMemberUpdate(handler GuildMemberUpdateHandler, opts ...GuildMemberUpdateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildMemberUpdateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildMemberUpdate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildMemberUpdate, inputs)
+}
+
+// GuildMembersChunkMiddleware filters or modifies GuildMembersChunk events before they reach the handler. Return nil to stop the event.
+type GuildMembersChunkMiddleware func(evt *GuildMembersChunk) *GuildMembersChunk
+
+func (GuildMembersChunkMiddleware) guildMembersChunkHandlerOption() {}
+
+// GuildMembersChunkHandlerOption configures a handler registered by Client.OnGuildMembersChunk. See GuildMembersChunkMiddleware and WithCtrl.
+type GuildMembersChunkHandlerOption interface {
+	guildMembersChunkHandlerOption()
+}
+
+func (CtrlOption) guildMembersChunkHandlerOption() {}
+
+// OnGuildMembersChunk registers a handler for GuildMembersChunk events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildMembersChunk(handler GuildMembersChunkHandler, opts ...GuildMembersChunkHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildMembersChunkMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildMembersChunk)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildMembersChunk, inputs)
+}
+
+// GuildRoleCreateMiddleware filters or modifies GuildRoleCreate events before they reach the handler. Return nil to stop the event.
+type GuildRoleCreateMiddleware func(evt *GuildRoleCreate) *GuildRoleCreate
+
+func (GuildRoleCreateMiddleware) guildRoleCreateHandlerOption() {}
+
+// GuildRoleCreateHandlerOption configures a handler registered by Client.OnGuildRoleCreate. See GuildRoleCreateMiddleware and WithCtrl.
+type GuildRoleCreateHandlerOption interface {
+	guildRoleCreateHandlerOption()
+}
+
+func (CtrlOption) guildRoleCreateHandlerOption() {}
+
+// OnGuildRoleCreate registers a handler for GuildRoleCreate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildRoleCreate(handler GuildRoleCreateHandler, opts ...GuildRoleCreateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildRoleCreateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildRoleCreate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildRoleCreate, inputs)
+}
+
+// GuildRoleDeleteMiddleware filters or modifies GuildRoleDelete events before they reach the handler. Return nil to stop the event.
+type GuildRoleDeleteMiddleware func(evt *GuildRoleDelete) *GuildRoleDelete
+
+func (GuildRoleDeleteMiddleware) guildRoleDeleteHandlerOption() {}
+
+// GuildRoleDeleteHandlerOption configures a handler registered by Client.OnGuildRoleDelete. See GuildRoleDeleteMiddleware and WithCtrl.
+type GuildRoleDeleteHandlerOption interface {
+	guildRoleDeleteHandlerOption()
+}
+
+func (CtrlOption) guildRoleDeleteHandlerOption() {}
+
+// OnGuildRoleDelete registers a handler for GuildRoleDelete events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildRoleDelete(handler GuildRoleDeleteHandler, opts ...GuildRoleDeleteHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildRoleDeleteMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildRoleDelete)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildRoleDelete, inputs)
+}
+
+// GuildRoleUpdateMiddleware filters or modifies GuildRoleUpdate events before they reach the handler. Return nil to stop the event.
+type GuildRoleUpdateMiddleware func(evt *GuildRoleUpdate) *GuildRoleUpdate
+
+func (GuildRoleUpdateMiddleware) guildRoleUpdateHandlerOption() {}
+
+// GuildRoleUpdateHandlerOption configures a handler registered by Client.OnGuildRoleUpdate. See GuildRoleUpdateMiddleware and WithCtrl.
+type GuildRoleUpdateHandlerOption interface {
+	guildRoleUpdateHandlerOption()
+}
+
+func (CtrlOption) guildRoleUpdateHandlerOption() {}
+
+// OnGuildRoleUpdate registers a handler for GuildRoleUpdate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildRoleUpdate(handler GuildRoleUpdateHandler, opts ...GuildRoleUpdateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildRoleUpdateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildRoleUpdate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildRoleUpdate, inputs)
+}
+
+// GuildUpdateMiddleware filters or modifies GuildUpdate events before they reach the handler. Return nil to stop the event.
+type GuildUpdateMiddleware func(evt *GuildUpdate) *GuildUpdate
+
+func (GuildUpdateMiddleware) guildUpdateHandlerOption() {}
+
+// GuildUpdateHandlerOption configures a handler registered by Client.OnGuildUpdate. See GuildUpdateMiddleware and WithCtrl.
+type GuildUpdateHandlerOption interface {
+	guildUpdateHandlerOption()
+}
+
+func (CtrlOption) guildUpdateHandlerOption() {}
+
+// OnGuildUpdate registers a handler for GuildUpdate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnGuildUpdate(handler GuildUpdateHandler, opts ...GuildUpdateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case GuildUpdateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*GuildUpdate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtGuildUpdate, inputs)
+}
+
+// MessageCreateMiddleware filters or modifies MessageCreate events before they reach the handler. Return nil to stop the event.
+type MessageCreateMiddleware func(evt *MessageCreate) *MessageCreate
+
+func (MessageCreateMiddleware) messageCreateHandlerOption() {}
+
+// MessageCreateHandlerOption configures a handler registered by Client.OnMessageCreate. See MessageCreateMiddleware and WithCtrl.
+type MessageCreateHandlerOption interface {
+	messageCreateHandlerOption()
+}
+
+func (CtrlOption) messageCreateHandlerOption() {}
+
+// OnMessageCreate registers a handler for MessageCreate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnMessageCreate(handler MessageCreateHandler, opts ...MessageCreateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case MessageCreateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*MessageCreate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtMessageCreate, inputs)
+}
+
+// MessageDeleteMiddleware filters or modifies MessageDelete events before they reach the handler. Return nil to stop the event.
+type MessageDeleteMiddleware func(evt *MessageDelete) *MessageDelete
+
+func (MessageDeleteMiddleware) messageDeleteHandlerOption() {}
+
+// MessageDeleteHandlerOption configures a handler registered by Client.OnMessageDelete. See MessageDeleteMiddleware and WithCtrl.
+type MessageDeleteHandlerOption interface {
+	messageDeleteHandlerOption()
+}
+
+func (CtrlOption) messageDeleteHandlerOption() {}
+
+// OnMessageDelete registers a handler for MessageDelete events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnMessageDelete(handler MessageDeleteHandler, opts ...MessageDeleteHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case MessageDeleteMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*MessageDelete)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtMessageDelete, inputs)
+}
+
+// MessageDeleteBulkMiddleware filters or modifies MessageDeleteBulk events before they reach the handler. Return nil to stop the event.
+type MessageDeleteBulkMiddleware func(evt *MessageDeleteBulk) *MessageDeleteBulk
+
+func (MessageDeleteBulkMiddleware) messageDeleteBulkHandlerOption() {}
+
+// MessageDeleteBulkHandlerOption configures a handler registered by Client.OnMessageDeleteBulk. See MessageDeleteBulkMiddleware and WithCtrl.
+type MessageDeleteBulkHandlerOption interface {
+	messageDeleteBulkHandlerOption()
+}
+
+func (CtrlOption) messageDeleteBulkHandlerOption() {}
+
+// OnMessageDeleteBulk registers a handler for MessageDeleteBulk events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnMessageDeleteBulk(handler MessageDeleteBulkHandler, opts ...MessageDeleteBulkHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case MessageDeleteBulkMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*MessageDeleteBulk)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtMessageDeleteBulk, inputs)
+}
+
+// MessageReactionAddMiddleware filters or modifies MessageReactionAdd events before they reach the handler. Return nil to stop the event.
+type MessageReactionAddMiddleware func(evt *MessageReactionAdd) *MessageReactionAdd
+
+func (MessageReactionAddMiddleware) messageReactionAddHandlerOption() {}
+
+// MessageReactionAddHandlerOption configures a handler registered by Client.OnMessageReactionAdd. See MessageReactionAddMiddleware and WithCtrl.
+type MessageReactionAddHandlerOption interface {
+	messageReactionAddHandlerOption()
+}
+
+func (CtrlOption) messageReactionAddHandlerOption() {}
+
+// OnMessageReactionAdd registers a handler for MessageReactionAdd events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnMessageReactionAdd(handler MessageReactionAddHandler, opts ...MessageReactionAddHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case MessageReactionAddMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*MessageReactionAdd)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtMessageReactionAdd, inputs)
+}
+
+// MessageReactionRemoveMiddleware filters or modifies MessageReactionRemove events before they reach the handler. Return nil to stop the event.
+type MessageReactionRemoveMiddleware func(evt *MessageReactionRemove) *MessageReactionRemove
+
+func (MessageReactionRemoveMiddleware) messageReactionRemoveHandlerOption() {}
+
+// MessageReactionRemoveHandlerOption configures a handler registered by Client.OnMessageReactionRemove. See MessageReactionRemoveMiddleware and WithCtrl.
+type MessageReactionRemoveHandlerOption interface {
+	messageReactionRemoveHandlerOption()
+}
+
+func (CtrlOption) messageReactionRemoveHandlerOption() {}
+
+// OnMessageReactionRemove registers a handler for MessageReactionRemove events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnMessageReactionRemove(handler MessageReactionRemoveHandler, opts ...MessageReactionRemoveHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case MessageReactionRemoveMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*MessageReactionRemove)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtMessageReactionRemove, inputs)
+}
+
+// MessageReactionRemoveAllMiddleware filters or modifies MessageReactionRemoveAll events before they reach the handler. Return nil to stop the event.
+type MessageReactionRemoveAllMiddleware func(evt *MessageReactionRemoveAll) *MessageReactionRemoveAll
+
+func (MessageReactionRemoveAllMiddleware) messageReactionRemoveAllHandlerOption() {}
+
+// MessageReactionRemoveAllHandlerOption configures a handler registered by Client.OnMessageReactionRemoveAll. See MessageReactionRemoveAllMiddleware and WithCtrl.
+type MessageReactionRemoveAllHandlerOption interface {
+	messageReactionRemoveAllHandlerOption()
+}
+
+func (CtrlOption) messageReactionRemoveAllHandlerOption() {}
+
+// OnMessageReactionRemoveAll registers a handler for MessageReactionRemoveAll events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnMessageReactionRemoveAll(handler MessageReactionRemoveAllHandler, opts ...MessageReactionRemoveAllHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case MessageReactionRemoveAllMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*MessageReactionRemoveAll)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtMessageReactionRemoveAll, inputs)
+}
+
+// MessageUpdateMiddleware filters or modifies MessageUpdate events before they reach the handler. Return nil to stop the event.
+type MessageUpdateMiddleware func(evt *MessageUpdate) *MessageUpdate
+
+func (MessageUpdateMiddleware) messageUpdateHandlerOption() {}
+
+// MessageUpdateHandlerOption configures a handler registered by Client.OnMessageUpdate. See MessageUpdateMiddleware and WithCtrl.
+type MessageUpdateHandlerOption interface {
+	messageUpdateHandlerOption()
+}
+
+func (CtrlOption) messageUpdateHandlerOption() {}
+
+// OnMessageUpdate registers a handler for MessageUpdate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnMessageUpdate(handler MessageUpdateHandler, opts ...MessageUpdateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case MessageUpdateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*MessageUpdate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtMessageUpdate, inputs)
+}
+
+// PresenceUpdateMiddleware filters or modifies PresenceUpdate events before they reach the handler. Return nil to stop the event.
+type PresenceUpdateMiddleware func(evt *PresenceUpdate) *PresenceUpdate
+
+func (PresenceUpdateMiddleware) presenceUpdateHandlerOption() {}
+
+// PresenceUpdateHandlerOption configures a handler registered by Client.OnPresenceUpdate. See PresenceUpdateMiddleware and WithCtrl.
+type PresenceUpdateHandlerOption interface {
+	presenceUpdateHandlerOption()
+}
+
+func (CtrlOption) presenceUpdateHandlerOption() {}
+
+// OnPresenceUpdate registers a handler for PresenceUpdate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnPresenceUpdate(handler PresenceUpdateHandler, opts ...PresenceUpdateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case PresenceUpdateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*PresenceUpdate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtPresenceUpdate, inputs)
+}
+
+// ReadyMiddleware filters or modifies Ready events before they reach the handler. Return nil to stop the event.
+type ReadyMiddleware func(evt *Ready) *Ready
+
+func (ReadyMiddleware) readyHandlerOption() {}
+
+// ReadyHandlerOption configures a handler registered by Client.OnReady. See ReadyMiddleware and WithCtrl.
+type ReadyHandlerOption interface {
+	readyHandlerOption()
+}
+
+func (CtrlOption) readyHandlerOption() {}
+
+// OnReady registers a handler for Ready events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnReady(handler ReadyHandler, opts ...ReadyHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case ReadyMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*Ready)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtReady, inputs)
+}
+
+// ResumedMiddleware filters or modifies Resumed events before they reach the handler. Return nil to stop the event.
+type ResumedMiddleware func(evt *Resumed) *Resumed
+
+func (ResumedMiddleware) resumedHandlerOption() {}
+
+// ResumedHandlerOption configures a handler registered by Client.OnResumed. See ResumedMiddleware and WithCtrl.
+type ResumedHandlerOption interface {
+	resumedHandlerOption()
+}
+
+func (CtrlOption) resumedHandlerOption() {}
+
+// OnResumed registers a handler for Resumed events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnResumed(handler ResumedHandler, opts ...ResumedHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case ResumedMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*Resumed)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtResumed, inputs)
+}
+
+// TypingStartMiddleware filters or modifies TypingStart events before they reach the handler. Return nil to stop the event.
+type TypingStartMiddleware func(evt *TypingStart) *TypingStart
+
+func (TypingStartMiddleware) typingStartHandlerOption() {}
+
+// TypingStartHandlerOption configures a handler registered by Client.OnTypingStart. See TypingStartMiddleware and WithCtrl.
+type TypingStartHandlerOption interface {
+	typingStartHandlerOption()
+}
+
+func (CtrlOption) typingStartHandlerOption() {}
+
+// OnTypingStart registers a handler for TypingStart events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnTypingStart(handler TypingStartHandler, opts ...TypingStartHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case TypingStartMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*TypingStart)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtTypingStart, inputs)
+}
+
+// UserUpdateMiddleware filters or modifies UserUpdate events before they reach the handler. Return nil to stop the event.
+type UserUpdateMiddleware func(evt *UserUpdate) *UserUpdate
+
+func (UserUpdateMiddleware) userUpdateHandlerOption() {}
+
+// UserUpdateHandlerOption configures a handler registered by Client.OnUserUpdate. See UserUpdateMiddleware and WithCtrl.
+type UserUpdateHandlerOption interface {
+	userUpdateHandlerOption()
+}
+
+func (CtrlOption) userUpdateHandlerOption() {}
+
+// OnUserUpdate registers a handler for UserUpdate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnUserUpdate(handler UserUpdateHandler, opts ...UserUpdateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case UserUpdateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*UserUpdate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtUserUpdate, inputs)
+}
+
+// VoiceServerUpdateMiddleware filters or modifies VoiceServerUpdate events before they reach the handler. Return nil to stop the event.
+type VoiceServerUpdateMiddleware func(evt *VoiceServerUpdate) *VoiceServerUpdate
+
+func (VoiceServerUpdateMiddleware) voiceServerUpdateHandlerOption() {}
+
+// VoiceServerUpdateHandlerOption configures a handler registered by Client.OnVoiceServerUpdate. See VoiceServerUpdateMiddleware and WithCtrl.
+type VoiceServerUpdateHandlerOption interface {
+	voiceServerUpdateHandlerOption()
+}
+
+func (CtrlOption) voiceServerUpdateHandlerOption() {}
+
+// OnVoiceServerUpdate registers a handler for VoiceServerUpdate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnVoiceServerUpdate(handler VoiceServerUpdateHandler, opts ...VoiceServerUpdateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case VoiceServerUpdateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*VoiceServerUpdate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtVoiceServerUpdate, inputs)
+}
+
+// VoiceStateUpdateMiddleware filters or modifies VoiceStateUpdate events before they reach the handler. Return nil to stop the event.
+type VoiceStateUpdateMiddleware func(evt *VoiceStateUpdate) *VoiceStateUpdate
+
+func (VoiceStateUpdateMiddleware) voiceStateUpdateHandlerOption() {}
+
+// VoiceStateUpdateHandlerOption configures a handler registered by Client.OnVoiceStateUpdate. See VoiceStateUpdateMiddleware and WithCtrl.
+type VoiceStateUpdateHandlerOption interface {
+	voiceStateUpdateHandlerOption()
+}
+
+func (CtrlOption) voiceStateUpdateHandlerOption() {}
+
+// OnVoiceStateUpdate registers a handler for VoiceStateUpdate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnVoiceStateUpdate(handler VoiceStateUpdateHandler, opts ...VoiceStateUpdateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case VoiceStateUpdateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*VoiceStateUpdate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtVoiceStateUpdate, inputs)
+}
+
+// WebhooksUpdateMiddleware filters or modifies WebhooksUpdate events before they reach the handler. Return nil to stop the event.
+type WebhooksUpdateMiddleware func(evt *WebhooksUpdate) *WebhooksUpdate
+
+func (WebhooksUpdateMiddleware) webhooksUpdateHandlerOption() {}
+
+// WebhooksUpdateHandlerOption configures a handler registered by Client.OnWebhooksUpdate. See WebhooksUpdateMiddleware and WithCtrl.
+type WebhooksUpdateHandlerOption interface {
+	webhooksUpdateHandlerOption()
+}
+
+func (CtrlOption) webhooksUpdateHandlerOption() {}
+
+// OnWebhooksUpdate registers a handler for WebhooksUpdate events. It works like Client.On, but the handler and options are
+// checked at compile time. The middlewares are executed in the given order.
+func (c *Client) OnWebhooksUpdate(handler WebhooksUpdateHandler, opts ...WebhooksUpdateHandlerOption) *Subscription {
+	inputs := make([]interface{}, 0, len(opts)+2)
+	var ctrl HandlerCtrl
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case WebhooksUpdateMiddleware:
+			inputs = append(inputs, Middleware(func(evt interface{}) interface{} {
+				if e := t(evt.(*WebhooksUpdate)); e != nil {
+					return e
+				}
+				return nil
+			}))
+		case CtrlOption:
+			ctrl = t.ctrl
+		}
+	}
+
+	inputs = append(inputs, handler)
+	if ctrl != nil {
+		inputs = append(inputs, ctrl)
+	}
+	return c.subscribeTyped(EvtWebhooksUpdate, inputs)
+}
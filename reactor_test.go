@@ -2,8 +2,14 @@ package disgord
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/andersfylling/disgord/internal/gateway"
 )
 
 func Test_isHandler(t *testing.T) {
@@ -19,6 +25,11 @@ func Test_isHandler(t *testing.T) {
 		func() {},
 		func(s Session) {},
 		func(s Session, e *MessageCreate) {},
+		func() error { return nil },
+		func(s Session) error { return nil },
+		func(s Session, e *MessageCreate) error { return nil },
+		func(s Session, e *RawEvent) {},
+		make(chan *RawEvent),
 	}
 	for i := range handlers {
 		if !isHandler(handlers[i]) {
@@ -72,3 +83,442 @@ func TestCtrl_CloseChannel(t *testing.T) {
 	// should not hang
 	d.dispatch(context.Background(), EvtMessageCreate, &MessageCreate{})
 }
+
+func TestDispatcher_HandlerPanic(t *testing.T) {
+	d := newDispatcher()
+	var panicked []interface{}
+	d.onHandlerPanic = func(evtName string, recovered interface{}, stack []byte) {
+		if evtName != EvtMessageCreate {
+			t.Errorf("expected event %s, got %s", EvtMessageCreate, evtName)
+		}
+		if len(stack) == 0 {
+			t.Error("expected a stack trace")
+		}
+		panicked = append(panicked, recovered)
+	}
+
+	var executed bool
+	err := d.register(EvtMessageCreate, func() {
+		panic("handler")
+	}, func() {
+		executed = true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mdlw Middleware = func(interface{}) interface{} {
+		panic("middleware")
+	}
+	if err = d.register(EvtMessageCreate, mdlw, func() {
+		t.Error("expected the handler to be skipped when the middleware panics")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	d.dispatch(context.Background(), EvtMessageCreate, &MessageCreate{})
+	if !executed {
+		t.Error("expected the remaining handlers to run after a panic")
+	}
+	if len(panicked) != 2 || panicked[0] != "handler" || panicked[1] != "middleware" {
+		t.Errorf("expected both panics to be recovered, got %+v", panicked)
+	}
+}
+
+func TestDispatcher_ErrHandler(t *testing.T) {
+	d := newDispatcher()
+	var errs []error
+	d.onHandlerError = func(evtName string, err error) {
+		errs = append(errs, err)
+	}
+
+	failure := errors.New("failure")
+	var handler MessageCreateErrHandler = func(s Session, h *MessageCreate) error {
+		return failure
+	}
+	var succeeds SimplestErrHandler = func() error {
+		return nil
+	}
+	if err := d.register(EvtMessageCreate, handler, succeeds); err != nil {
+		t.Fatal(err)
+	}
+
+	d.dispatch(context.Background(), EvtMessageCreate, &MessageCreate{})
+	if len(errs) != 1 || errs[0] != failure {
+		t.Errorf("expected the handler error to be reported, got %+v", errs)
+	}
+}
+
+func TestDispatcher_HandlerTimeout(t *testing.T) {
+	d := newDispatcher()
+	d.handlerTimeout = 10 * time.Millisecond
+	errs := make(chan error, 1)
+	d.onHandlerError = func(evtName string, err error) {
+		errs <- err
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	cancelled := make(chan struct{})
+	if err := d.register(EvtMessageCreate, func(s Session, h *MessageCreate) {
+		<-h.Ctx.Done()
+		close(cancelled)
+		<-release
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var executed bool
+	if err := d.register(EvtMessageCreate, func(s Session, h *MessageCreate) {
+		executed = true
+		if h.Ctx.Err() != nil {
+			t.Error("expected every handler spec to have their own context")
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	evt := &MessageCreate{Ctx: context.Background()}
+	d.dispatch(context.Background(), EvtMessageCreate, evt)
+	if !executed {
+		t.Error("expected the next handler spec to run after a timeout")
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the context of the hung handler to be cancelled")
+	}
+
+	err := <-errs
+	if e, ok := err.(*ErrorHandlerTimeout); !ok || e.Event != EvtMessageCreate {
+		t.Errorf("expected a handler timeout error, got %+v", err)
+	}
+	if evt.Ctx != context.Background() {
+		t.Error("expected the original event to be left untouched")
+	}
+}
+
+func TestDispatcher_HandlerTimeoutSerial(t *testing.T) {
+	d := newDispatcher()
+	d.handlerTimeout = 10 * time.Millisecond
+	d.onHandlerError = func(evtName string, err error) {}
+
+	var (
+		mu               sync.Mutex
+		running, maxRuns int
+		order            []string
+	)
+	release := make(chan struct{})
+	if err := d.register(EvtMessageCreate, func(s Session, h *MessageCreate) {
+		mu.Lock()
+		running++
+		if running > maxRuns {
+			maxRuns = running
+		}
+		order = append(order, h.Message.Content)
+		mu.Unlock()
+
+		if h.Message.Content == "hung" {
+			<-release
+		}
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	d.dispatch(context.Background(), EvtMessageCreate, &MessageCreate{Message: &Message{Content: "hung"}})
+
+	// the spec is still locked by the hung handler, so the next event waits for it
+	next := make(chan struct{})
+	go func() {
+		d.dispatch(context.Background(), EvtMessageCreate, &MessageCreate{Message: &Message{Content: "next"}})
+		close(next)
+	}()
+	select {
+	case <-next:
+		t.Fatal("expected the next event to wait for the timed out handler")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-next:
+	case <-time.After(time.Second):
+		t.Fatal("expected the next event to be dispatched once the handler returned")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if maxRuns != 1 {
+		t.Errorf("expected the spec to never run concurrently, got %d runs", maxRuns)
+	}
+	if len(order) != 2 || order[0] != "hung" || order[1] != "next" {
+		t.Errorf("expected the events in order, got %v", order)
+	}
+}
+
+func TestDispatcher_HandlerTimeoutContext(t *testing.T) {
+	d := newDispatcher()
+	d.handlerTimeout = time.Minute
+
+	contexts := make(chan context.Context, 1)
+	if err := d.register(EvtMessageCreate, func(s Session, h *MessageCreate) {
+		contexts <- h.Ctx
+	}); err != nil {
+		t.Fatal(err)
+	}
+	messages := make(chan *MessageCreate, 1)
+	if err := d.register(EvtMessageCreate, messages); err != nil {
+		t.Fatal(err)
+	}
+
+	d.dispatch(context.Background(), EvtMessageCreate, &MessageCreate{Ctx: context.Background()})
+	if ctx := <-contexts; ctx.Err() != nil {
+		t.Errorf("expected the handler context to outlive the handler, got %v", ctx.Err())
+	}
+	evt := <-messages
+	if evt.Ctx.Err() != nil {
+		t.Errorf("expected the channel consumer to get a live context, got %v", evt.Ctx.Err())
+	}
+	if _, hasDeadline := evt.Ctx.Deadline(); hasDeadline {
+		t.Error("expected the channel consumer to get the event context, without the handler timeout")
+	}
+}
+
+func TestDispatcher_HandlerTimeoutParentCancelled(t *testing.T) {
+	d := newDispatcher()
+	d.handlerTimeout = time.Second
+	errs := make(chan error, 1)
+	d.onHandlerError = func(evtName string, err error) {
+		errs <- err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := d.register(EvtMessageCreate, func(s Session, h *MessageCreate) {
+		cancel()
+		<-h.Ctx.Done()
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	d.dispatch(ctx, EvtMessageCreate, &MessageCreate{Ctx: ctx})
+	select {
+	case err := <-errs:
+		t.Errorf("expected no handler timeout when the parent context is cancelled, got %+v", err)
+	default:
+	}
+}
+
+type countingCtrl struct {
+	eternalHandlersCtrl
+	mu      sync.Mutex
+	removed int
+}
+
+func (c *countingCtrl) OnRemove(Session) error {
+	c.mu.Lock()
+	c.removed++
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *countingCtrl) nrOfRemovals() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.removed
+}
+
+func TestSubscription_Unsubscribe(t *testing.T) {
+	d := newDispatcher()
+	ctrl := &countingCtrl{}
+
+	var triggered int
+	sub, err := d.subscribe(EvtMessageCreate, func() {
+		triggered++
+	}, ctrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Event() != EvtMessageCreate {
+		t.Errorf("expected event %s, got %s", EvtMessageCreate, sub.Event())
+	}
+
+	d.dispatch(context.Background(), EvtMessageCreate, &MessageCreate{})
+	if triggered != 1 {
+		t.Fatalf("expected handler to be triggered once, got %d", triggered)
+	}
+
+	sub.Unsubscribe()
+	if got := d.nrOfAliveHandlers(); got != 0 {
+		t.Errorf("expected the handler to be removed immediately, got %d handlers", got)
+	}
+	if ctrl.nrOfRemovals() != 1 {
+		t.Errorf("expected OnRemove to be called once, got %d", ctrl.nrOfRemovals())
+	}
+
+	d.dispatch(context.Background(), EvtMessageCreate, &MessageCreate{})
+	if triggered != 1 {
+		t.Errorf("expected handler to not be triggered after unsubscribing, got %d", triggered)
+	}
+
+	sub.Unsubscribe()
+	if ctrl.nrOfRemovals() != 1 {
+		t.Errorf("expected OnRemove to only be called once, got %d", ctrl.nrOfRemovals())
+	}
+}
+
+func TestSubscription_UnsubscribeFromHandler(t *testing.T) {
+	d := newDispatcher()
+
+	var sub *Subscription
+	var triggered int
+	sub, err := d.subscribe(EvtMessageCreate, func() {
+		triggered++
+		sub.Unsubscribe()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.dispatch(context.Background(), EvtMessageCreate, &MessageCreate{})
+	d.dispatch(context.Background(), EvtMessageCreate, &MessageCreate{})
+	if triggered != 1 {
+		t.Errorf("expected handler to be triggered once, got %d", triggered)
+	}
+}
+
+func TestSubscription_UnsubscribeConcurrently(t *testing.T) {
+	d := newDispatcher()
+
+	subs := make([]*Subscription, 0, 50)
+	ctrls := make([]*countingCtrl, 0, 50)
+	for i := 0; i < 50; i++ {
+		ctrl := &countingCtrl{}
+		sub, err := d.subscribe(EvtMessageCreate, func() {}, ctrl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		subs = append(subs, sub)
+		ctrls = append(ctrls, ctrl)
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				d.dispatch(context.Background(), EvtMessageCreate, &MessageCreate{})
+			}
+		}()
+	}
+	for i := range subs {
+		wg.Add(1)
+		go func(sub *Subscription) {
+			defer wg.Done()
+			sub.Unsubscribe()
+			sub.Unsubscribe()
+		}(subs[i])
+	}
+	wg.Wait()
+
+	if got := d.nrOfAliveHandlers(); got != 0 {
+		t.Errorf("expected every handler to be removed, got %d", got)
+	}
+	for i := range ctrls {
+		if got := ctrls[i].nrOfRemovals(); got != 1 {
+			t.Errorf("expected OnRemove to be called once, got %d", got)
+		}
+	}
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *recordingLogger) record(v ...interface{}) {
+	l.mu.Lock()
+	l.entries = append(l.entries, fmt.Sprint(v...))
+	l.mu.Unlock()
+}
+
+func (l *recordingLogger) Debug(v ...interface{}) { l.record(v...) }
+func (l *recordingLogger) Info(v ...interface{})  { l.record(v...) }
+func (l *recordingLogger) Error(v ...interface{}) { l.record(v...) }
+
+func (l *recordingLogger) contains(substr string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, entry := range l.entries {
+		if strings.Contains(entry, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDemultiplexer_RawEvents(t *testing.T) {
+	log := &recordingLogger{}
+	c := New(Config{
+		BotToken:     "testing",
+		DisableCache: true,
+		Logger:       log,
+	})
+	defer close(c.dispatcher.shutdown)
+
+	input := make(chan *gateway.Event)
+	go demultiplexer(c.dispatcher, input, nil)
+
+	raws := make(chan *RawEvent, 2)
+	c.On(EvtRaw, func(s Session, evt *RawEvent) {
+		raws <- evt
+	})
+	messages := make(chan *MessageCreate, 1)
+	c.On(EvtMessageCreate, func(s Session, evt *MessageCreate) {
+		messages <- evt
+	})
+
+	input <- &gateway.Event{Name: "SOMETHING_NEW", Data: []byte(`{"id":"1"}`), ShardID: 2, Sequence: 5}
+	input <- &gateway.Event{Name: EvtMessageCreate, Data: []byte(`{"content":"hi"}`), ShardID: 2, Sequence: 6}
+
+	received := map[string]*RawEvent{}
+	for i := 0; i < 2; i++ {
+		select {
+		case evt := <-raws:
+			received[evt.Name] = evt
+		case <-time.After(time.Second):
+			t.Fatal("expected every event to reach the raw handler")
+		}
+	}
+
+	unknown, ok := received["SOMETHING_NEW"]
+	if !ok {
+		t.Fatal("expected unknown events to reach the raw handler")
+	}
+	if unknown.ShardID != 2 || unknown.Sequence != 5 || string(unknown.Data) != `{"id":"1"}` {
+		t.Errorf("unexpected raw event: %+v", unknown)
+	}
+	if sequence, _ := EventSequence(unknown.Ctx); sequence != 5 {
+		t.Errorf("expected the raw event context to hold the sequence, got %d", sequence)
+	}
+	if _, ok = received[EvtMessageCreate]; !ok {
+		t.Error("expected known events to reach the raw handler")
+	}
+
+	select {
+	case msg := <-messages:
+		if msg.Message.Content != "hi" {
+			t.Errorf("expected content hi, got %s", msg.Message.Content)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected known events to still reach their handlers")
+	}
+
+	if !log.contains("SOMETHING_NEW") {
+		t.Error("expected the unknown event to be logged")
+	}
+}
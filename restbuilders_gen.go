@@ -94,11 +94,6 @@ func (b *updateChannelBuilder) SetParentID(parentID Snowflake) *updateChannelBui
 	return b
 }
 
-func (b *updateChannelBuilder) SetPermissionOverwrites(permissionOverwrites []PermissionOverwrite) *updateChannelBuilder {
-	b.r.param("permission_overwrites", permissionOverwrites)
-	return b
-}
-
 func (b *updateChannelBuilder) SetUserLimit(userLimit uint) *updateChannelBuilder {
 	b.r.param("user_limit", userLimit)
 	return b
@@ -533,11 +528,6 @@ func (b *updateGuildRoleBuilder) SetName(name string) *updateGuildRoleBuilder {
 	return b
 }
 
-func (b *updateGuildRoleBuilder) SetPermissions(permissions PermissionBits) *updateGuildRoleBuilder {
-	b.r.param("permissions", permissions)
-	return b
-}
-
 func (b *updateGuildRoleBuilder) SetColor(color uint) *updateGuildRoleBuilder {
 	b.r.param("color", color)
 	return b
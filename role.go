@@ -2,6 +2,7 @@ package disgord
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
@@ -51,14 +52,14 @@ func NewRole() *Role {
 type Role struct {
 	Lockable `json:"-"`
 
-	ID          Snowflake `json:"id"`
-	Name        string    `json:"name"`
-	Color       uint      `json:"color"`
-	Hoist       bool      `json:"hoist"`
-	Position    int       `json:"position"` // can be -1
-	Permissions uint64    `json:"permissions"`
-	Managed     bool      `json:"managed"`
-	Mentionable bool      `json:"mentionable"`
+	ID          Snowflake      `json:"id"`
+	Name        string         `json:"name"`
+	Color       uint           `json:"color"`
+	Hoist       bool           `json:"hoist"`
+	Position    int            `json:"position"` // can be -1
+	Permissions PermissionBits `json:"permissions"`
+	Managed     bool           `json:"managed"`
+	Mentionable bool           `json:"mentionable"`
 
 	guildID Snowflake
 }
@@ -70,6 +71,21 @@ var _ Copier = (*Role)(nil)
 var _ discordDeleter = (*Role)(nil)
 var _ fmt.Stringer = (*Role)(nil)
 
+// UnmarshalJSON see interface json.Unmarshaler
+func (r *Role) UnmarshalJSON(data []byte) error {
+	type roleJSON Role
+	j := struct {
+		*roleJSON
+		Permissions permissionBitsJSON `json:"permissions"`
+	}{roleJSON: (*roleJSON)(r), Permissions: permissionBitsJSON(r.Permissions)}
+	if err := unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	r.Permissions = PermissionBits(j.Permissions)
+	return nil
+}
+
 func (r *Role) String() string {
 	return r.Name
 }
@@ -153,11 +169,11 @@ func (r *Role) deleteFromDiscord(ctx context.Context, s Session, flags ...Flag)
 // CreateGuildRoleParams ...
 // https://discordapp.com/developers/docs/resources/guild#create-guild-role-json-params
 type CreateGuildRoleParams struct {
-	Name        string `json:"name,omitempty"`
-	Permissions uint64 `json:"permissions,omitempty"`
-	Color       uint   `json:"color,omitempty"`
-	Hoist       bool   `json:"hoist,omitempty"`
-	Mentionable bool   `json:"mentionable,omitempty"`
+	Name        string         `json:"name,omitempty"`
+	Permissions PermissionBits `json:"permissions,omitempty"`
+	Color       uint           `json:"color,omitempty"`
+	Hoist       bool           `json:"hoist,omitempty"`
+	Mentionable bool           `json:"mentionable,omitempty"`
 
 	// Reason is a X-Audit-Log-Reason header field that will show up on the audit log for this action.
 	Reason string `json:"-"`
@@ -171,11 +187,19 @@ type CreateGuildRoleParams struct {
 //  Reviewed                2018-08-18
 //  Comment                 All JSON params are optional.
 func (c *Client) CreateGuildRole(ctx context.Context, id Snowflake, params *CreateGuildRoleParams, flags ...Flag) (ret *Role, err error) {
+	var body interface{} = params
+	if c.req.APIVersion() >= 8 {
+		body = &struct {
+			*CreateGuildRoleParams
+			Permissions permissionBitsJSON `json:"permissions,omitempty"`
+		}{params, permissionBitsJSON(params.Permissions)}
+	}
+
 	r := c.newRESTRequest(&httd.Request{
 		Method:      httd.MethodPost,
 		Ctx:         ctx,
 		Endpoint:    endpoint.GuildRoles(id),
-		Body:        params,
+		Body:        body,
 		ContentType: httd.ContentTypeJSON,
 		Reason:      params.Reason,
 	}, flags)
@@ -199,7 +223,7 @@ func (c *Client) CreateGuildRole(ctx context.Context, id Snowflake, params *Crea
 //  Reviewed                2018-08-18
 //  Comment                 -
 func (c *Client) UpdateGuildRole(ctx context.Context, guildID, roleID Snowflake, flags ...Flag) (builder *updateGuildRoleBuilder) {
-	builder = &updateGuildRoleBuilder{}
+	builder = &updateGuildRoleBuilder{apiVersion: c.req.APIVersion()}
 	builder.r.itemFactory = func() interface{} {
 		return &Role{}
 	}
@@ -265,9 +289,11 @@ func (c *Client) GetGuildRoles(ctx context.Context, guildID Snowflake, flags ...
 	return getRoles(r.Execute)
 }
 
-// GetMemberPermissions populates a uint64 with all the permission flags
+// GetMemberPermissions returns the guild wide permissions of the member; the permissions of the @everyone role
+// and the roles of the member. The guild owner and administrators are granted every permission.
+// See GetMemberChannelPermissions for the permissions in a channel.
 func (c *Client) GetMemberPermissions(ctx context.Context, guildID, userID Snowflake, flags ...Flag) (permissions PermissionBits, err error) {
-	roles, err := c.GetGuildRoles(ctx, guildID, flags...)
+	guild, err := c.GetGuild(ctx, guildID, flags...)
 	if err != nil {
 		return 0, err
 	}
@@ -277,22 +303,30 @@ func (c *Client) GetMemberPermissions(ctx context.Context, guildID, userID Snowf
 		return 0, err
 	}
 
-	roleIDs := member.Roles
-	for i := range roles {
-		for j := range roleIDs {
-			if roles[i].ID == roleIDs[j] {
-				permissions |= roles[i].Permissions
-				roleIDs = roleIDs[:j+copy(roleIDs[j:], roleIDs[j+1:])]
-				break
-			}
-		}
+	return memberBasePermissions(guild, member), nil
+}
 
-		if len(roleIDs) == 0 {
-			break
-		}
+// GetMemberChannelPermissions returns the permissions of the member in a guild channel, after applying the
+// permission overwrites of the channel.
+func (c *Client) GetMemberChannelPermissions(ctx context.Context, channelID, userID Snowflake, flags ...Flag) (permissions PermissionBits, err error) {
+	channel, err := c.GetChannel(ctx, channelID, flags...)
+	if err != nil {
+		return 0, err
+	}
+	if channel.GuildID.IsZero() {
+		return 0, errors.New("permissions can only be calculated for guild channels")
+	}
+
+	guild, err := c.GetGuild(ctx, channel.GuildID, flags...)
+	if err != nil {
+		return 0, err
+	}
+	member, err := c.GetMember(ctx, channel.GuildID, userID, flags...)
+	if err != nil {
+		return 0, err
 	}
 
-	return permissions, nil
+	return memberChannelPermissions(guild, member, channel), nil
 }
 
 //////////////////////////////////////////////////////
@@ -303,7 +337,14 @@ func (c *Client) GetMemberPermissions(ctx context.Context, guildID, userID Snowf
 
 // updateGuildRoleBuilder ...
 //generate-rest-basic-execute: role:*Role,
-//generate-rest-params: name:string, permissions:PermissionBits, color:uint, hoist:bool, mentionable:bool,
+//generate-rest-params: name:string, color:uint, hoist:bool, mentionable:bool,
 type updateGuildRoleBuilder struct {
-	r RESTBuilder
+	r          RESTBuilder
+	apiVersion int
+}
+
+// SetPermissions sets the permission bit set of the role. They are sent as a string from v8.
+func (b *updateGuildRoleBuilder) SetPermissions(permissions PermissionBits) *updateGuildRoleBuilder {
+	b.r.param("permissions", permissionsParam(b.apiVersion, permissions))
+	return b
 }
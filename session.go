@@ -53,6 +53,10 @@ type SocketHandler interface {
 	//  Client.On(EvtReady, onReady, &Ctrl{Duration: 10*time.Minute})
 	On(event string, inputs ...interface{})
 
+	// Subscribe works like On, but returns an error in stead of panicking. The handlers can be removed
+	// at any time using Subscription.Unsubscribe.
+	Subscribe(event string, inputs ...interface{}) (*Subscription, error)
+
 	Emitter
 }
 
@@ -296,6 +300,12 @@ type RESTGuild interface {
 	GetGuildRoles(ctx context.Context, guildID Snowflake, flags ...Flag) ([]*Role, error)
 
 	GetMemberPermissions(ctx context.Context, guildID, userID Snowflake, flags ...Flag) (permissions PermissionBits, err error)
+	GetMemberChannelPermissions(ctx context.Context, channelID, userID Snowflake, flags ...Flag) (permissions PermissionBits, err error)
+
+	// CanManageMember checks if the actor outranks the target in the role hierarchy.
+	CanManageMember(ctx context.Context, guildID, actorID, targetID Snowflake, flags ...Flag) (bool, error)
+	// CanManageRole checks if the actor outranks the role in the role hierarchy.
+	CanManageRole(ctx context.Context, guildID, actorID, roleID Snowflake, flags ...Flag) (bool, error)
 
 	// CreateGuildRole Create a new role for the guild. Requires the 'MANAGE_ROLES' permission.
 	// Returns the new role object on success. Fires a Guild Role Create Gateway event.
@@ -474,6 +484,8 @@ type RESTMethods interface {
 // VoiceHandler holds all the voice connection related methods
 type VoiceHandler interface {
 	VoiceConnect(guildID, channelID Snowflake) (ret VoiceConnection, err error)
+	VoiceConnection(guildID Snowflake) VoiceConnection
+	VoiceConnections() map[Snowflake]VoiceConnection
 }
 
 // Session Is the runtime interface for DisGord. It allows you to interact with a live session (using sockets or not).
@@ -94,6 +94,8 @@ func (f *msgFilter) StripPrefix(evt interface{}) interface{} {
 	return evt
 }
 
+// HasPermissions filters out messages from authors without the permissions given by SetMinPermissions and
+// SetAltPermissions, in the channel of the message
 func (f *msgFilter) HasPermissions(evt interface{}) interface{} {
 	msg := getMsg(evt)
 	uID := msg.Author.ID
@@ -101,7 +103,7 @@ func (f *msgFilter) HasPermissions(evt interface{}) interface{} {
 		return nil
 	}
 
-	p, err := f.s.GetMemberPermissions(context.Background(), msg.GuildID, uID)
+	p, err := f.s.GetMemberChannelPermissions(context.Background(), msg.ChannelID, uID)
 	if err != nil {
 		return nil
 	}
@@ -215,6 +215,37 @@ func (mfal *MFALvl) Elevated() bool {
 	return *mfal == MFALvlElevated
 }
 
+// PremiumTier is the server boost level of a guild
+// https://discordapp.com/developers/docs/resources/guild#guild-object-premium-tier
+type PremiumTier uint
+
+// the different server boost levels
+const (
+	PremiumTierNone PremiumTier = iota
+	PremiumTier1
+	PremiumTier2
+	PremiumTier3
+)
+
+// upload limits in bytes for each server boost level, including DMs
+const (
+	UploadLimitDefault int64 = 8 * 1024 * 1024
+	UploadLimitTier2   int64 = 50 * 1024 * 1024
+	UploadLimitTier3   int64 = 100 * 1024 * 1024
+)
+
+// UploadLimit returns the maximum number of bytes that can be uploaded per message
+func (pt PremiumTier) UploadLimit() int64 {
+	switch pt {
+	case PremiumTier2:
+		return UploadLimitTier2
+	case PremiumTier3:
+		return UploadLimitTier3
+	default:
+		return UploadLimitDefault
+	}
+}
+
 // VerificationLvl ...
 // https://discordapp.com/developers/docs/resources/guild#guild-object-verification-level
 type VerificationLvl uint
@@ -775,12 +775,12 @@ func (c *Client) GetCurrentUser(ctx context.Context, flags ...Flag) (user *User,
 		Ctx:      ctx,
 	}, flags)
 	r.CacheRegistry = UserCache
-	r.ID = c.myID
+	r.ID = c.botID()
 	r.pool = c.pool.user
 	r.factory = userFactory
 
 	if user, err = getUser(r.Execute); err == nil {
-		c.myID = user.ID
+		c.setBotID(user.ID)
 	}
 	return user, err
 }
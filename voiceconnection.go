@@ -1,6 +1,7 @@
 package disgord
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 	"io"
@@ -12,8 +13,6 @@ import (
 
 	"github.com/andersfylling/disgord/internal/gateway"
 	"github.com/andersfylling/disgord/internal/gateway/cmd"
-
-	"golang.org/x/crypto/nacl/secretbox"
 )
 
 type voiceRepository struct {
@@ -22,6 +21,47 @@ type voiceRepository struct {
 
 	pendingStates  map[Snowflake]chan *VoiceStateUpdate
 	pendingServers map[Snowflake]chan *VoiceServerUpdate
+
+	// active holds the established voice connections, that must follow the voice state
+	// and voice server of the bot
+	active map[Snowflake]*voiceImpl
+
+	// connecting holds the guilds with a VoiceConnect in progress
+	connecting map[Snowflake]bool
+}
+
+// VoiceConnectionState is the state of a VoiceConnection, see VoiceConnection.OnStateChange.
+type VoiceConnectionState int
+
+const (
+	// VoiceConnectionConnected means audio can be sent and received
+	VoiceConnectionConnected VoiceConnectionState = iota
+	// VoiceConnectionResuming means the websocket connection was lost, and the session is being resumed
+	VoiceConnectionResuming
+	// VoiceConnectionReconnecting means a new session is being established, eg. because the voice server changed
+	VoiceConnectionReconnecting
+	// VoiceConnectionDisconnected means the connection was lost, and Discord must assign a voice server
+	// before audio can be sent again. eg. the bot was kicked or the voice server is not available
+	VoiceConnectionDisconnected
+	// VoiceConnectionClosed means the connection was closed by VoiceConnection.Close
+	VoiceConnectionClosed
+)
+
+func (s VoiceConnectionState) String() string {
+	switch s {
+	case VoiceConnectionConnected:
+		return "connected"
+	case VoiceConnectionResuming:
+		return "resuming"
+	case VoiceConnectionReconnecting:
+		return "reconnecting"
+	case VoiceConnectionDisconnected:
+		return "disconnected"
+	case VoiceConnectionClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
 }
 
 // VoiceConnection is the interface used to interact with active voice connections.
@@ -37,26 +77,117 @@ type VoiceConnection interface {
 	SendOpusFrame(data []byte)
 	// SendDCA reads from a Reader expecting a DCA encoded stream/file and sends them as frames.
 	SendDCA(r io.Reader) error
+	// SendOggOpus reads from a Reader expecting an Ogg Opus stream/file, eg. an .opus file, and sends the packets
+	// as frames until the end of the stream or until the context is cancelled.
+	SendOggOpus(ctx context.Context, r io.Reader) error
+
+	// Receive returns the Opus frames received from the users in the voice channel. The channel is closed
+	// when the connection is closed. Frames are dropped when the channel is not read from, see
+	// VoiceReceiveBufferSize.
+	Receive() <-chan *VoicePacket
+
+	// ChannelID returns the voice channel the bot is connected to.
+	ChannelID() Snowflake
+	// MoveTo moves the bot to another voice channel in the same guild. The connection is re-established
+	// automatically when Discord assigns a different voice server.
+	MoveTo(channelID Snowflake) error
+
+	// Stats returns the counters of the sent frames.
+	Stats() VoiceStats
+	// Latency returns the round trip time of the last heartbeat of the voice websocket.
+	Latency() (time.Duration, error)
+
+	// State returns the current state of the connection.
+	State() VoiceConnectionState
+	// OnStateChange registers a handler that is called whenever the connection changes state, eg. when the
+	// connection is lost and resumed. Frames sent while the connection is not connected are lost.
+	OnStateChange(handler func(state VoiceConnectionState))
 
 	// Close closes the websocket and UDP connection. This VoiceConnection interface will no longer be usable and will
-	// panic if any other functions are called beyond this point. It is the callers responsibility to ensure there are
-	// no concurrent calls to any other methods of this interface after calling Close.
+	// panic if any other functions are called beyond this point, except SendOpusFrame which discards the frames, and
+	// StartSpeaking and StopSpeaking which return an error. It is the callers responsibility to ensure there are no
+	// concurrent calls to any other methods of this interface after calling Close.
+	//
+	// The connection is also closed when the bot leaves the voice channel by other means, eg. when it is kicked, in
+	// which case the state changes to VoiceConnectionClosed, sent frames are discarded and Close must still be called.
 	Close() error
 }
 
+// voiceLink is a single session with a voice server. The link is replaced when the voice server changes,
+// or when the session can not be resumed.
+type voiceLink struct {
+	ws  *gateway.VoiceClient
+	udp net.Conn
+
+	ssrc   uint32
+	crypto *voiceCrypto
+
+	// done is closed when the link is replaced or closed
+	done chan struct{}
+}
+
+func (l *voiceLink) close() error {
+	if l.done != nil {
+		close(l.done)
+	}
+
+	var err1, err2 error
+	if l.udp != nil {
+		err1 = l.udp.Close()
+	}
+	if l.ws != nil {
+		err2 = l.ws.Disconnect()
+	}
+
+	if err1 != nil || err2 != nil {
+		var errMsg string
+		if err1 != nil {
+			errMsg += err1.Error()
+		}
+		if err2 != nil {
+			errMsg += err2.Error()
+		}
+
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
+
 type voiceImpl struct {
 	sync.Mutex
+	voiceLink
 
 	ready bool
 
-	ws  *gateway.VoiceClient
-	udp net.Conn
+	// closed is set once the connection is torn down, by Close or when the bot left the voice channel
+	closed bool
 
-	ssrc      uint32
-	secretKey [32]byte
 	send      chan []byte
+	receive   chan *VoicePacket
+	receivers sync.WaitGroup
 	close     chan struct{}
 
+	ssrcs voiceSSRCs
+
+	// clock paces the sent frames, defaults to the system clock
+	clock voiceClock
+	stats voiceStats
+
+	// session of the bot, see VoiceStateUpdate and VoiceServerUpdate
+	sessionID string
+	channelID Snowflake
+	selfDeaf  bool
+	selfMute  bool
+	endpoint  string
+	token     string
+
+	reconnecting bool
+
+	stateMu       sync.Mutex
+	state         VoiceConnectionState
+	stateHandlers []func(state VoiceConnectionState)
+
 	guildID Snowflake
 	c       *Client
 }
@@ -67,6 +198,8 @@ func newVoiceRepository(c *Client) (voice *voiceRepository) {
 
 		pendingStates:  make(map[Snowflake]chan *VoiceStateUpdate),
 		pendingServers: make(map[Snowflake]chan *VoiceServerUpdate),
+		active:         make(map[Snowflake]*voiceImpl),
+		connecting:     make(map[Snowflake]bool),
 	}
 	c.On(EvtVoiceServerUpdate, voice.onVoiceServerUpdate)
 	c.On(EvtVoiceStateUpdate, voice.onVoiceStateUpdate)
@@ -74,10 +207,55 @@ func newVoiceRepository(c *Client) (voice *voiceRepository) {
 	return voice
 }
 
+// VoiceConnect joins a voice channel, see VoiceConnectOptions.
 func (r *voiceRepository) VoiceConnect(guildID, channelID Snowflake) (ret VoiceConnection, err error) {
 	return r.VoiceConnectOptions(guildID, channelID, false, false)
 }
 
+// VoiceConnection returns the active voice connection of the guild, or nil.
+func (r *voiceRepository) VoiceConnection(guildID Snowflake) VoiceConnection {
+	r.Lock()
+	defer r.Unlock()
+
+	if voice, exists := r.active[guildID]; exists {
+		return voice
+	}
+	return nil
+}
+
+// VoiceConnections returns the active voice connections, by guild ID.
+func (r *voiceRepository) VoiceConnections() map[Snowflake]VoiceConnection {
+	r.Lock()
+	defer r.Unlock()
+
+	connections := make(map[Snowflake]VoiceConnection, len(r.active))
+	for guildID, voice := range r.active {
+		connections[guildID] = voice
+	}
+	return connections
+}
+
+// closeAll tears down every active voice connection and leaves the voice channels, before the client disconnects
+// from the gateway. The connections are not closed, such that a later call to Close by the owner, or frames sent
+// by a goroutine that is still running, does not panic.
+func (r *voiceRepository) closeAll() {
+	r.Lock()
+	connections := make([]*voiceImpl, 0, len(r.active))
+	for _, voice := range r.active {
+		connections = append(connections, voice)
+	}
+	r.Unlock()
+
+	for _, voice := range connections {
+		if err := voice.shutdown(); err != nil {
+			r.c.log.Error("unable to close voice connection for guild ", voice.guildID, ": ", err)
+		}
+		voice.leave()
+	}
+}
+
+// VoiceConnectOptions joins a voice channel. When the bot is already connected to a voice channel in the
+// guild, the existing connection is moved to the channel and returned, with selfDeaf and selfMute applied.
 func (r *voiceRepository) VoiceConnectOptions(guildID, channelID Snowflake, selfDeaf, selfMute bool) (ret VoiceConnection, err error) {
 	if guildID.IsZero() {
 		err = errors.New("guildID must be set to connect to a voice channel")
@@ -88,10 +266,28 @@ func (r *voiceRepository) VoiceConnectOptions(guildID, channelID Snowflake, self
 		return
 	}
 
+	// a bot can only be in one voice channel per guild
+	r.Lock()
+	if r.connecting[guildID] {
+		r.Unlock()
+		err = errors.New("already connecting to a voice channel in guild " + guildID.String())
+		return
+	}
+	if voice, exists := r.active[guildID]; exists {
+		r.Unlock()
+		changed := voice.setSelfState(selfDeaf, selfMute)
+		if voice.ChannelID() != channelID {
+			err = voice.MoveTo(channelID)
+		} else if changed {
+			err = voice.updateSelfState()
+		}
+		return voice, err
+	}
+	r.connecting[guildID] = true
+
 	// Set up some listeners for this connection attempt
 	stateCh := make(chan *VoiceStateUpdate, 1)
 	serverCh := make(chan *VoiceServerUpdate, 1)
-	r.Lock()
 	r.pendingStates[guildID] = stateCh
 	r.pendingServers[guildID] = serverCh
 	r.Unlock()
@@ -101,6 +297,7 @@ func (r *voiceRepository) VoiceConnectOptions(guildID, channelID Snowflake, self
 
 		delete(r.pendingStates, guildID)
 		delete(r.pendingServers, guildID)
+		delete(r.connecting, guildID)
 	}(r, guildID)
 
 	// Tell Discord we want to connect to a channel
@@ -138,51 +335,92 @@ waiter:
 		}
 	}
 
-	voice := voiceImpl{
-		guildID: guildID,
-		c:       r.c,
-		send:    make(chan []byte),
-		close:   make(chan struct{}),
+	voice := &voiceImpl{
+		guildID:   guildID,
+		c:         r.c,
+		send:      make(chan []byte),
+		receive:   make(chan *VoicePacket, VoiceReceiveBufferSize),
+		close:     make(chan struct{}),
+		sessionID: state.SessionID,
+		channelID: channelID,
+		selfDeaf:  selfDeaf,
+		selfMute:  selfMute,
+		endpoint:  server.Endpoint,
+		token:     server.Token,
+	}
+
+	var link *voiceLink
+	if link, err = voice.dial(state.SessionID, server.Endpoint, server.Token); err != nil {
+		return
+	}
+	voice.voiceLink = *link
+	voice.ready = true
+
+	go voice.opusSendLoop()
+	voice.startReceiving(link)
+	go voice.keepAlive(link)
+
+	r.Lock()
+	r.active[guildID] = voice
+	r.Unlock()
+
+	ret = voice
+	return
+}
+
+// dial establishes a new session with the voice server
+func (v *voiceImpl) dial(sessionID, endpoint, token string) (link *voiceLink, err error) {
+	link = &voiceLink{
+		done: make(chan struct{}),
 	}
 	// Defer a cleanup just in case
-	defer func(v *voiceImpl) {
-		if !v.ready {
-			if v.ws != nil {
-				_ = v.ws.Disconnect()
-			}
-			if v.udp != nil {
-				_ = v.udp.Close()
-			}
+	defer func() {
+		if err != nil {
+			_ = link.close()
+			link = nil
 		}
-	}(&voice)
+	}()
 
 	// Connect to the websocket
-	voice.ws, err = gateway.NewVoiceClient(&gateway.VoiceConfig{
-		GuildID:        server.GuildID,
-		UserID:         r.c.myID,
-		SessionID:      state.SessionID,
-		Token:          server.Token,
-		HTTPClient:     r.c.config.HTTPClient,
-		Endpoint:       "wss://" + strings.TrimSuffix(server.Endpoint, ":80") + "/?v=3",
-		Logger:         r.c.log,
-		SystemShutdown: r.c.shutdownChan,
+	current := link
+	link.ws, err = gateway.NewVoiceClient(&gateway.VoiceConfig{
+		GuildID:        v.guildID,
+		UserID:         v.c.botID(),
+		SessionID:      sessionID,
+		Token:          token,
+		HTTPClient:     v.c.config.HTTPClient,
+		Endpoint:       "wss://" + strings.TrimSuffix(endpoint, ":80") + "/?v=4",
+		Logger:         v.c.log,
+		SystemShutdown: v.c.shutdownChan,
+
+		OnSpeaking:         v.ssrcs.onSpeaking,
+		OnClientDisconnect: v.ssrcs.onClientDisconnect,
+		OnResuming: func() {
+			v.setState(VoiceConnectionResuming)
+		},
+		OnResumed: func() {
+			v.setState(VoiceConnectionConnected)
+		},
+		OnClose: func(code int, reason string) {
+			v.onClose(current.ws, code, reason)
+		},
 	})
 	if err != nil {
 		return
 	}
 
 	var ready *gateway.VoiceReady
-	if ready, err = voice.ws.Connect(); err != nil {
+	if ready, err = link.ws.Connect(); err != nil {
 		return
 	}
-	voice.ssrc = ready.SSRC
+	link.ssrc = ready.SSRC
 
 	// Connect to UDP
 	dialer := net.Dial
-	if r.c.config.Proxy != nil {
-		dialer = r.c.config.Proxy.Dial
+	if v.c.config.Proxy != nil {
+		dialer = v.c.config.Proxy.Dial
 	}
-	voice.udp, err = dialer("udp", ready.IP+":"+strconv.Itoa(ready.Port))
+	link.udp, err = dialer("udp", ready.IP+":"+strconv.Itoa(ready.Port))
 	if err != nil {
 		return
 	}
@@ -190,14 +428,14 @@ waiter:
 	// SendOpusFrame our SSRC with no further data for the IP discovery process.
 	ssrcBuffer := make([]byte, 70)
 	binary.BigEndian.PutUint32(ssrcBuffer, ready.SSRC)
-	_, err = voice.udp.Write(ssrcBuffer)
+	_, err = link.udp.Write(ssrcBuffer)
 	if err != nil {
 		return
 	}
 
 	ipBuffer := make([]byte, 70)
 	var n int
-	n, err = voice.udp.Read(ipBuffer)
+	n, err = link.udp.Read(ipBuffer)
 	if err != nil {
 		return
 	}
@@ -215,46 +453,63 @@ waiter:
 	ip := ipb[:nullPos]
 	port := binary.LittleEndian.Uint16(ipBuffer[68:70])
 
-	// Tell the websocket which encryption mode we want to use. We'll go with XSalsa20 and Poly1305 since that's what
-	// libSodium/NaCl and golang.org/x/crypto/nacl/secretbox use. If both Discord and Go both start supporting more
-	// modes "out of the box" we might want to consider implementing a "preferred mode selection" algorithm here.
+	// Tell the websocket which encryption mode we want to use. Every supported mode uses XSalsa20 and Poly1305,
+	// as implemented by golang.org/x/crypto/nacl/secretbox, and only differs in how the nonce is created.
+	var mode string
+	if mode, err = selectVoiceEncryptionMode(v.c.config.VoiceEncryptionModes, ready.Modes); err != nil {
+		return
+	}
 	var session *gateway.VoiceSessionDescription
-	session, err = voice.ws.SendUDPInfo(&gateway.VoiceSelectProtocolParams{
-		Mode:    "xsalsa20_poly1305",
+	session, err = link.ws.SendUDPInfo(&gateway.VoiceSelectProtocolParams{
+		Mode:    mode,
 		Address: ip,
 		Port:    port,
 	})
 	if err != nil {
 		return
 	}
-	if session.Mode != "xsalsa20_poly1305" {
+	if session.Mode != mode {
 		err = errors.New("discord selected mismatching encryption algorithm")
 		return
 	}
 
-	voice.secretKey = session.SecretKey
-	voice.ready = true
-
-	go voice.opusSendLoop()
-
-	ret = &voice
+	link.crypto, err = newVoiceCrypto(session.Mode, session.SecretKey)
 	return
 }
 
 func (r *voiceRepository) onVoiceStateUpdate(_ Session, event *VoiceStateUpdate) {
-	if event.UserID != r.c.myID {
+	if event.UserID != r.c.botID() {
 		return
 	}
 
 	r.Lock()
+	voice := r.active[event.VoiceState.GuildID]
 
 	if ch, exists := r.pendingStates[event.VoiceState.GuildID]; exists {
 		delete(r.pendingStates, event.VoiceState.GuildID)
 		r.Unlock()
 
+		if voice != nil {
+			voice.onVoiceStateUpdate(event)
+		}
 		ch <- event
 	} else {
 		r.Unlock()
+
+		if voice == nil {
+			return
+		}
+		if event.ChannelID.IsZero() {
+			// the bot left the voice channel, eg. it was kicked or the channel was deleted
+			r.c.log.Info("bot left the voice channel of guild ", voice.guildID, ", closing the voice connection")
+			go func() {
+				if err := voice.shutdown(); err != nil {
+					r.c.log.Debug(err)
+				}
+			}()
+			return
+		}
+		voice.onVoiceStateUpdate(event)
 	}
 }
 
@@ -262,15 +517,227 @@ func (r *voiceRepository) onVoiceServerUpdate(_ Session, event *VoiceServerUpdat
 	r.Lock()
 
 	if ch, exists := r.pendingServers[event.GuildID]; exists {
-		delete(r.pendingStates, event.GuildID)
+		delete(r.pendingServers, event.GuildID)
 		r.Unlock()
 
 		ch <- event
+	} else if voice, exists := r.active[event.GuildID]; exists {
+		r.Unlock()
+
+		// the voice server changed, eg. a region change or the bot was moved
+		go voice.reconnect(event.Endpoint, event.Token)
 	} else {
 		r.Unlock()
 	}
 }
 
+func (v *voiceImpl) ChannelID() Snowflake {
+	v.Lock()
+	defer v.Unlock()
+	return v.channelID
+}
+
+func (v *voiceImpl) onVoiceStateUpdate(event *VoiceStateUpdate) {
+	v.Lock()
+	v.sessionID = event.SessionID
+	v.channelID = event.ChannelID
+	v.Unlock()
+
+	if event.ChannelID.IsZero() {
+		v.setState(VoiceConnectionDisconnected)
+	}
+}
+
+// onClose handles the voice close codes of the websocket. A session that can not be resumed
+// is replaced by a new session.
+func (v *voiceImpl) onClose(ws *gateway.VoiceClient, code int, reason string) {
+	v.c.log.Debug("voice connection for guild ", v.guildID, " was closed by discord: ", code, " ", reason)
+
+	switch code {
+	case gateway.VoiceCloseDisconnected:
+		// kicked, the channel was deleted or the bot was moved to another voice server. Discord
+		// sends a voice server update when the bot can reconnect
+		v.setState(VoiceConnectionDisconnected)
+	case gateway.VoiceCloseSessionNoLongerValid, gateway.VoiceCloseSessionTimeout, gateway.VoiceCloseServerNotFound:
+		v.Lock()
+		current := v.ws == ws
+		endpoint, token := v.endpoint, v.token
+		v.Unlock()
+		if current {
+			go v.reconnect(endpoint, token)
+		}
+	default:
+		// the session is resumed by the voice client
+	}
+}
+
+// reconnect replaces the current session with a new session on the given voice server
+func (v *voiceImpl) reconnect(endpoint, token string) {
+	v.Lock()
+	if !v.ready || v.closed || v.reconnecting {
+		v.Unlock()
+		return
+	}
+	v.reconnecting = true
+	v.endpoint, v.token = endpoint, token
+	sessionID := v.sessionID
+	old := v.voiceLink
+	v.voiceLink = voiceLink{}
+	v.Unlock()
+	defer func() {
+		v.Lock()
+		v.reconnecting = false
+		v.Unlock()
+	}()
+
+	_ = old.close()
+	if endpoint == "" {
+		// Discord is allocating a new voice server, and sends a new update once it is available
+		v.setState(VoiceConnectionDisconnected)
+		return
+	}
+	v.setState(VoiceConnectionReconnecting)
+
+	var link *voiceLink
+	var err error
+	const attempts = 3
+	delay := time.Second
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if link, err = v.dial(sessionID, endpoint, token); err == nil {
+			break
+		}
+		v.c.log.Info("unable to reconnect voice connection for guild ", v.guildID, ": ", err)
+		if attempt == attempts {
+			break
+		}
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-v.close:
+			return
+		}
+	}
+	if err != nil {
+		v.setState(VoiceConnectionDisconnected)
+		return
+	}
+
+	// the receivers are started under the lock, such that a concurrent shutdown either sees them when
+	// waiting for them, or is seen here and the link is discarded
+	v.Lock()
+	if !v.ready || v.closed {
+		v.Unlock()
+		_ = link.close()
+		return
+	}
+	v.voiceLink = *link
+	v.startReceiving(link)
+	go v.keepAlive(link)
+	v.Unlock()
+
+	v.setState(VoiceConnectionConnected)
+}
+
+// setSelfState updates the self deaf and self mute flags sent on voice state updates, and reports if
+// they changed.
+func (v *voiceImpl) setSelfState(selfDeaf, selfMute bool) (changed bool) {
+	v.Lock()
+	defer v.Unlock()
+	changed = v.selfDeaf != selfDeaf || v.selfMute != selfMute
+	v.selfDeaf, v.selfMute = selfDeaf, selfMute
+	return changed
+}
+
+// updateSelfState tells Discord about the self deaf and self mute flags, without changing channel
+func (v *voiceImpl) updateSelfState() error {
+	v.Lock()
+	payload := &UpdateVoiceStatePayload{
+		GuildID:   v.guildID,
+		ChannelID: v.channelID,
+		SelfDeaf:  v.selfDeaf,
+		SelfMute:  v.selfMute,
+	}
+	v.Unlock()
+
+	_, err := v.c.Emit(UpdateVoiceState, payload)
+	return err
+}
+
+func (v *voiceImpl) MoveTo(channelID Snowflake) error {
+	if channelID.IsZero() {
+		return errors.New("channelID must be set to move to a voice channel, use Close to disconnect")
+	}
+
+	v.Lock()
+	if !v.ready {
+		v.Unlock()
+		panic("Attempting to interact with a closed voice connection")
+	}
+	selfDeaf, selfMute := v.selfDeaf, v.selfMute
+	v.Unlock()
+
+	r := v.c.voiceRepository
+	stateCh := make(chan *VoiceStateUpdate, 1)
+	r.Lock()
+	r.pendingStates[v.guildID] = stateCh
+	r.Unlock()
+	defer func() {
+		r.Lock()
+		if r.pendingStates[v.guildID] == stateCh {
+			delete(r.pendingStates, v.guildID)
+		}
+		r.Unlock()
+	}()
+
+	_, err := v.c.Emit(UpdateVoiceState, &UpdateVoiceStatePayload{
+		GuildID:   v.guildID,
+		ChannelID: channelID,
+		SelfDeaf:  selfDeaf,
+		SelfMute:  selfMute,
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case state := <-stateCh:
+		if state.ChannelID != channelID {
+			return errors.New("discord did not move the bot to channel " + channelID.String())
+		}
+		return nil
+	case <-time.After(10 * time.Second):
+		return errors.New("timeout on receiving voice channel information from discord")
+	}
+}
+
+func (v *voiceImpl) State() VoiceConnectionState {
+	v.stateMu.Lock()
+	defer v.stateMu.Unlock()
+	return v.state
+}
+
+func (v *voiceImpl) OnStateChange(handler func(state VoiceConnectionState)) {
+	v.stateMu.Lock()
+	defer v.stateMu.Unlock()
+	v.stateHandlers = append(v.stateHandlers, handler)
+}
+
+func (v *voiceImpl) setState(state VoiceConnectionState) {
+	v.stateMu.Lock()
+	if v.state == state || v.state == VoiceConnectionClosed {
+		v.stateMu.Unlock()
+		return
+	}
+	v.state = state
+	handlers := v.stateHandlers
+	v.stateMu.Unlock()
+
+	for _, handler := range handlers {
+		handler(state)
+	}
+}
+
 func (v *voiceImpl) StartSpeaking() error {
 	return v.speakingImpl(true)
 }
@@ -284,83 +751,130 @@ func (v *voiceImpl) speakingImpl(b bool) error {
 	defer v.Unlock()
 
 	if !v.ready {
-		panic("Attempting to interact with a closed voice connection")
+		return errors.New("voice connection was closed")
+	}
+	if v.ws == nil {
+		return errors.New("voice connection is " + v.State().String())
 	}
 
+	// since v4 speaking is a bitfield, where 1 is the microphone
+	var speaking uint
+	if b {
+		speaking = voiceSpeakingMicrophone
+	}
 	return v.ws.Emit(cmd.VoiceSpeaking, &voiceSpeakingData{
-		Speaking: b,
+		Speaking: speaking,
 		SSRC:     v.ssrc,
 	})
 }
 
+// isReady reports whether the connection is still usable, see Close
+func (v *voiceImpl) isReady() bool {
+	v.Lock()
+	defer v.Unlock()
+	return v.ready
+}
+
 func (v *voiceImpl) SendOpusFrame(data []byte) {
-	if !v.ready {
-		panic("Attempting to send to a closed voice connection")
+	// frames sent after Close are discarded, eg. by a Player that is still stopping
+	select {
+	case <-v.close:
+		return
+	default:
+	}
+
+	select {
+	case v.send <- data:
+	case <-v.close:
+		// the bot left the voice channel, the frame is discarded
 	}
-	v.send <- data
 }
 
 func (v *voiceImpl) SendDCA(r io.Reader) error {
-	if !v.ready {
+	if !v.isReady() {
 		panic("Attempting to send to a closed voice connection")
 	}
 
-	var sampleSize uint16
+	source := NewDCASource(r)
 	for {
-		if err := binary.Read(r, binary.LittleEndian, &sampleSize); err != nil {
+		frame, err := source.ReadFrame()
+		if err != nil {
 			if err == io.EOF {
 				return nil
 			}
 			return err
 		}
 
-		buf := make([]byte, sampleSize)
-		if _, err := io.ReadFull(r, buf); err != nil {
-			panic(err)
+		select {
+		case v.send <- frame:
+		case <-v.close:
+			return errors.New("voice connection was closed")
 		}
-
-		v.send <- buf
 	}
 }
 
 func (v *voiceImpl) Close() (err error) {
 	v.Lock()
-	defer v.Unlock()
-
 	if !v.ready {
+		v.Unlock()
 		panic("Attempting to close a closed Voice Connection")
 	}
+	v.ready = false
+	left := v.closed
+	v.Unlock()
+
+	// the send loop stops once the connection is shut down, so v.send is never closed as
+	// frames might still be sent concurrently. See SendOpusFrame
+	err = v.shutdown()
+	if !left {
+		v.leave()
+	}
+	return err
+}
 
-	// Tell Discord we want to disconnect from channel/guild
+// leave tells Discord that the bot disconnects from the voice channel
+func (v *voiceImpl) leave() {
 	_, _ = v.c.Emit(UpdateVoiceState, &UpdateVoiceStatePayload{
 		GuildID:   v.guildID,
 		ChannelID: 0, // disconnect "code/value" (disgord implementation specific)
 		SelfDeaf:  true,
 		SelfMute:  true,
 	})
+}
 
-	close(v.close)
-	close(v.send)
-	err1 := v.udp.Close()
-	err2 := v.ws.Disconnect()
-
-	if err1 != nil || err2 != nil {
-		var errMsg string
-		if err1 != nil {
-			errMsg += err1.Error()
-		}
-		if err2 != nil {
-			errMsg += err2.Error()
-		}
+// shutdown tears down the connection and removes it from the active connections. Unlike Close, the
+// connection is still usable, but sent frames are discarded. See VoiceConnection.Close
+func (v *voiceImpl) shutdown() (err error) {
+	v.Lock()
+	if v.closed {
+		v.Unlock()
+		return nil
+	}
+	v.closed = true
 
-		return errors.New(errMsg)
+	r := v.c.voiceRepository
+	r.Lock()
+	if r.active[v.guildID] == v {
+		delete(r.active, v.guildID)
 	}
+	r.Unlock()
 
-	return nil
+	close(v.close)
+	err = v.voiceLink.close()
+	v.voiceLink = voiceLink{}
+	v.Unlock()
+
+	v.receivers.Wait()
+	close(v.receive)
+
+	v.setState(VoiceConnectionClosed)
+	return err
 }
 
+const voiceSpeakingMicrophone = 1 << 0
+
 type voiceSpeakingData struct {
-	Speaking bool   `json:"speaking"`
+	Speaking uint   `json:"speaking"`
 	Delay    int    `json:"delay"`
 	SSRC     uint32 `json:"ssrc"`
 }
@@ -370,27 +884,32 @@ func (v *voiceImpl) opusSendLoop() {
 	header := make([]byte, 12)
 	header[0] = 0x80
 	header[1] = 0x78
-	binary.BigEndian.PutUint32(header[8:12], v.ssrc)
 
 	var (
 		sequence  uint16
 		timestamp uint32
-		nonce     [24]byte
 
 		msg  []byte
 		open bool
-	)
+		idle bool
 
-	frequency := time.NewTicker(time.Millisecond * 20) // 50 sends per sec, 960 samples each at 48kHz
-	defer frequency.Stop()
+		failing bool
+	)
 
+	pacer := newVoicePacer(v.clock) // 50 sends per sec, 960 samples each at 48kHz
 	for {
 		select {
 		case msg, open = <-v.send:
-			if !open {
+			idle = false
+		default:
+			idle = true
+			select {
+			case msg, open = <-v.send:
+			case <-v.close:
 				return
 			}
-		case <-v.close:
+		}
+		if !open {
 			return
 		}
 
@@ -400,16 +919,42 @@ func (v *voiceImpl) opusSendLoop() {
 		binary.BigEndian.PutUint32(header[4:8], timestamp)
 		timestamp += 960 // samples
 
-		copy(nonce[:], header)
+		// the link is replaced on reconnects
+		v.Lock()
+		udp, crypto := v.udp, v.crypto
+		binary.BigEndian.PutUint32(header[8:12], v.ssrc)
+		v.Unlock()
 
-		toSend := secretbox.Seal(header, msg, &nonce, &v.secretKey)
-		select {
-		case <-frequency.C:
-		case <-v.close:
+		switch pacer.wait(v.close, idle) {
+		case pacerClosed:
 			return
+		case pacerDrop:
+			v.stats.dropped.Inc()
+			continue
+		case pacerSendLate:
+			v.stats.late.Inc()
+		}
+		if udp == nil {
+			v.stats.dropped.Inc()
+			continue // not connected
 		}
 
-		_, _ = v.udp.Write(toSend)
-		// err on udp write? hahahahahah... hahah.. good joke.
+		toSend, err := crypto.seal(header, msg)
+		if err == nil {
+			_, err = udp.Write(toSend)
+		}
+		if err != nil {
+			v.stats.sendErrors.Inc()
+			if !failing {
+				v.c.log.Error("unable to send voice frame for guild ", v.guildID, ": ", err)
+			}
+			failing = true
+			continue
+		}
+		if failing {
+			v.c.log.Info("voice frames for guild ", v.guildID, " are sent again")
+		}
+		failing = false
+		v.stats.sent.Inc()
 	}
 }
@@ -0,0 +1,127 @@
+package disgord
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// Voice encryption modes, see https://discordapp.com/developers/docs/topics/voice-connections#establishing-a-voice-udp-connection
+const (
+	// VoiceModeXSalsa20Poly1305 uses the RTP header as nonce
+	VoiceModeXSalsa20Poly1305 = "xsalsa20_poly1305"
+	// VoiceModeXSalsa20Poly1305Suffix appends a random 24 byte nonce to every packet
+	VoiceModeXSalsa20Poly1305Suffix = "xsalsa20_poly1305_suffix"
+	// VoiceModeXSalsa20Poly1305Lite appends an incrementing 4 byte nonce to every packet
+	VoiceModeXSalsa20Poly1305Lite = "xsalsa20_poly1305_lite"
+)
+
+// DefaultVoiceEncryptionModes is the preferred order of the voice encryption modes, see Config.VoiceEncryptionModes
+var DefaultVoiceEncryptionModes = []string{
+	VoiceModeXSalsa20Poly1305Lite,
+	VoiceModeXSalsa20Poly1305Suffix,
+	VoiceModeXSalsa20Poly1305,
+}
+
+const (
+	voiceSuffixNonceSize = 24
+	voiceLiteNonceSize   = 4
+)
+
+// selectVoiceEncryptionMode picks the first preferred mode that is offered by Discord in the voice READY payload
+func selectVoiceEncryptionMode(preferred, offered []string) (string, error) {
+	if len(preferred) == 0 {
+		preferred = DefaultVoiceEncryptionModes
+	}
+	for _, mode := range preferred {
+		for i := range offered {
+			if offered[i] == mode {
+				return mode, nil
+			}
+		}
+	}
+	return "", errors.New("no supported voice encryption mode, discord offered: " + strings.Join(offered, ", "))
+}
+
+// voiceCrypto encrypts and decrypts the RTP payloads of the negotiated encryption mode
+type voiceCrypto struct {
+	mode      string
+	secretKey [32]byte
+
+	// liteNonce is incremented for every sent packet, see VoiceModeXSalsa20Poly1305Lite.
+	// Only accessed by the send loop.
+	liteNonce uint32
+
+	// random creates the nonces of VoiceModeXSalsa20Poly1305Suffix. Defaults to crypto/rand.
+	random io.Reader
+}
+
+func newVoiceCrypto(mode string, secretKey [32]byte) (*voiceCrypto, error) {
+	switch mode {
+	case VoiceModeXSalsa20Poly1305, VoiceModeXSalsa20Poly1305Suffix, VoiceModeXSalsa20Poly1305Lite:
+	default:
+		return nil, errors.New("unsupported voice encryption mode: " + mode)
+	}
+	return &voiceCrypto{
+		mode:      mode,
+		secretKey: secretKey,
+		random:    rand.Reader,
+	}, nil
+}
+
+// seal encrypts the opus frame and appends it to the RTP header, followed by the nonce for the suffix
+// and lite modes. The header must be the 12 bytes RTP header.
+func (c *voiceCrypto) seal(header, opus []byte) ([]byte, error) {
+	var nonce [24]byte
+	switch c.mode {
+	case VoiceModeXSalsa20Poly1305Suffix:
+		if _, err := io.ReadFull(c.random, nonce[:]); err != nil {
+			return nil, err
+		}
+		packet := secretbox.Seal(header, opus, &nonce, &c.secretKey)
+		return append(packet, nonce[:]...), nil
+	case VoiceModeXSalsa20Poly1305Lite:
+		binary.BigEndian.PutUint32(nonce[:voiceLiteNonceSize], c.liteNonce)
+		c.liteNonce++
+		packet := secretbox.Seal(header, opus, &nonce, &c.secretKey)
+		return append(packet, nonce[:voiceLiteNonceSize]...), nil
+	default:
+		copy(nonce[:], header[:rtpHeaderSize])
+		return secretbox.Seal(header, opus, &nonce, &c.secretKey), nil
+	}
+}
+
+// open decrypts the payload of the RTP packet, that starts after the header of the given size
+func (c *voiceCrypto) open(packet []byte, headerSize int) ([]byte, error) {
+	var nonce [24]byte
+	payload := packet[headerSize:]
+	switch c.mode {
+	case VoiceModeXSalsa20Poly1305Suffix:
+		if len(payload) < voiceSuffixNonceSize {
+			return nil, errors.New("voice packet is too short")
+		}
+		copy(nonce[:], payload[len(payload)-voiceSuffixNonceSize:])
+		payload = payload[:len(payload)-voiceSuffixNonceSize]
+	case VoiceModeXSalsa20Poly1305Lite:
+		if len(payload) < voiceLiteNonceSize {
+			return nil, errors.New("voice packet is too short")
+		}
+		copy(nonce[:], payload[len(payload)-voiceLiteNonceSize:])
+		payload = payload[:len(payload)-voiceLiteNonceSize]
+	default:
+		copy(nonce[:], packet[:rtpHeaderSize])
+	}
+
+	if len(payload) < secretbox.Overhead {
+		return nil, errors.New("voice packet is too short")
+	}
+	opus, ok := secretbox.Open(nil, payload, &nonce, &c.secretKey)
+	if !ok {
+		return nil, errors.New("unable to decrypt voice packet")
+	}
+	return opus, nil
+}
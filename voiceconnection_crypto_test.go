@@ -0,0 +1,89 @@
+package disgord
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestSelectVoiceEncryptionMode(t *testing.T) {
+	offered := []string{VoiceModeXSalsa20Poly1305, VoiceModeXSalsa20Poly1305Suffix, "aead_aes256_gcm"}
+
+	if mode, err := selectVoiceEncryptionMode(nil, offered); err != nil || mode != VoiceModeXSalsa20Poly1305Suffix {
+		t.Errorf("expected the default preference to pick %s, got %s, %v", VoiceModeXSalsa20Poly1305Suffix, mode, err)
+	}
+	if mode, err := selectVoiceEncryptionMode([]string{VoiceModeXSalsa20Poly1305}, offered); err != nil || mode != VoiceModeXSalsa20Poly1305 {
+		t.Errorf("expected the preferred mode, got %s, %v", mode, err)
+	}
+	if _, err := selectVoiceEncryptionMode(nil, []string{"aead_aes256_gcm"}); err == nil {
+		t.Error("expected an error when no supported mode is offered")
+	}
+}
+
+func TestVoiceCrypto(t *testing.T) {
+	var key [32]byte
+	for i := range key {
+		key[i] = byte(i)
+	}
+	header := []byte{0x80, 0x78, 0x00, 0x01, 0x00, 0x00, 0x03, 0xC0, 0x00, 0x00, 0x00, 0x2A}
+	opus := []byte{0xF8, 0xFF, 0xFE}
+
+	testCases := []struct {
+		mode    string
+		expects string
+	}{
+		{VoiceModeXSalsa20Poly1305, "80780001000003c00000002af846f016e98d89b23105f8ba30b246bbcddfbf"},
+		{VoiceModeXSalsa20Poly1305Suffix, "80780001000003c00000002a2b8623274e4af9bc84223dc39cd912fbadb232" + "abababababababababababababababababababababababab"},
+		{VoiceModeXSalsa20Poly1305Lite, "80780001000003c00000002abff00585cef9870c659e228523c536018e9667" + "00000007"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.mode, func(t *testing.T) {
+			crypto, err := newVoiceCrypto(tc.mode, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			crypto.random = bytes.NewReader(bytes.Repeat([]byte{0xAB}, 24))
+			crypto.liteNonce = 7
+
+			packet, err := crypto.seal(append([]byte(nil), header...), opus)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(packet); got != tc.expects {
+				t.Errorf("expected packet %s, got %s", tc.expects, got)
+			}
+
+			expects, _ := hex.DecodeString(tc.expects)
+			decrypted, err := crypto.open(expects, rtpHeaderSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(decrypted, opus) {
+				t.Errorf("expected %v, got %v", opus, decrypted)
+			}
+
+			expects[rtpHeaderSize] ^= 0xFF
+			if _, err = crypto.open(expects, rtpHeaderSize); err == nil {
+				t.Error("expected a modified packet to be rejected")
+			}
+		})
+	}
+
+	if _, err := newVoiceCrypto("aead_aes256_gcm", key); err == nil {
+		t.Error("expected unsupported modes to fail")
+	}
+}
+
+func TestVoiceCrypto_LiteNonce(t *testing.T) {
+	crypto, _ := newVoiceCrypto(VoiceModeXSalsa20Poly1305Lite, [32]byte{})
+	header := make([]byte, rtpHeaderSize)
+	first, _ := crypto.seal(append([]byte(nil), header...), []byte{1})
+	second, _ := crypto.seal(append([]byte(nil), header...), []byte{1})
+	if bytes.Equal(first, second) {
+		t.Error("expected the nonce to change between packets")
+	}
+	if !bytes.Equal(second[len(second)-4:], []byte{0, 0, 0, 1}) {
+		t.Errorf("expected the incremented nonce, got %v", second[len(second)-4:])
+	}
+}
@@ -0,0 +1,77 @@
+package disgord
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+const (
+	// voiceKeepAliveInterval is how often a keepalive packet is sent over UDP, which keeps the
+	// NAT binding open while no audio is sent, eg. when the player is paused
+	voiceKeepAliveInterval = 5 * time.Second
+
+	// voiceMaxKeepAliveFailures is the number of keepalive packets in a row that could not be sent,
+	// before the UDP connection is considered dead and the session is re-established
+	voiceMaxKeepAliveFailures = 3
+)
+
+// keepAlive sends a keepalive packet with an incrementing counter, until the link is replaced or closed
+func (v *voiceImpl) keepAlive(link *voiceLink) {
+	clock := v.clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	packet := make([]byte, 8)
+	var (
+		counter  uint64
+		failures int
+	)
+	for {
+		select {
+		case <-clock.After(voiceKeepAliveInterval):
+		case <-link.done:
+			return
+		case <-v.close:
+			return
+		}
+
+		binary.LittleEndian.PutUint64(packet, counter)
+		counter++
+		if _, err := link.udp.Write(packet); err != nil {
+			failures++
+			v.c.log.Debug("unable to send voice keepalive for guild ", v.guildID, ": ", err)
+			if failures >= voiceMaxKeepAliveFailures {
+				v.udpPathDead(link, err)
+				return
+			}
+			continue
+		}
+		failures = 0
+	}
+}
+
+// udpPathDead re-establishes the session when the UDP connection of the current link stopped working
+func (v *voiceImpl) udpPathDead(link *voiceLink, err error) {
+	v.Lock()
+	current := v.udp == link.udp
+	endpoint, token := v.endpoint, v.token
+	v.Unlock()
+	if !current {
+		return
+	}
+
+	v.c.log.Info("voice connection for guild ", v.guildID, " lost the UDP connection, reconnecting: ", err)
+	go v.reconnect(endpoint, token)
+}
+
+func (v *voiceImpl) Latency() (time.Duration, error) {
+	v.Lock()
+	ws := v.ws
+	v.Unlock()
+	if ws == nil {
+		return 0, errors.New("voice connection is " + v.State().String())
+	}
+	return ws.HeartbeatLatency()
+}
@@ -0,0 +1,44 @@
+package disgord
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestVoiceImpl_keepAlive(t *testing.T) {
+	start := time.Unix(1546300800, 0)
+	udp := &fakeVoiceUDP{
+		clock:  &fakeVoiceClock{now: start},
+		errors: map[int]bool{2: true, 4: true, 5: true, 6: true},
+	}
+	v := &voiceImpl{
+		voiceLink: voiceLink{
+			udp:  udp,
+			done: make(chan struct{}),
+		},
+		close: make(chan struct{}),
+		clock: udp.clock,
+		c:     New(Config{BotToken: "testing", DisableCache: true}),
+	}
+
+	// returns once the UDP connection is considered dead. The connection is not ready, so
+	// it is not re-established
+	v.keepAlive(&v.voiceLink)
+
+	if udp.writes != 6 {
+		t.Errorf("expected the connection to be considered dead after 3 failures in a row, got %d writes", udp.writes)
+	}
+	for i, packet := range udp.packets {
+		if counter := binary.LittleEndian.Uint64(packet); len(packet) != 8 || counter != []uint64{0, 2}[i] {
+			t.Errorf("unexpected keepalive packet %v", packet)
+		}
+		if expects := start.Add(time.Duration(2*i+1) * voiceKeepAliveInterval); !udp.times[i].Equal(expects) {
+			t.Errorf("keepalive %d was sent at %s, expected %s", i, udp.times[i].Sub(start), expects.Sub(start))
+		}
+	}
+
+	if _, err := v.Latency(); err == nil {
+		t.Error("expected no latency without a voice websocket")
+	}
+}
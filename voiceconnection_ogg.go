@@ -0,0 +1,388 @@
+package disgord
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+)
+
+//////////////////////////////////////////////////////
+//
+// Ogg Opus: Reads and writes Opus packets in an Ogg container, eg. .opus files.
+//
+// See https://tools.ietf.org/html/rfc3533 for the Ogg container and
+// https://tools.ietf.org/html/rfc7845 for the Opus mapping.
+//
+//////////////////////////////////////////////////////
+
+const (
+	oggPageHeaderSize = 27
+	oggMaxSegments    = 255
+
+	oggFlagContinued = 0x01
+	oggFlagBOS       = 0x02 // beginning of stream
+	oggFlagEOS       = 0x04 // end of stream
+
+	// oggOpusSampleRate is the granule position rate of Ogg Opus, regardless of the input sample rate
+	oggOpusSampleRate = 48000
+	// oggOpusFrameSamples is the number of samples of a 20ms frame, as sent to Discord
+	oggOpusFrameSamples = 960
+)
+
+var (
+	oggCapturePattern = []byte("OggS")
+	opusHeadMagic     = []byte("OpusHead")
+	opusTagsMagic     = []byte("OpusTags")
+)
+
+var oggCRCTable = func() (table [256]uint32) {
+	// CRC-32 with the polynomial 0x04c11db7, without reflection. Not the same as hash/crc32.
+	for i := range table {
+		r := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if r&0x80000000 != 0 {
+				r = (r << 1) ^ 0x04c11db7
+			} else {
+				r <<= 1
+			}
+		}
+		table[i] = r
+	}
+	return
+}()
+
+func oggCRC(crc uint32, data []byte) uint32 {
+	for _, b := range data {
+		crc = (crc << 8) ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	return crc
+}
+
+// OpusHead is the identification header of an Ogg Opus stream
+type OpusHead struct {
+	Version         uint8
+	Channels        uint8
+	PreSkip         uint16
+	InputSampleRate uint32
+	OutputGain      int16
+	ChannelMapping  uint8
+}
+
+func (h *OpusHead) unmarshal(packet []byte) error {
+	if len(packet) < 19 || !bytes.Equal(packet[:8], opusHeadMagic) {
+		return errors.New("not an Ogg Opus stream, missing OpusHead")
+	}
+	h.Version = packet[8]
+	h.Channels = packet[9]
+	h.PreSkip = binary.LittleEndian.Uint16(packet[10:12])
+	h.InputSampleRate = binary.LittleEndian.Uint32(packet[12:16])
+	h.OutputGain = int16(binary.LittleEndian.Uint16(packet[16:18]))
+	h.ChannelMapping = packet[18]
+	return nil
+}
+
+func (h *OpusHead) marshal() []byte {
+	packet := make([]byte, 19)
+	copy(packet, opusHeadMagic)
+	packet[8] = h.Version
+	packet[9] = h.Channels
+	binary.LittleEndian.PutUint16(packet[10:12], h.PreSkip)
+	binary.LittleEndian.PutUint32(packet[12:16], h.InputSampleRate)
+	binary.LittleEndian.PutUint16(packet[16:18], uint16(h.OutputGain))
+	packet[18] = h.ChannelMapping
+	return packet
+}
+
+type oggPage struct {
+	flags   byte
+	granule int64
+	serial  uint32
+	lacing  []byte
+	body    []byte
+}
+
+// OggOpusReader reads the Opus packets of an Ogg Opus stream. Only the first logical stream is read,
+// unless the streams are chained, in which case every stream is read in order.
+type OggOpusReader struct {
+	r    io.Reader
+	head *OpusHead
+
+	serial    uint32
+	inStream  bool
+	nrPackets int // of the current logical stream
+
+	page    *oggPage
+	segment int
+	partial []byte
+}
+
+// NewOggOpusReader creates a reader for Ogg Opus streams, eg. an .opus file.
+func NewOggOpusReader(r io.Reader) *OggOpusReader {
+	return &OggOpusReader{r: r}
+}
+
+// Head returns the identification header of the current stream. Nil until the first packet was read.
+func (o *OggOpusReader) Head() *OpusHead {
+	return o.head
+}
+
+// ReadPacket returns the next Opus packet, skipping the OpusHead and OpusTags headers.
+// io.EOF is returned at the end of the stream.
+func (o *OggOpusReader) ReadPacket() ([]byte, error) {
+	for {
+		packet, err := o.nextPacket()
+		if err != nil {
+			return nil, err
+		}
+
+		o.nrPackets++
+		switch o.nrPackets {
+		case 1:
+			head := &OpusHead{}
+			if err = head.unmarshal(packet); err != nil {
+				return nil, err
+			}
+			o.head = head
+		case 2:
+			if !bytes.HasPrefix(packet, opusTagsMagic) {
+				return nil, errors.New("invalid Ogg Opus stream, missing OpusTags")
+			}
+		default:
+			return packet, nil
+		}
+	}
+}
+
+// nextPacket assembles the next packet of the logical stream from the page segments
+func (o *OggOpusReader) nextPacket() ([]byte, error) {
+	for {
+		if o.page == nil || o.segment >= len(o.page.lacing) {
+			if err := o.nextPage(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		page := o.page
+		var size int
+		start := o.segment
+		for ; o.segment < len(page.lacing); o.segment++ {
+			size += int(page.lacing[o.segment])
+			if page.lacing[o.segment] < 255 {
+				o.segment++
+				break
+			}
+		}
+
+		offset := 0
+		for _, l := range page.lacing[:start] {
+			offset += int(l)
+		}
+		o.partial = append(o.partial, page.body[offset:offset+size]...)
+		if page.lacing[o.segment-1] == 255 {
+			continue // the packet continues on the next page
+		}
+
+		packet := o.partial
+		o.partial = nil
+		return packet, nil
+	}
+}
+
+func (o *OggOpusReader) nextPage() error {
+	for {
+		page, err := readOggPage(o.r)
+		if err != nil {
+			if err == io.EOF && (o.inStream || o.partial != nil) {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+
+		if !o.inStream {
+			if page.flags&oggFlagBOS == 0 {
+				continue // not the start of a logical stream
+			}
+			o.inStream = true
+			o.serial = page.serial
+			o.nrPackets = 0
+			o.partial = nil
+		} else if page.serial != o.serial {
+			continue // multiplexed stream
+		}
+
+		if page.flags&oggFlagContinued == 0 && o.partial != nil {
+			return errors.New("invalid Ogg stream, packet was not continued")
+		}
+		if page.flags&oggFlagEOS != 0 {
+			o.inStream = false
+		}
+
+		o.page = page
+		o.segment = 0
+		return nil
+	}
+}
+
+func readOggPage(r io.Reader) (*oggPage, error) {
+	header := make([]byte, oggPageHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("invalid Ogg stream, truncated page header")
+		}
+		return nil, err
+	}
+	if !bytes.Equal(header[:4], oggCapturePattern) {
+		return nil, errors.New("invalid Ogg stream, missing capture pattern")
+	}
+	if header[4] != 0 {
+		return nil, errors.New("unsupported Ogg stream version")
+	}
+
+	page := &oggPage{
+		flags:   header[5],
+		granule: int64(binary.LittleEndian.Uint64(header[6:14])),
+		serial:  binary.LittleEndian.Uint32(header[14:18]),
+		lacing:  make([]byte, header[26]),
+	}
+	if _, err := io.ReadFull(r, page.lacing); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	var size int
+	for _, l := range page.lacing {
+		size += int(l)
+	}
+	page.body = make([]byte, size)
+	if _, err := io.ReadFull(r, page.body); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	checksum := binary.LittleEndian.Uint32(header[22:26])
+	binary.LittleEndian.PutUint32(header[22:26], 0)
+	crc := oggCRC(0, header)
+	crc = oggCRC(crc, page.lacing)
+	crc = oggCRC(crc, page.body)
+	if crc != checksum {
+		return nil, errors.New("invalid Ogg stream, page checksum mismatch")
+	}
+	return page, nil
+}
+
+// OggOpusWriter writes Opus packets to an Ogg Opus stream, eg. to store the audio received from
+// a VoiceConnection as an .opus file. Every packet is expected to hold 20ms of audio.
+type OggOpusWriter struct {
+	w        io.Writer
+	serial   uint32
+	sequence uint32
+	granule  int64
+	closed   bool
+}
+
+// NewOggOpusWriter writes the OpusHead and OpusTags headers, and returns a writer for the packets.
+// Discord sends stereo audio, so channels should usually be 2.
+func NewOggOpusWriter(w io.Writer, channels uint8) (*OggOpusWriter, error) {
+	o := &OggOpusWriter{
+		w:      w,
+		serial: rand.Uint32(),
+	}
+
+	head := &OpusHead{
+		Version:         1,
+		Channels:        channels,
+		PreSkip:         0,
+		InputSampleRate: oggOpusSampleRate,
+	}
+	if err := o.writePage(head.marshal(), oggFlagBOS); err != nil {
+		return nil, err
+	}
+
+	vendor := "disgord"
+	tags := make([]byte, 0, len(opusTagsMagic)+4+len(vendor)+4)
+	tags = append(tags, opusTagsMagic...)
+	tags = append(tags, byte(len(vendor)), 0, 0, 0)
+	tags = append(tags, vendor...)
+	tags = append(tags, 0, 0, 0, 0) // no user comments
+	if err := o.writePage(tags, 0); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// WritePacket writes a single Opus packet of 20ms to a new page.
+func (o *OggOpusWriter) WritePacket(packet []byte) error {
+	if o.closed {
+		return errors.New("ogg opus writer is closed")
+	}
+	if len(packet) >= oggMaxSegments*255 {
+		return errors.New("opus packet is too large for a single Ogg page")
+	}
+	o.granule += oggOpusFrameSamples
+	return o.writePage(packet, 0)
+}
+
+// Close ends the stream. The underlying writer is not closed.
+func (o *OggOpusWriter) Close() error {
+	if o.closed {
+		return nil
+	}
+	o.closed = true
+	return o.writePage(nil, oggFlagEOS)
+}
+
+func (o *OggOpusWriter) writePage(packet []byte, flags byte) error {
+	lacing := make([]byte, 0, len(packet)/255+1)
+	for size := len(packet); ; size -= 255 {
+		if size < 255 {
+			if packet != nil {
+				lacing = append(lacing, byte(size))
+			}
+			break
+		}
+		lacing = append(lacing, 255)
+	}
+
+	page := make([]byte, oggPageHeaderSize, oggPageHeaderSize+len(lacing)+len(packet))
+	copy(page, oggCapturePattern)
+	page[5] = flags
+	binary.LittleEndian.PutUint64(page[6:14], uint64(o.granule))
+	binary.LittleEndian.PutUint32(page[14:18], o.serial)
+	binary.LittleEndian.PutUint32(page[18:22], o.sequence)
+	page[26] = byte(len(lacing))
+	page = append(page, lacing...)
+	page = append(page, packet...)
+	binary.LittleEndian.PutUint32(page[22:26], oggCRC(0, page))
+	o.sequence++
+
+	_, err := o.w.Write(page)
+	return err
+}
+
+// SendOggOpus reads the Opus packets of an Ogg Opus stream and sends them as frames, until the end of
+// the stream or until the context is cancelled.
+func (v *voiceImpl) SendOggOpus(ctx context.Context, r io.Reader) error {
+	if !v.isReady() {
+		panic("Attempting to send to a closed voice connection")
+	}
+
+	ogg := NewOggOpusReader(r)
+	for {
+		packet, err := ogg.ReadPacket()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		select {
+		case v.send <- packet:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-v.close:
+			return errors.New("voice connection was closed")
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package disgord
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func readOggOpusPackets(t *testing.T, r io.Reader) (*OggOpusReader, [][]byte, error) {
+	t.Helper()
+	ogg := NewOggOpusReader(r)
+	var packets [][]byte
+	for {
+		packet, err := ogg.ReadPacket()
+		if err == io.EOF {
+			return ogg, packets, nil
+		}
+		if err != nil {
+			return ogg, packets, err
+		}
+		packets = append(packets, packet)
+	}
+}
+
+func TestOggOpusReader(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/voice/frames.opus")
+	check(err, t)
+
+	ogg, packets, err := readOggOpusPackets(t, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head := ogg.Head()
+	if head == nil || head.Channels != 2 || head.PreSkip != 312 || head.InputSampleRate != 48000 {
+		t.Errorf("unexpected OpusHead %+v", head)
+	}
+
+	// multiple packets per page, a packet larger than 255 bytes, and a packet continued on the next page
+	sizes := []int{3, 10, 300, 600, 3}
+	if len(packets) != len(sizes) {
+		t.Fatalf("expected %d packets, got %d", len(sizes), len(packets))
+	}
+	for i, packet := range packets {
+		if len(packet) != sizes[i] || !bytes.Equal(packet, bytes.Repeat([]byte{byte(i + 1)}, sizes[i])) {
+			t.Errorf("unexpected packet %d of %d bytes", i, len(packet))
+		}
+	}
+}
+
+func TestOggOpusReader_Invalid(t *testing.T) {
+	vorbis, err := ioutil.ReadFile("testdata/voice/vorbis.ogg")
+	check(err, t)
+	if _, _, err = readOggOpusPackets(t, bytes.NewReader(vorbis)); err == nil {
+		t.Error("expected an error for a stream without OpusHead")
+	}
+
+	data, err := ioutil.ReadFile("testdata/voice/frames.opus")
+	check(err, t)
+
+	corrupted := append([]byte(nil), data...)
+	corrupted[len(corrupted)-1] ^= 0xFF
+	if _, _, err = readOggOpusPackets(t, bytes.NewReader(corrupted)); err == nil {
+		t.Error("expected a checksum error")
+	}
+
+	if _, _, err = readOggOpusPackets(t, bytes.NewReader(data[:len(data)-10])); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected unexpected EOF for a truncated stream, got %v", err)
+	}
+}
+
+func TestOggOpusWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewOggOpusWriter(buf, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	written := [][]byte{{0xF8, 0xFF, 0xFE}, bytes.Repeat([]byte{7}, 510), {}}
+	for _, packet := range written {
+		if err = w.WritePacket(packet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.WritePacket([]byte{1}); err == nil {
+		t.Error("expected an error after closing")
+	}
+
+	ogg, packets, err := readOggOpusPackets(t, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ogg.Head().Channels != 2 {
+		t.Errorf("expected 2 channels, got %d", ogg.Head().Channels)
+	}
+	if len(packets) != len(written) {
+		t.Fatalf("expected %d packets, got %d", len(written), len(packets))
+	}
+	for i := range written {
+		if !bytes.Equal(packets[i], written[i]) {
+			t.Errorf("packet %d differs", i)
+		}
+	}
+}
+
+func TestVoiceImpl_SendOggOpus(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/voice/frames.opus")
+	check(err, t)
+
+	v := &voiceImpl{
+		ready: true,
+		send:  make(chan []byte),
+	}
+	go func() {
+		<-v.send
+		<-v.send
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err = v.SendOggOpus(ctx, bytes.NewReader(data)); err != context.DeadlineExceeded {
+		t.Errorf("expected the context to stop sending, got %v", err)
+	}
+
+	go func() {
+		for range v.send {
+		}
+	}()
+	if err = v.SendOggOpus(context.Background(), bytes.NewReader(data)); err != nil {
+		t.Error(err)
+	}
+	close(v.send)
+}
@@ -0,0 +1,111 @@
+package disgord
+
+import (
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+const (
+	// voiceFrameDuration is the audio duration of every sent frame, 960 samples at 48kHz
+	voiceFrameDuration = 20 * time.Millisecond
+
+	// voiceMaxCatchUp is how far behind schedule frames are still sent, back to back, to catch up
+	// with the timeline after a short stall
+	voiceMaxCatchUp = 5 * voiceFrameDuration
+)
+
+// voiceClock allows the pacer to be tested without sleeping
+type voiceClock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+// Now includes the monotonic clock reading, which keeps the timeline correct on wall clock changes
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+type pacerDecision int
+
+const (
+	pacerSend pacerDecision = iota
+	pacerSendLate
+	pacerDrop
+	pacerClosed
+)
+
+// voicePacer schedules every frame against an absolute timeline, so the time spent encrypting and
+// writing a frame does not delay the next frame.
+//
+// A frame can be behind schedule for two reasons. Either no audio was sent for a while, eg. the player
+// was paused, and the timeline is restarted. Or the send loop stalled while the next frame was waiting,
+// in which case the frames are sent back to back to catch up, or dropped when too far behind.
+type voicePacer struct {
+	clock voiceClock
+	next  time.Time // when the next frame is due
+}
+
+func newVoicePacer(clock voiceClock) *voicePacer {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &voicePacer{clock: clock}
+}
+
+// wait blocks until the frame is due, and decides if the frame is sent or dropped. Idle must be true
+// when the send loop had to wait for the frame.
+func (p *voicePacer) wait(closed <-chan struct{}, idle bool) pacerDecision {
+	now := p.clock.Now()
+	if p.next.IsZero() || (idle && now.Sub(p.next) > voiceMaxCatchUp) {
+		p.next = now
+	}
+
+	due := p.next
+	p.next = p.next.Add(voiceFrameDuration)
+
+	if late := now.Sub(due); late > 0 {
+		if late > voiceMaxCatchUp {
+			return pacerDrop
+		}
+		return pacerSendLate
+	}
+
+	select {
+	case <-p.clock.After(due.Sub(now)):
+		return pacerSend
+	case <-closed:
+		return pacerClosed
+	}
+}
+
+// VoiceStats holds the counters of the audio sent by a VoiceConnection, see VoiceConnection.Stats.
+type VoiceStats struct {
+	FramesSent uint64
+
+	// FramesLate were sent behind schedule, to catch up after a short stall
+	FramesLate uint64
+
+	// FramesDropped were never sent, as the connection was too far behind schedule or was not connected
+	FramesDropped uint64
+
+	// SendErrors is the number of frames that could not be written to the UDP connection
+	SendErrors uint64
+}
+
+type voiceStats struct {
+	sent       atomic.Uint64
+	late       atomic.Uint64
+	dropped    atomic.Uint64
+	sendErrors atomic.Uint64
+}
+
+func (v *voiceImpl) Stats() VoiceStats {
+	return VoiceStats{
+		FramesSent:    v.stats.sent.Load(),
+		FramesLate:    v.stats.late.Load(),
+		FramesDropped: v.stats.dropped.Load(),
+		SendErrors:    v.stats.sendErrors.Load(),
+	}
+}
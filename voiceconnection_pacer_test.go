@@ -0,0 +1,195 @@
+package disgord
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeVoiceClock moves forward instantly when waited on
+type fakeVoiceClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeVoiceClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeVoiceClock) After(d time.Duration) <-chan time.Time {
+	c.advance(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return ch
+}
+
+func (c *fakeVoiceClock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// fakeVoiceUDP records when every frame was written. Stalls and errors are keyed by the write number,
+// starting at 1.
+type fakeVoiceUDP struct {
+	net.Conn
+	clock   *fakeVoiceClock
+	writes  int
+	times   []time.Time
+	packets [][]byte
+	stalls  map[int]time.Duration
+	errors  map[int]bool
+}
+
+func (c *fakeVoiceUDP) Write(b []byte) (int, error) {
+	c.writes++
+	if c.errors[c.writes] {
+		return 0, errors.New("network is unreachable")
+	}
+	c.times = append(c.times, c.clock.Now())
+	c.packets = append(c.packets, append([]byte(nil), b...))
+	c.clock.advance(c.stalls[c.writes])
+	return len(b), nil
+}
+
+func sendTestVoiceFrames(udp *fakeVoiceUDP, nrOfFrames int) *voiceImpl {
+	v := &voiceImpl{
+		voiceLink: voiceLink{
+			udp:    udp,
+			crypto: newTestVoiceCrypto(VoiceModeXSalsa20Poly1305, [32]byte{}),
+		},
+		send:  make(chan []byte, nrOfFrames),
+		close: make(chan struct{}),
+		clock: udp.clock,
+		c:     New(Config{BotToken: "testing", DisableCache: true}),
+	}
+	for i := 0; i < nrOfFrames; i++ {
+		v.send <- silenceFrame
+	}
+	close(v.send)
+
+	// every frame is queued, so the loop returns once the frames are sent
+	v.opusSendLoop()
+	return v
+}
+
+func TestVoiceImpl_opusSendLoop(t *testing.T) {
+	start := time.Unix(1546300800, 0)
+
+	t.Run("paced", func(t *testing.T) {
+		udp := &fakeVoiceUDP{clock: &fakeVoiceClock{now: start}}
+		v := sendTestVoiceFrames(udp, 10)
+		for i, sent := range udp.times {
+			if expects := start.Add(time.Duration(i) * voiceFrameDuration); !sent.Equal(expects) {
+				t.Errorf("frame %d was sent at %s, expected %s", i, sent.Sub(start), expects.Sub(start))
+			}
+		}
+		if stats := v.Stats(); stats != (VoiceStats{FramesSent: 10}) {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+	})
+	t.Run("short stall", func(t *testing.T) {
+		udp := &fakeVoiceUDP{
+			clock:  &fakeVoiceClock{now: start},
+			stalls: map[int]time.Duration{3: 3 * voiceFrameDuration},
+		}
+		v := sendTestVoiceFrames(udp, 10)
+
+		// frame 4 and 5 catch up, and frame 6 is back on schedule
+		if stats := v.Stats(); stats != (VoiceStats{FramesSent: 10, FramesLate: 2}) {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+		if sent := udp.times[9]; !sent.Equal(start.Add(9 * voiceFrameDuration)) {
+			t.Errorf("expected the last frame to be on schedule, got %s", sent.Sub(start))
+		}
+	})
+	t.Run("long stall", func(t *testing.T) {
+		udp := &fakeVoiceUDP{
+			clock:  &fakeVoiceClock{now: start},
+			stalls: map[int]time.Duration{3: 10 * voiceFrameDuration},
+		}
+		v := sendTestVoiceFrames(udp, 20)
+
+		// frame 4 to 7 are more than 100ms behind, frame 8 to 12 are caught up
+		if stats := v.Stats(); stats != (VoiceStats{FramesSent: 16, FramesLate: 5, FramesDropped: 4}) {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+		if sent := udp.times[len(udp.times)-1]; !sent.Equal(start.Add(19 * voiceFrameDuration)) {
+			t.Errorf("expected the last frame to be on schedule, got %s", sent.Sub(start))
+		}
+	})
+	t.Run("send errors", func(t *testing.T) {
+		udp := &fakeVoiceUDP{
+			clock:  &fakeVoiceClock{now: start},
+			errors: map[int]bool{2: true, 3: true, 7: true},
+		}
+		v := sendTestVoiceFrames(udp, 10)
+		if stats := v.Stats(); stats != (VoiceStats{FramesSent: 7, SendErrors: 3}) {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+	})
+	t.Run("not connected", func(t *testing.T) {
+		v := &voiceImpl{
+			send:  make(chan []byte, 1),
+			close: make(chan struct{}),
+			clock: &fakeVoiceClock{now: start},
+		}
+		v.send <- silenceFrame
+		close(v.send)
+		v.opusSendLoop()
+		if stats := v.Stats(); stats != (VoiceStats{FramesDropped: 1}) {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+	})
+}
+
+func TestVoicePacer_wait(t *testing.T) {
+	clock := &fakeVoiceClock{now: time.Unix(1546300800, 0)}
+	pacer := newVoicePacer(clock)
+	closed := make(chan struct{})
+
+	if decision := pacer.wait(closed, true); decision != pacerSend {
+		t.Errorf("expected the first frame to be sent, got %d", decision)
+	}
+	if decision := pacer.wait(closed, false); decision != pacerSend {
+		t.Errorf("expected the second frame to be sent, got %d", decision)
+	}
+
+	// the connection was idle, eg. paused
+	clock.advance(time.Second)
+	before := clock.Now()
+	if decision := pacer.wait(closed, true); decision != pacerSend {
+		t.Errorf("expected the timeline to restart after being idle, got %d", decision)
+	}
+	if !clock.Now().Equal(before) {
+		t.Errorf("expected the frame to be sent without waiting, waited %s", clock.Now().Sub(before))
+	}
+
+	// the send loop stalled
+	clock.advance(voiceMaxCatchUp + 2*voiceFrameDuration)
+	if decision := pacer.wait(closed, false); decision != pacerDrop {
+		t.Errorf("expected the frame to be dropped, got %d", decision)
+	}
+	if decision := pacer.wait(closed, false); decision != pacerSendLate {
+		t.Errorf("expected the frame to be sent late, got %d", decision)
+	}
+
+	close(closed)
+	pacer = newVoicePacer(blockingVoiceClock{clock})
+	pacer.wait(closed, true)
+	if decision := pacer.wait(closed, false); decision != pacerClosed {
+		t.Errorf("expected the pacer to stop, got %d", decision)
+	}
+}
+
+type blockingVoiceClock struct {
+	*fakeVoiceClock
+}
+
+func (blockingVoiceClock) After(time.Duration) <-chan time.Time {
+	return nil
+}
@@ -0,0 +1,343 @@
+package disgord
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"sync"
+)
+
+//////////////////////////////////////////////////////
+//
+// Player: Plays a queue of tracks on a VoiceConnection.
+//
+// The player toggles speaking, and sends the trailing silence frames required by Discord
+// whenever the playback stops, is paused or the queue runs empty.
+//
+//////////////////////////////////////////////////////
+
+// silenceFrame is sent five times when the audio stops, to avoid unintended Opus interpolation
+var silenceFrame = []byte{0xF8, 0xFF, 0xFE}
+
+const nrOfTrailingSilenceFrames = 5
+
+// AudioSource provides the Opus frames of a track. Every frame holds 20ms of audio (960 samples at 48kHz).
+// When the source implements io.Closer, it is closed once the track ends.
+type AudioSource interface {
+	// ReadFrame returns the next Opus frame, or io.EOF when there are no more frames.
+	ReadFrame() ([]byte, error)
+}
+
+type dcaSource struct {
+	r io.Reader
+}
+
+// NewDCASource creates an AudioSource from a DCA encoded stream/file.
+func NewDCASource(r io.Reader) AudioSource {
+	return &dcaSource{r: r}
+}
+
+func (s *dcaSource) ReadFrame() ([]byte, error) {
+	var sampleSize uint16
+	if err := binary.Read(s.r, binary.LittleEndian, &sampleSize); err != nil {
+		return nil, err
+	}
+
+	frame := make([]byte, sampleSize)
+	if _, err := io.ReadFull(s.r, frame); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return frame, nil
+}
+
+type oggOpusSource struct {
+	*OggOpusReader
+}
+
+// NewOggOpusSource creates an AudioSource from an Ogg Opus stream/file, eg. an .opus file.
+func NewOggOpusSource(r io.Reader) AudioSource {
+	return &oggOpusSource{NewOggOpusReader(r)}
+}
+
+func (s *oggOpusSource) ReadFrame() ([]byte, error) {
+	return s.ReadPacket()
+}
+
+// OpusFrameSource is an AudioSource of raw Opus frames, eg. from an encoder. The source ends when
+// the channel is closed.
+type OpusFrameSource <-chan []byte
+
+func (s OpusFrameSource) ReadFrame() ([]byte, error) {
+	frame, open := <-s
+	if !open {
+		return nil, io.EOF
+	}
+	return frame, nil
+}
+
+// Track is a single item in the queue of a Player
+type Track struct {
+	// Title is not used by the player, but can help identify the track in the player events
+	Title  string
+	Source AudioSource
+}
+
+// PlayerConfig holds the optional event handlers of a Player. The handlers are called from
+// the player goroutine, and delay the playback until they return.
+type PlayerConfig struct {
+	// OnTrackStart is called before the first frame of a track is sent
+	OnTrackStart func(track *Track)
+	// OnTrackEnd is called when a track ended, was skipped or was stopped
+	OnTrackEnd func(track *Track)
+	// OnTrackError is called in stead of OnTrackEnd, when the source of a track returned an error
+	OnTrackError func(track *Track, err error)
+}
+
+// Player plays a queue of tracks on a VoiceConnection, until the context is cancelled.
+//  player := disgord.NewPlayer(ctx, voice, nil)
+//  player.Enqueue(&disgord.Track{Title: "intro", Source: disgord.NewOggOpusSource(file)})
+type Player struct {
+	conn VoiceConnection
+	conf PlayerConfig
+	ctx  context.Context
+
+	mu      sync.Mutex
+	queue   []*Track
+	current *Track
+	paused  bool
+
+	// speaking is only accessed by the player goroutine
+	speaking bool
+
+	// wake signals changes to the queue or pause state, and skip ends the current track
+	wake chan struct{}
+	skip chan struct{}
+
+	// done is closed once the playback goroutine returned
+	done chan struct{}
+}
+
+// NewPlayer creates a Player for the voice connection, and starts the playback goroutine. The player
+// stops once the context is cancelled. Use Wait before closing the voice connection, such that the
+// trailing silence frames are sent. The config is optional.
+func NewPlayer(ctx context.Context, conn VoiceConnection, config *PlayerConfig) *Player {
+	p := &Player{
+		conn: conn,
+		ctx:  ctx,
+		wake: make(chan struct{}, 1),
+		skip: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	if config != nil {
+		p.conf = *config
+	}
+
+	go p.run()
+	return p
+}
+
+// Enqueue adds tracks to the end of the queue. The playback starts immediately when the player is idle.
+func (p *Player) Enqueue(tracks ...*Track) {
+	p.mu.Lock()
+	p.queue = append(p.queue, tracks...)
+	p.mu.Unlock()
+	p.signal(p.wake)
+}
+
+// Queue returns the tracks waiting to be played, not including the current track.
+func (p *Player) Queue() []*Track {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*Track(nil), p.queue...)
+}
+
+// Current returns the track being played, or nil.
+func (p *Player) Current() *Track {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current
+}
+
+// Pause pauses the current track. The speaking indicator is turned off until the playback resumes.
+func (p *Player) Pause() {
+	p.mu.Lock()
+	p.paused = true
+	p.mu.Unlock()
+	p.signal(p.wake)
+}
+
+// Resume continues the playback after Pause.
+func (p *Player) Resume() {
+	p.mu.Lock()
+	p.paused = false
+	p.mu.Unlock()
+	p.signal(p.wake)
+}
+
+// Paused checks if the playback is paused.
+func (p *Player) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+// Skip ends the current track, and plays the next track in the queue. A source that blocks in ReadFrame,
+// eg. an OpusFrameSource without frames, ends once ReadFrame returns.
+func (p *Player) Skip() {
+	p.mu.Lock()
+	playing := p.current != nil
+	p.mu.Unlock()
+	if playing {
+		p.signal(p.skip)
+	}
+}
+
+// Done returns a channel that is closed once the player stopped, after the context was cancelled.
+func (p *Player) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait blocks until the player stopped, see Done.
+func (p *Player) Wait() {
+	<-p.done
+}
+
+// Stop clears the queue and ends the current track. The player keeps running, see NewPlayer.
+func (p *Player) Stop() {
+	p.mu.Lock()
+	p.queue = nil
+	p.mu.Unlock()
+	p.Skip()
+}
+
+func (p *Player) signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+		// already signalled
+	}
+}
+
+func (p *Player) next() *Track {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.queue) == 0 {
+		return nil
+	}
+
+	// a skip that arrived between two tracks must not skip the next track. See Skip
+	select {
+	case <-p.skip:
+	default:
+	}
+	p.current = p.queue[0]
+	p.queue = p.queue[1:]
+	return p.current
+}
+
+func (p *Player) run() {
+	defer close(p.done)
+	defer p.silence()
+
+	for {
+		track := p.next()
+		if track == nil {
+			p.silence()
+			select {
+			case <-p.wake:
+				continue
+			case <-p.ctx.Done():
+				return
+			}
+		}
+
+		p.play(track)
+
+		p.mu.Lock()
+		p.current = nil
+		p.mu.Unlock()
+		if p.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (p *Player) play(track *Track) {
+	if closer, ok := track.Source.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	if p.conf.OnTrackStart != nil {
+		p.conf.OnTrackStart(track)
+	}
+
+	var err error
+	for err == nil {
+		select {
+		case <-p.skip:
+			err = io.EOF
+			continue
+		case <-p.ctx.Done():
+			err = io.EOF
+			continue
+		default:
+		}
+
+		if p.Paused() {
+			p.silence()
+			select {
+			case <-p.wake:
+			case <-p.skip:
+				err = io.EOF
+			case <-p.ctx.Done():
+				err = io.EOF
+			}
+			continue
+		}
+
+		var frame []byte
+		if frame, err = track.Source.ReadFrame(); err != nil {
+			continue
+		}
+		if err = p.startSpeaking(); err != nil {
+			continue
+		}
+		p.conn.SendOpusFrame(frame)
+	}
+
+	if err != io.EOF {
+		if p.conf.OnTrackError != nil {
+			p.conf.OnTrackError(track, err)
+		}
+		return
+	}
+	if p.conf.OnTrackEnd != nil {
+		p.conf.OnTrackEnd(track)
+	}
+}
+
+func (p *Player) startSpeaking() error {
+	if p.speaking {
+		return nil
+	}
+	if err := p.conn.StartSpeaking(); err != nil {
+		return err
+	}
+	p.speaking = true
+	return nil
+}
+
+// silence sends the trailing silence frames, and turns off the speaking indicator
+func (p *Player) silence() {
+	if !p.speaking {
+		return
+	}
+	for i := 0; i < nrOfTrailingSilenceFrames; i++ {
+		p.conn.SendOpusFrame(silenceFrame)
+	}
+	_ = p.conn.StopSpeaking()
+	p.speaking = false
+}
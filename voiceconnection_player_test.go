@@ -0,0 +1,242 @@
+package disgord
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingVoiceConnection struct {
+	VoiceConnection // panics on methods not used by the player
+
+	mu       sync.Mutex
+	frames   [][]byte
+	speaking []bool
+	sent     chan []byte
+}
+
+func newRecordingVoiceConnection() *recordingVoiceConnection {
+	return &recordingVoiceConnection{sent: make(chan []byte, 100)}
+}
+
+func (c *recordingVoiceConnection) StartSpeaking() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.speaking = append(c.speaking, true)
+	return nil
+}
+
+func (c *recordingVoiceConnection) StopSpeaking() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.speaking = append(c.speaking, false)
+	return nil
+}
+
+func (c *recordingVoiceConnection) SendOpusFrame(data []byte) {
+	c.mu.Lock()
+	c.frames = append(c.frames, data)
+	c.mu.Unlock()
+	c.sent <- data
+}
+
+func (c *recordingVoiceConnection) recorded() ([][]byte, []bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]byte(nil), c.frames...), append([]bool(nil), c.speaking...)
+}
+
+func receiveFrame(t *testing.T, conn *recordingVoiceConnection) []byte {
+	t.Helper()
+	select {
+	case frame := <-conn.sent:
+		return frame
+	case <-time.After(time.Second):
+		t.Fatal("expected a frame")
+		return nil
+	}
+}
+
+type failingSource struct{}
+
+func (failingSource) ReadFrame() ([]byte, error) {
+	return nil, errors.New("decoder crashed")
+}
+
+func TestPlayer(t *testing.T) {
+	conn := newRecordingVoiceConnection()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan string, 10)
+	player := NewPlayer(ctx, conn, &PlayerConfig{
+		OnTrackStart: func(track *Track) { events <- "start " + track.Title },
+		OnTrackEnd:   func(track *Track) { events <- "end " + track.Title },
+		OnTrackError: func(track *Track, err error) { events <- "error " + track.Title + ": " + err.Error() },
+	})
+
+	frames := make(chan []byte)
+	player.Enqueue(
+		&Track{Title: "live", Source: OpusFrameSource(frames)},
+		&Track{Title: "broken", Source: failingSource{}},
+	)
+
+	frames <- []byte{1}
+	if frame := receiveFrame(t, conn); frame[0] != 1 {
+		t.Errorf("unexpected frame %v", frame)
+	}
+	if current := player.Current(); current == nil || current.Title != "live" {
+		t.Errorf("expected the first track to be playing, got %+v", current)
+	}
+
+	player.Pause()
+	frames <- []byte{2} // read before the pause was noticed, or after the resume
+	for i := 0; i < nrOfTrailingSilenceFrames; i++ {
+		frame := receiveFrame(t, conn)
+		if frame[0] == 2 {
+			frame = receiveFrame(t, conn)
+		}
+		if !bytes.Equal(frame, silenceFrame) {
+			t.Fatalf("expected silence when paused, got %v", frame)
+		}
+	}
+	player.Resume()
+
+	player.Skip()
+	close(frames)
+
+	var got []string
+	for len(got) < 4 {
+		select {
+		case evt := <-events:
+			got = append(got, evt)
+		case <-time.After(time.Second):
+			t.Fatalf("expected more player events, got %v", got)
+		}
+	}
+	expects := []string{"start live", "end live", "start broken", "error broken: decoder crashed"}
+	for i := range expects {
+		if got[i] != expects[i] {
+			t.Errorf("expected events %v, got %v", expects, got)
+			break
+		}
+	}
+
+	// the queue ran empty
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, speaking := conn.recorded()
+		if len(speaking) > 0 && !speaking[len(speaking)-1] {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected speaking to stop, got %v", speaking)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if player.Current() != nil || len(player.Queue()) != 0 {
+		t.Error("expected the player to be idle")
+	}
+}
+
+func TestPlayer_Stop(t *testing.T) {
+	conn := newRecordingVoiceConnection()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	player := NewPlayer(ctx, conn, nil)
+	frames := make(chan []byte)
+	player.Enqueue(&Track{Source: OpusFrameSource(frames)}, &Track{Source: OpusFrameSource(frames)})
+	frames <- []byte{1}
+	receiveFrame(t, conn)
+
+	player.Stop()
+	close(frames)
+	for i := 0; i < nrOfTrailingSilenceFrames; i++ {
+		if frame := receiveFrame(t, conn); !bytes.Equal(frame, silenceFrame) {
+			t.Fatalf("expected silence after stopping, got %v", frame)
+		}
+	}
+	if len(player.Queue()) != 0 {
+		t.Error("expected the queue to be cleared")
+	}
+}
+
+func TestPlayer_Wait(t *testing.T) {
+	c := New(Config{BotToken: "testing", DisableCache: true})
+	v := newTestVoiceImpl(c, 486833611564253184)
+	v.send = make(chan []byte, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	player := NewPlayer(ctx, v, nil)
+	frames := make(chan []byte, 1)
+	frames <- []byte{1}
+	player.Enqueue(&Track{Source: OpusFrameSource(frames)})
+
+	cancel()
+	select {
+	case <-player.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the player to stop")
+	}
+	player.Wait()
+
+	if err := v.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a frame sent after Close is discarded
+	v.SendOpusFrame(silenceFrame)
+	if err := v.StopSpeaking(); err == nil {
+		t.Error("expected an error when speaking on a closed connection")
+	}
+}
+
+func TestDCASource(t *testing.T) {
+	buf := &bytes.Buffer{}
+	for _, frame := range [][]byte{{1, 2, 3}, {4}} {
+		_ = binary.Write(buf, binary.LittleEndian, uint16(len(frame)))
+		buf.Write(frame)
+	}
+	data := buf.Bytes()
+
+	source := NewDCASource(bytes.NewReader(data))
+	for _, expects := range [][]byte{{1, 2, 3}, {4}} {
+		frame, err := source.ReadFrame()
+		if err != nil || !bytes.Equal(frame, expects) {
+			t.Errorf("expected %v, got %v, %v", expects, frame, err)
+		}
+	}
+	if _, err := source.ReadFrame(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+
+	// short reads are errors, not panics
+	source = NewDCASource(bytes.NewReader(data[:4]))
+	if _, err := source.ReadFrame(); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected unexpected EOF, got %v", err)
+	}
+}
+
+func TestOggOpusSource(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/voice/frames.opus")
+	check(err, t)
+
+	source := NewOggOpusSource(bytes.NewReader(data))
+	var frames int
+	for {
+		if _, err = source.ReadFrame(); err != nil {
+			break
+		}
+		frames++
+	}
+	if err != io.EOF || frames != 5 {
+		t.Errorf("expected 5 frames and EOF, got %d, %v", frames, err)
+	}
+}
@@ -0,0 +1,156 @@
+package disgord
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/andersfylling/disgord/internal/gateway"
+)
+
+// VoiceReceiveBufferSize is the number of received packets that can wait to be read from
+// VoiceConnection.Receive. Packets are dropped when the buffer is full.
+const VoiceReceiveBufferSize = 256
+
+// VoicePacket is a single Opus frame received from a user in the voice channel.
+type VoicePacket struct {
+	// UserID is zero until the user has been seen speaking, as Discord only maps the SSRC
+	// to the user once the user starts speaking.
+	UserID Snowflake
+	SSRC   uint32
+
+	// Sequence and Timestamp are taken from the RTP header. The sequence increases by one for every
+	// frame and can be used to detect lost packets, while the timestamp increases by 960 samples.
+	Sequence  uint16
+	Timestamp uint32
+
+	Opus []byte
+}
+
+const (
+	rtpHeaderSize     = 12
+	rtpVersion        = 2
+	rtpPayloadTypeMin = 200 // RTCP packets are sent over the same socket, see RFC 5761
+	rtpPayloadTypeMax = 204
+)
+
+// voiceSSRCs maps the SSRC of the received audio to the speaking users
+type voiceSSRCs struct {
+	sync.RWMutex
+	users map[uint32]Snowflake
+}
+
+func (s *voiceSSRCs) onSpeaking(evt *gateway.VoiceSpeaking) {
+	s.Lock()
+	defer s.Unlock()
+	if s.users == nil {
+		s.users = make(map[uint32]Snowflake)
+	}
+	s.users[evt.SSRC] = evt.UserID
+}
+
+func (s *voiceSSRCs) onClientDisconnect(evt *gateway.VoiceClientDisconnect) {
+	s.Lock()
+	defer s.Unlock()
+	for ssrc, userID := range s.users {
+		if userID == evt.UserID {
+			delete(s.users, ssrc)
+		}
+	}
+}
+
+func (s *voiceSSRCs) user(ssrc uint32) Snowflake {
+	s.RLock()
+	defer s.RUnlock()
+	return s.users[ssrc]
+}
+
+// decodeVoicePacket parses the RTP packet and decrypts the Opus frame. A nil packet without an error
+// is returned for packets that do not hold audio, eg. RTCP packets.
+func decodeVoicePacket(data []byte, crypto *voiceCrypto) (*VoicePacket, error) {
+	if len(data) < rtpHeaderSize || data[0]>>6 != rtpVersion {
+		return nil, nil
+	}
+	if data[1] >= rtpPayloadTypeMin && data[1] <= rtpPayloadTypeMax {
+		return nil, nil
+	}
+
+	headerSize := rtpHeaderSize + 4*int(data[0]&0x0F) // CSRC identifiers
+	if len(data) < headerSize {
+		return nil, errors.New("voice packet is too short")
+	}
+
+	opus, err := crypto.open(data, headerSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// the header extension is encrypted with the audio, see RFC 3550 section 5.3.1
+	if data[0]&0x10 != 0 {
+		if len(opus) < 4 {
+			return nil, errors.New("voice packet header extension is too short")
+		}
+		extensionSize := 4 + 4*int(binary.BigEndian.Uint16(opus[2:4]))
+		if len(opus) < extensionSize {
+			return nil, errors.New("voice packet header extension is too short")
+		}
+		opus = opus[extensionSize:]
+	}
+
+	return &VoicePacket{
+		SSRC:      binary.BigEndian.Uint32(data[8:12]),
+		Sequence:  binary.BigEndian.Uint16(data[2:4]),
+		Timestamp: binary.BigEndian.Uint32(data[4:8]),
+		Opus:      opus,
+	}, nil
+}
+
+func (v *voiceImpl) Receive() <-chan *VoicePacket {
+	return v.receive
+}
+
+// startReceiving reads the packets of the link in the background. Once the connection is ready it must be
+// called while holding the lock, and not after shutdown, as shutdown closes the receive channel.
+func (v *voiceImpl) startReceiving(link *voiceLink) {
+	v.receivers.Add(1)
+	go func() {
+		defer v.receivers.Done()
+		v.opusReceiveLoop(link)
+	}()
+}
+
+// opusReceiveLoop reads the packets of a single link, until the link is replaced or closed
+func (v *voiceImpl) opusReceiveLoop(link *voiceLink) {
+	// https://discordapp.com/developers/docs/topics/voice-connections#encrypting-and-sending-voice
+	buffer := make([]byte, 1500) // MTU
+	for {
+		n, err := link.udp.Read(buffer)
+		if err != nil {
+			select {
+			case <-v.close:
+			case <-link.done:
+			default:
+				v.udpPathDead(link, err)
+			}
+			return
+		}
+
+		packet, err := decodeVoicePacket(buffer[:n], link.crypto)
+		if err != nil {
+			v.c.log.Debug(err)
+			continue
+		}
+		if packet == nil {
+			continue
+		}
+		packet.UserID = v.ssrcs.user(packet.SSRC)
+
+		select {
+		case v.receive <- packet:
+		case <-v.close:
+			return
+		default:
+			// the caller is not keeping up, the packet is lost
+		}
+	}
+}
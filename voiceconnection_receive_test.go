@@ -0,0 +1,131 @@
+package disgord
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/andersfylling/disgord/internal/gateway"
+)
+
+func sealVoicePacket(crypto *voiceCrypto, csrcs int, extension []byte, sequence uint16, timestamp, ssrc uint32, opus []byte) []byte {
+	header := make([]byte, rtpHeaderSize+4*csrcs)
+	header[0] = 0x80 | byte(csrcs)
+	if extension != nil {
+		header[0] |= 0x10
+	}
+	header[1] = 0x78
+	binary.BigEndian.PutUint16(header[2:4], sequence)
+	binary.BigEndian.PutUint32(header[4:8], timestamp)
+	binary.BigEndian.PutUint32(header[8:12], ssrc)
+
+	packet, err := crypto.seal(header, append(extension, opus...))
+	if err != nil {
+		panic(err)
+	}
+	return packet
+}
+
+func newTestVoiceCrypto(mode string, key [32]byte) *voiceCrypto {
+	crypto, err := newVoiceCrypto(mode, key)
+	if err != nil {
+		panic(err)
+	}
+	return crypto
+}
+
+func TestDecodeVoicePacket(t *testing.T) {
+	key := newTestVoiceCrypto(VoiceModeXSalsa20Poly1305, [32]byte{1, 2, 3})
+	opus := []byte{0xF8, 0xFF, 0xFE}
+
+	t.Run("plain", func(t *testing.T) {
+		packet, err := decodeVoicePacket(sealVoicePacket(key, 0, nil, 7, 960, 42, opus), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if packet.SSRC != 42 || packet.Sequence != 7 || packet.Timestamp != 960 || string(packet.Opus) != string(opus) {
+			t.Errorf("unexpected packet %+v", packet)
+		}
+	})
+	t.Run("csrc and header extension", func(t *testing.T) {
+		extension := []byte{0xBE, 0xDE, 0x00, 0x01, 0x10, 0xFF, 0x00, 0x00}
+		packet, err := decodeVoicePacket(sealVoicePacket(key, 2, extension, 1, 0, 42, opus), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(packet.Opus) != string(opus) {
+			t.Errorf("expected the header extension to be skipped, got %v", packet.Opus)
+		}
+	})
+	t.Run("wrong key", func(t *testing.T) {
+		if _, err := decodeVoicePacket(sealVoicePacket(key, 0, nil, 1, 0, 42, opus), newTestVoiceCrypto(VoiceModeXSalsa20Poly1305, [32]byte{})); err == nil {
+			t.Error("expected decryption to fail")
+		}
+	})
+	t.Run("rtcp", func(t *testing.T) {
+		rtcp := make([]byte, 32)
+		rtcp[0], rtcp[1] = 0x81, 201
+		if packet, err := decodeVoicePacket(rtcp, key); packet != nil || err != nil {
+			t.Errorf("expected rtcp packets to be skipped, got %+v, %v", packet, err)
+		}
+	})
+}
+
+func TestVoiceImpl_Receive(t *testing.T) {
+	c := New(Config{BotToken: "testing", DisableCache: true})
+	local, remote := net.Pipe()
+	v := &voiceImpl{
+		voiceLink: voiceLink{
+			udp:    local,
+			crypto: newTestVoiceCrypto(VoiceModeXSalsa20Poly1305Lite, [32]byte{4, 5, 6}),
+			done:   make(chan struct{}),
+		},
+		ready:   true,
+		send:    make(chan []byte),
+		receive: make(chan *VoicePacket, 1),
+		close:   make(chan struct{}),
+		c:       c,
+	}
+	link := v.voiceLink
+	v.startReceiving(&link)
+
+	v.ssrcs.onSpeaking(&gateway.VoiceSpeaking{UserID: 486833611564253184, SSRC: 42})
+	packets := [][]byte{
+		sealVoicePacket(v.crypto, 0, nil, 1, 0, 42, []byte{1}),
+		sealVoicePacket(v.crypto, 0, nil, 1, 0, 43, []byte{2}), // unknown user
+	}
+	for i, data := range packets {
+		if _, err := remote.Write(data); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case packet := <-v.Receive():
+			if packet.Opus[0] != byte(i+1) {
+				t.Errorf("unexpected packet %+v", packet)
+			}
+			if expects := []Snowflake{486833611564253184, 0}[i]; packet.UserID != expects {
+				t.Errorf("expected user %d, got %d", expects, packet.UserID)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected a packet")
+		}
+	}
+
+	v.ssrcs.onClientDisconnect(&gateway.VoiceClientDisconnect{UserID: 486833611564253184})
+	if userID := v.ssrcs.user(42); !userID.IsZero() {
+		t.Errorf("expected the ssrc to be forgotten, got %d", userID)
+	}
+
+	_ = v.Close()
+	_ = remote.Close()
+	select {
+	case _, open := <-v.Receive():
+		if open {
+			t.Error("expected no more packets")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the channel to be closed")
+	}
+}
@@ -0,0 +1,215 @@
+package disgord
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/andersfylling/disgord/internal/gateway"
+)
+
+func newTestVoiceImpl(c *Client, guildID Snowflake) *voiceImpl {
+	v := &voiceImpl{
+		ready:   true,
+		send:    make(chan []byte),
+		receive: make(chan *VoicePacket),
+		close:   make(chan struct{}),
+		guildID: guildID,
+		c:       c,
+	}
+	c.voiceRepository.Lock()
+	c.voiceRepository.active[guildID] = v
+	c.voiceRepository.Unlock()
+	return v
+}
+
+func TestVoiceImpl_State(t *testing.T) {
+	c := New(Config{BotToken: "testing", DisableCache: true})
+	v := newTestVoiceImpl(c, 486833611564253184)
+
+	var states []VoiceConnectionState
+	v.OnStateChange(func(state VoiceConnectionState) {
+		states = append(states, state)
+	})
+
+	v.setState(VoiceConnectionResuming)
+	v.setState(VoiceConnectionResuming)
+	v.setState(VoiceConnectionConnected)
+	v.onClose(nil, gateway.VoiceCloseDisconnected, "disconnected")
+	if v.State() != VoiceConnectionDisconnected {
+		t.Errorf("expected %s, got %s", VoiceConnectionDisconnected, v.State())
+	}
+
+	// a voice server update without an endpoint waits for the next update
+	c.voiceRepository.onVoiceServerUpdate(c, &VoiceServerUpdate{GuildID: v.guildID, Token: "token"})
+	v.reconnect("", "token")
+
+	if err := v.Close(); err != nil {
+		t.Fatal(err)
+	}
+	v.setState(VoiceConnectionConnected)
+
+	expects := []VoiceConnectionState{
+		VoiceConnectionResuming,
+		VoiceConnectionConnected,
+		VoiceConnectionDisconnected,
+		VoiceConnectionClosed,
+	}
+	if len(states) != len(expects) {
+		t.Fatalf("expected states %v, got %v", expects, states)
+	}
+	for i := range expects {
+		if states[i] != expects[i] {
+			t.Errorf("expected states %v, got %v", expects, states)
+			break
+		}
+	}
+
+	if _, exists := c.voiceRepository.active[v.guildID]; exists {
+		t.Error("expected the closed connection to be removed")
+	}
+}
+
+func TestVoiceRepository_OnVoiceStateUpdate(t *testing.T) {
+	c := New(Config{BotToken: "testing", DisableCache: true})
+	c.myID = 486833611564253185
+	v := newTestVoiceImpl(c, 486833611564253184)
+
+	update := &VoiceStateUpdate{VoiceState: &VoiceState{
+		GuildID:   v.guildID,
+		ChannelID: 486833611564253186,
+		UserID:    c.myID,
+		SessionID: "moved",
+	}}
+	c.voiceRepository.onVoiceStateUpdate(c, update)
+	if v.channelID != update.ChannelID || v.sessionID != "moved" {
+		t.Errorf("expected the session to follow the voice state, got %d, %s", v.channelID, v.sessionID)
+	}
+
+	// the bot was kicked from the voice channel
+	closed := make(chan struct{})
+	v.OnStateChange(func(state VoiceConnectionState) {
+		if state == VoiceConnectionClosed {
+			close(closed)
+		}
+	})
+	update.ChannelID = 0
+	c.voiceRepository.onVoiceStateUpdate(c, update)
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatalf("expected %s, got %s", VoiceConnectionClosed, v.State())
+	}
+	if c.VoiceConnection(v.guildID) != nil {
+		t.Error("expected the connection to be removed")
+	}
+
+	// frames are discarded, and Close can still be called
+	v.SendOpusFrame(silenceFrame)
+	if err := v.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVoiceRepository_VoiceConnections(t *testing.T) {
+	c := New(Config{BotToken: "testing", DisableCache: true})
+	guildIDs := []Snowflake{486833611564253184, 486833611564253185}
+	for _, guildID := range guildIDs {
+		newTestVoiceImpl(c, guildID).channelID = 486833611564253186
+	}
+
+	if voice := c.VoiceConnection(486833611564253187); voice != nil {
+		t.Errorf("expected no connection, got %v", voice)
+	}
+	for _, guildID := range guildIDs {
+		voice := c.VoiceConnection(guildID)
+		if voice == nil {
+			t.Fatalf("expected a connection for guild %d", guildID)
+		}
+		if voice != c.VoiceConnections()[guildID] {
+			t.Errorf("expected the same connection for guild %d", guildID)
+		}
+
+		// the bot is already in the channel
+		same, err := c.VoiceConnect(guildID, 486833611564253186)
+		if err != nil || same != voice {
+			t.Errorf("expected the active connection to be returned, got %v, %v", same, err)
+		}
+	}
+
+	closed := c.VoiceConnection(guildIDs[0])
+	if err := closed.Close(); err != nil {
+		t.Fatal(err)
+	}
+	open := c.VoiceConnection(guildIDs[1])
+
+	c.voiceRepository.closeAll()
+	if connections := c.VoiceConnections(); len(connections) != 0 {
+		t.Errorf("expected every connection to be closed, got %d", len(connections))
+	}
+	if open.State() != VoiceConnectionClosed {
+		t.Errorf("expected %s, got %s", VoiceConnectionClosed, open.State())
+	}
+
+	// the owner can still send frames and close the connection after a disconnect
+	open.SendOpusFrame(silenceFrame)
+	if err := open.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+type recordingEventSource struct {
+	recordingEmitter
+}
+
+func (s *recordingEventSource) Events() <-chan *RawEvent {
+	return nil
+}
+
+func TestVoiceRepository_VoiceConnectOptions(t *testing.T) {
+	src := &recordingEventSource{}
+	c := New(Config{BotToken: "testing", DisableCache: true, EventSource: src})
+	guildID := Snowflake(486833611564253184)
+	voice := newTestVoiceImpl(c, guildID)
+	voice.channelID = 486833611564253186
+
+	// the flags are unchanged, so there is nothing to tell Discord
+	if _, err := c.VoiceConnectOptions(guildID, 486833611564253186, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if cmds := src.received(); len(cmds) != 0 {
+		t.Fatalf("expected no commands, got %d", len(cmds))
+	}
+
+	same, err := c.VoiceConnectOptions(guildID, 486833611564253186, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same != voice {
+		t.Error("expected the active connection to be returned")
+	}
+	cmds := src.received()
+	if len(cmds) != 1 || cmds[0].Name != string(UpdateVoiceState) {
+		t.Fatalf("expected a voice state update, got %+v", cmds)
+	}
+	payload := UpdateVoiceStatePayload{}
+	if err = json.Unmarshal(cmds[0].Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.ChannelID != 486833611564253186 || !payload.SelfDeaf || !payload.SelfMute {
+		t.Errorf("expected the new flags for the same channel, got %+v", payload)
+	}
+}
+
+func TestVoiceRepository_VoiceConnectRace(t *testing.T) {
+	c := New(Config{BotToken: "testing", DisableCache: true})
+	guildID := Snowflake(486833611564253184)
+
+	c.voiceRepository.Lock()
+	c.voiceRepository.connecting[guildID] = true
+	c.voiceRepository.Unlock()
+
+	if _, err := c.VoiceConnect(guildID, 486833611564253186); err == nil {
+		t.Error("expected a second connection attempt to fail")
+	}
+}
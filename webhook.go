@@ -293,8 +293,11 @@ type ExecuteWebhookParams struct {
 	Username  string      `json:"username"`
 	AvatarURL string      `json:"avatar_url"`
 	TTS       bool        `json:"tts"`
-	File      interface{} `json:"file"`
+	File      interface{} `json:"file"` // Deprecated: use Files
 	Embeds    []*Embed    `json:"embeds"`
+
+	// Files are streamed as a multipart body, see CreateMessageFileParams
+	Files []CreateMessageFileParams `json:"-"`
 }
 
 type execWebhookParams struct {
@@ -317,19 +320,35 @@ var _ URLQueryStringer = (*execWebhookParams)(nil)
 //                          rich regardless of if you try to set it), provider, video, and any height, width,
 //                          or proxy_url values for images.
 func (c *Client) ExecuteWebhook(ctx context.Context, params *ExecuteWebhookParams, wait bool, URLSuffix string, flags ...Flag) (err error) {
+	_, err = c.executeWebhook(ctx, params, wait, URLSuffix, flags)
+	return err
+}
+
+// executeWebhook returns the created message when wait is true, unless it is a Slack or GitHub compatible
+// webhook as they do not respond with a message object.
+func (c *Client) executeWebhook(ctx context.Context, params *ExecuteWebhookParams, wait bool, URLSuffix string, flags []Flag) (msg *Message, err error) {
 	if params == nil {
-		return errors.New("params can not be nil")
+		return nil, errors.New("params can not be nil")
 	}
 
 	if params.WebhookID.IsZero() {
-		return errors.New("webhook id is required")
+		return nil, errors.New("webhook id is required")
 	}
 	if params.Token == "" {
-		return errors.New("webhook token is required")
+		return nil, errors.New("webhook token is required")
 	}
 
+	var body interface{} = params
 	var contentType string
-	if params.File == nil {
+	if len(params.Files) > 0 {
+		uploadLimit := UploadLimitDefault
+		if exceedsUploadLimit(params.Files, uploadLimit) {
+			uploadLimit = c.webhookUploadLimit(ctx, params.WebhookID, params.Token)
+		}
+		if body, contentType, err = newMultipartBody(params, params.Files, uploadLimit); err != nil {
+			return nil, err
+		}
+	} else if params.File == nil {
 		contentType = httd.ContentTypeJSON
 	} else {
 		contentType = "multipart/form-data"
@@ -340,13 +359,38 @@ func (c *Client) ExecuteWebhook(ctx context.Context, params *ExecuteWebhookParam
 		Method:      httd.MethodPost,
 		Ctx:         ctx,
 		Endpoint:    endpoint.WebhookToken(params.WebhookID, params.Token) + URLSuffix + urlparams.URLQueryString(),
-		Body:        params,
+		Body:        body,
 		ContentType: contentType,
 	}, flags)
-	r.expectsStatusCode = http.StatusNoContent // TODO: verify
 
-	_, err = r.Execute()
-	return err
+	switch {
+	case URLSuffix != "":
+		_, _, err = r.doRequest()
+		return nil, err
+	case wait:
+		r.pool = c.pool.message
+		r.factory = func() interface{} {
+			return &Message{}
+		}
+		return getMessage(r.Execute)
+	default:
+		r.expectsStatusCode = http.StatusNoContent
+		_, err = r.Execute()
+		return nil, err
+	}
+}
+
+// webhookUploadLimit returns the upload limit of the guild the webhook belongs to. Without a bot token
+// the guild can not be looked up, in which case there is no limit and Discord has the final say.
+func (c *Client) webhookUploadLimit(ctx context.Context, id Snowflake, token string) int64 {
+	if c.config.webhookOnly {
+		return 0
+	}
+	webhook, err := c.GetWebhookWithToken(ctx, id, token)
+	if err != nil || webhook.GuildID.IsZero() {
+		return UploadLimitDefault
+	}
+	return c.guildUploadLimit(ctx, webhook.GuildID)
 }
 
 // ExecuteSlackWebhook [REST] Trigger a webhook in Discord from the Slack app.
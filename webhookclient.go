@@ -0,0 +1,233 @@
+package disgord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+
+	"golang.org/x/net/proxy"
+
+	"github.com/andersfylling/disgord/internal/endpoint"
+	"github.com/andersfylling/disgord/internal/httd"
+)
+
+var regexpWebhookURL = regexp.MustCompile(`/webhooks/([0-9]+)/([^/?#]+)`)
+
+// WebhookConfig is the configuration of a WebhookClient. Every field is optional.
+type WebhookConfig struct {
+	HTTPClient *http.Client
+	Proxy      proxy.Dialer
+
+	CancelRequestWhenRateLimited bool
+
+	// your project name, name of bot, or application
+	ProjectName string
+
+	Logger Logger
+
+	// RESTBucketManager stores the rate limit buckets, see Config.RESTBucketManager
+	RESTBucketManager httd.RESTBucketManager
+
+	// RESTBaseURL see Config.RESTBaseURL
+	RESTBaseURL string
+
+	// APIVersion see Config.APIVersion
+	APIVersion int
+}
+
+// WebhookClient executes and manages a single webhook. It authenticates through the webhook token,
+// so no bot token nor gateway connection is needed. Requests are rate limited like the Client.
+//
+//	hook, err := disgord.NewWebhookClientFromURL("https://discord.com/api/webhooks/1234/token", disgord.WebhookConfig{})
+//	msg, err := hook.Execute(ctx, &disgord.ExecuteWebhookParams{Content: "hello"}, true)
+type WebhookClient struct {
+	id    Snowflake
+	token string
+	c     *Client
+}
+
+// NewWebhookClient creates a client for the webhook with the given id and token.
+func NewWebhookClient(id Snowflake, token string, conf WebhookConfig) (*WebhookClient, error) {
+	if id.IsZero() {
+		return nil, errors.New("webhook id is required")
+	}
+	if token == "" {
+		return nil, errors.New("webhook token is required")
+	}
+
+	c, err := createClient(&Config{
+		webhookOnly:                  true,
+		HTTPClient:                   conf.HTTPClient,
+		Proxy:                        conf.Proxy,
+		CancelRequestWhenRateLimited: conf.CancelRequestWhenRateLimited,
+		ProjectName:                  conf.ProjectName,
+		Logger:                       conf.Logger,
+		RESTBucketManager:            conf.RESTBucketManager,
+		RESTBaseURL:                  conf.RESTBaseURL,
+		APIVersion:                   conf.APIVersion,
+		DisableCache:                 true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookClient{
+		id:    id,
+		token: token,
+		c:     c,
+	}, nil
+}
+
+// NewWebhookClientFromURL creates a client from a webhook url as shown in the Discord app.
+// eg. https://discord.com/api/webhooks/{webhook.id}/{webhook.token}
+func NewWebhookClientFromURL(url string, conf WebhookConfig) (*WebhookClient, error) {
+	matches := regexpWebhookURL.FindStringSubmatch(url)
+	if len(matches) != 3 {
+		return nil, errors.New("invalid webhook url, expected the format .../webhooks/{webhook.id}/{webhook.token}")
+	}
+
+	id, err := GetSnowflake(matches[1])
+	if err != nil {
+		return nil, err
+	}
+	return NewWebhookClient(id, matches[2], conf)
+}
+
+// ID of the webhook
+func (w *WebhookClient) ID() Snowflake {
+	return w.id
+}
+
+// Token of the webhook
+func (w *WebhookClient) Token() string {
+	return w.token
+}
+
+// Get returns the webhook object. See Client.GetWebhookWithToken.
+func (w *WebhookClient) Get(ctx context.Context, flags ...Flag) (*Webhook, error) {
+	return w.c.GetWebhookWithToken(ctx, w.id, w.token, flags...)
+}
+
+// Update modifies the webhook. See Client.UpdateWebhookWithToken.
+func (w *WebhookClient) Update(ctx context.Context, flags ...Flag) *updateWebhookBuilder {
+	return w.c.UpdateWebhookWithToken(ctx, w.id, w.token, flags...)
+}
+
+// Delete deletes the webhook permanently. See Client.DeleteWebhookWithToken.
+func (w *WebhookClient) Delete(ctx context.Context, flags ...Flag) error {
+	return w.c.DeleteWebhookWithToken(ctx, w.id, w.token, flags...)
+}
+
+// Execute triggers the webhook. The webhook id and token of the params are ignored. When wait is true, Discord
+// confirms the message was saved and the message is returned, otherwise the message is nil.
+// Files are streamed, see ExecuteWebhookParams.Files.
+func (w *WebhookClient) Execute(ctx context.Context, params *ExecuteWebhookParams, wait bool, flags ...Flag) (*Message, error) {
+	if params == nil {
+		return nil, errors.New("params can not be nil")
+	}
+	params.WebhookID = w.id
+	params.Token = w.token
+	return w.c.executeWebhook(ctx, params, wait, "", flags)
+}
+
+// ExecuteSlack triggers the webhook with a Slack compatible payload.
+//  Method                  POST
+//  Endpoint                /webhooks/{webhook.id}/{webhook.token}/slack
+//  Discord documentation   https://discordapp.com/developers/docs/resources/webhook#execute-slackcompatible-webhook
+//  Reviewed                2026-10-18
+//  Comment                 Refer to Slack's documentation for the payload format. Discord does not support
+//                          Slack's channel, icon_emoji, mrkdwn, or mrkdwn_in properties.
+func (w *WebhookClient) ExecuteSlack(ctx context.Context, payload interface{}, flags ...Flag) error {
+	return w.executeCompatible(ctx, endpoint.Slack(), payload, nil, flags)
+}
+
+// ExecuteGitHub triggers the webhook with a GitHub event payload.
+//  Method                  POST
+//  Endpoint                /webhooks/{webhook.id}/{webhook.token}/github
+//  Discord documentation   https://discordapp.com/developers/docs/resources/webhook#execute-githubcompatible-webhook
+//  Reviewed                2026-10-18
+//  Comment                 The event is the value of the X-GitHub-Event header, eg. "push".
+func (w *WebhookClient) ExecuteGitHub(ctx context.Context, event string, payload interface{}, flags ...Flag) error {
+	if event == "" {
+		return errors.New("GitHub event name is required")
+	}
+	header := http.Header{}
+	header.Set("X-GitHub-Event", event)
+	return w.executeCompatible(ctx, endpoint.GitHub(), payload, header, flags)
+}
+
+func (w *WebhookClient) executeCompatible(ctx context.Context, suffix string, payload interface{}, header http.Header, flags []Flag) error {
+	if payload == nil {
+		return errors.New("payload can not be nil")
+	}
+
+	r := w.c.newRESTRequest(&httd.Request{
+		Method:      httd.MethodPost,
+		Ctx:         ctx,
+		Endpoint:    endpoint.WebhookToken(w.id, w.token) + suffix,
+		Body:        payload,
+		ContentType: httd.ContentTypeJSON,
+		Header:      header,
+	}, flags)
+
+	_, _, err := r.doRequest()
+	return err
+}
+
+// UpdateWebhookMessageParams JSON params for WebhookClient.UpdateMessage
+type UpdateWebhookMessageParams struct {
+	Content string   `json:"content,omitempty"`
+	Embeds  []*Embed `json:"embeds,omitempty"`
+}
+
+// UpdateMessage edits a message previously sent by this webhook.
+//  Method                  PATCH
+//  Endpoint                /webhooks/{webhook.id}/{webhook.token}/messages/{message.id}
+//  Discord documentation   https://discordapp.com/developers/docs/resources/webhook#edit-webhook-message
+//  Reviewed                2026-10-18
+//  Comment                 -
+func (w *WebhookClient) UpdateMessage(ctx context.Context, messageID Snowflake, params *UpdateWebhookMessageParams, flags ...Flag) (*Message, error) {
+	if messageID.IsZero() {
+		return nil, errors.New("messageID must be set")
+	}
+	if params == nil {
+		return nil, errors.New("params can not be nil")
+	}
+
+	r := w.c.newRESTRequest(&httd.Request{
+		Method:      httd.MethodPatch,
+		Ctx:         ctx,
+		Endpoint:    endpoint.WebhookMessage(w.id, w.token, messageID),
+		Body:        params,
+		ContentType: httd.ContentTypeJSON,
+	}, flags)
+	r.pool = w.c.pool.message
+	r.factory = func() interface{} {
+		return &Message{}
+	}
+
+	return getMessage(r.Execute)
+}
+
+// DeleteMessage deletes a message previously sent by this webhook.
+//  Method                  DELETE
+//  Endpoint                /webhooks/{webhook.id}/{webhook.token}/messages/{message.id}
+//  Discord documentation   https://discordapp.com/developers/docs/resources/webhook#delete-webhook-message
+//  Reviewed                2026-10-18
+//  Comment                 -
+func (w *WebhookClient) DeleteMessage(ctx context.Context, messageID Snowflake, flags ...Flag) error {
+	if messageID.IsZero() {
+		return errors.New("messageID must be set")
+	}
+
+	r := w.c.newRESTRequest(&httd.Request{
+		Method:   httd.MethodDelete,
+		Ctx:      ctx,
+		Endpoint: endpoint.WebhookMessage(w.id, w.token, messageID),
+	}, flags)
+	r.expectsStatusCode = http.StatusNoContent
+
+	_, err := r.Execute()
+	return err
+}
@@ -0,0 +1,94 @@
+package disgord
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/andersfylling/disgord/disgordtest"
+)
+
+func TestNewWebhookClientFromURL(t *testing.T) {
+	urls := []string{
+		"https://discord.com/api/webhooks/123456789/abc-DEF_123",
+		"https://canary.discordapp.com/api/v6/webhooks/123456789/abc-DEF_123?wait=true",
+	}
+	for _, url := range urls {
+		hook, err := NewWebhookClientFromURL(url, WebhookConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hook.ID() != 123456789 || hook.Token() != "abc-DEF_123" {
+			t.Errorf("unexpected id %s and token %s from %s", hook.ID(), hook.Token(), url)
+		}
+	}
+
+	if _, err := NewWebhookClientFromURL("https://discord.com/api/channels/123", WebhookConfig{}); err == nil {
+		t.Error("expected an error for a url without a webhook")
+	}
+}
+
+func TestWebhookClient(t *testing.T) {
+	srv := disgordtest.NewRESTServer()
+	defer srv.Close()
+
+	channel := srv.AddChannel(disgordtest.Channel{Name: "general"})
+	webhook := srv.AddWebhook(disgordtest.Webhook{ChannelID: channel.ID, Name: "hook"})
+
+	hook, err := NewWebhookClient(webhook.ID, webhook.Token, WebhookConfig{RESTBaseURL: srv.URL()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	msg, err := hook.Execute(ctx, &ExecuteWebhookParams{
+		Content: "hello",
+		Files: []CreateMessageFileParams{
+			{Reader: strings.NewReader("data"), FileName: "file.txt"},
+		},
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg == nil || msg.Content != "hello" || msg.WebhookID != webhook.ID || len(msg.Attachments) != 1 {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+	if msg, err := hook.Execute(ctx, &ExecuteWebhookParams{Content: "no wait"}, false); err != nil || msg != nil {
+		t.Errorf("expected no message and no error when not waiting. Got %+v, %v", msg, err)
+	}
+
+	updated, err := hook.UpdateMessage(ctx, msg.ID, &UpdateWebhookMessageParams{Content: "edited"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Content != "edited" {
+		t.Errorf("expected content to be edited. Got %s", updated.Content)
+	}
+	if err = hook.DeleteMessage(ctx, msg.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = hook.ExecuteSlack(ctx, map[string]string{"text": "from slack"}); err != nil {
+		t.Fatal(err)
+	}
+	msgs := srv.Messages(channel.ID)
+	if len(msgs) != 2 || msgs[1].Content != "from slack" {
+		t.Errorf("expected the slack message to be stored. Got %+v", msgs)
+	}
+
+	for _, req := range srv.Requests() {
+		if req.Header.Get("Authorization") != "" {
+			t.Errorf("expected no authorization header. Got %s", req.Header.Get("Authorization"))
+		}
+	}
+
+	if _, err = hook.Get(ctx); err != nil {
+		t.Error(err)
+	}
+	if err = hook.Delete(ctx); err != nil {
+		t.Error(err)
+	}
+	if _, ok := srv.Webhook(webhook.ID); ok {
+		t.Error("expected the webhook to be deleted")
+	}
+}